@@ -8,9 +8,12 @@ import (
 	"awesomeProject/internal/bookmark"
 	"awesomeProject/internal/form"
 	"awesomeProject/internal/jwt"
+	"awesomeProject/internal/response"
+	"awesomeProject/internal/share"
 	"awesomeProject/internal/user"
 	"context"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -20,6 +23,25 @@ import (
 
 const baseURL = "http://localhost:8080"
 
+// defaultTrashRetention is how long deleted forms stay restorable unless
+// FORM_TRASH_RETENTION overrides it.
+const defaultTrashRetention = 30 * 24 * time.Hour
+
+// defaultJWTKeysFile is the key file made by cmd/jwtkeys, unless
+// JWT_KEYS_FILE points elsewhere.
+const defaultJWTKeysFile = "jwt_keys.json"
+
+// shareLinkRateLimit is how many requests each share link serves per minute,
+// views and submissions together.
+const shareLinkRateLimit = 60
+
+// accessTokenExpiration is how long access tokens are valid.
+const accessTokenExpiration = 15 * time.Minute
+
+// revocationSyncInterval is how often each server reloads the revoked access
+// tokens, which is how long a logout on another server may take to apply.
+const revocationSyncInterval = 10 * time.Second
+
 func main() {
 	_ = godotenv.Load()
 
@@ -55,35 +77,116 @@ func main() {
 	userQuerier := user.New(dbPool)
 	jwtQuerier := jwt.New(dbPool)
 	bookmarkQuerier := bookmark.New(dbPool)
+	responseQuerier := response.New(dbPool)
+	shareQuerier := share.New(dbPool)
 
-	formService := form.NewService(logger, formQuerier)
+	formService := form.NewService(logger, formQuerier, form.NewTransactor(dbPool))
 	userService := user.NewService(logger, userQuerier)
+	jwtKeysFile := defaultJWTKeysFile
+	if value := os.Getenv("JWT_KEYS_FILE"); value != "" {
+		jwtKeysFile = value
+	}
+	jwtKeys, err := jwt.LoadKeySet(jwtKeysFile)
+	if err != nil {
+		logger.Fatal("Failed to load JWT keys, generate them with `go run ./cmd/jwtkeys generate`", zap.String("path", jwtKeysFile), zap.Error(err))
+	}
 	// [MODIFIED] Add dbPool argument, as required by the new service definition
-	jwtService := jwt.NewService(logger, 15*time.Minute, jwtQuerier)
+	jwtService := jwt.NewService(logger, accessTokenExpiration, jwtQuerier, jwtKeys)
+	revocations := jwt.NewRevocationList(logger, jwtQuerier, accessTokenExpiration)
+	err = revocations.Load(context.Background())
+	if err != nil {
+		logger.Fatal("Failed to load revoked access tokens", zap.Error(err))
+	}
 	bookmarkService := bookmark.NewService(logger, bookmarkQuerier)
+	responseService := response.NewService(logger, responseQuerier, formService)
+	shareService := share.NewService(logger, shareQuerier, formService, responseService)
+
+	trashRetention := defaultTrashRetention
+	if value := os.Getenv("FORM_TRASH_RETENTION"); value != "" {
+		trashRetention, err = time.ParseDuration(value)
+		if err != nil || trashRetention <= 0 {
+			logger.Fatal("Invalid FORM_TRASH_RETENTION", zap.String("value", value), zap.Error(err))
+		}
+	}
+	go formService.RunPurge(context.Background(), trashRetention, time.Hour)
+	go jwtService.WatchKeys(context.Background(), jwtKeysFile, time.Minute)
+	go revocations.Sync(context.Background(), revocationSyncInterval)
 
 	formHandler := form.NewHandler(logger, validator, formService)
 	userHandler := user.NewHandler(logger, validator, userService)
-	authHandler := auth.NewHandler(logger, baseURL, jwtService, userService, jwtService)
-	jwtHandler := jwt.NewHandler(logger, validator, jwtService, userService)
+	authHandler := auth.NewHandler(logger, baseURL, jwtService, userService, jwtService, formService)
+	jwtHandler := jwt.NewHandler(logger, validator, jwtService, userService, revocations)
 	bookmarkHandler := bookmark.NewHandler(logger, validator, bookmarkService)
+	responseHandler := response.NewHandler(logger, validator, responseService)
+	shareHandler := share.NewHandler(logger, validator, baseURL, shareService)
 
 	basicMiddleware := handlerutil.NewMiddleware(logger, true)
-	jwtMiddleware := jwt.NewMiddleware(logger, jwtService)
+	jwtMiddleware := jwt.NewMiddleware(logger, jwtService, revocations)
+	shareMiddleware := share.NewMiddleware(logger, jwtService, revocations, share.NewRateLimiter(shareLinkRateLimit, time.Minute))
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("POST /api/forms", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Create)))
 	mux.HandleFunc("GET /api/forms", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.List)))
+	mux.HandleFunc("GET /api/forms/trash", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Trash)))
+	mux.HandleFunc("POST /api/forms/{id}/restore", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Restore)))
+	mux.HandleFunc("POST /api/forms/{id}/duplicate", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Duplicate)))
+	mux.HandleFunc("GET /api/forms/search", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Search)))
+	mux.HandleFunc("GET /api/forms/{id}", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Get)))
 	mux.HandleFunc("PUT /api/forms", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Update)))
 	mux.HandleFunc("DELETE /api/forms", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Delete)))
+	mux.HandleFunc("POST /api/forms/{id}/questions", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.CreateQuestion)))
+	mux.HandleFunc("GET /api/forms/{id}/questions", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.ListQuestions)))
+	mux.HandleFunc("PUT /api/forms/{id}/questions/{questionId}", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.UpdateQuestion)))
+	mux.HandleFunc("DELETE /api/forms/{id}/questions/{questionId}", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.DeleteQuestion)))
+	mux.HandleFunc("POST /api/forms/{id}/sections", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.CreateSection)))
+	mux.HandleFunc("GET /api/forms/{id}/sections", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.ListSections)))
+	mux.HandleFunc("PUT /api/forms/{id}/sections/{sectionId}", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.UpdateSection)))
+	mux.HandleFunc("DELETE /api/forms/{id}/sections/{sectionId}", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.DeleteSection)))
+	mux.HandleFunc("POST /api/forms/{id}/validate", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.ValidateAnswers)))
+	mux.HandleFunc("POST /api/forms/{id}/publish", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Publish)))
+	mux.HandleFunc("POST /api/forms/{id}/close", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Close)))
+	mux.HandleFunc("POST /api/forms/{id}/reopen", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Reopen)))
+	mux.HandleFunc("POST /api/forms/{id}/archive", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Archive)))
+	mux.HandleFunc("GET /api/forms/{id}/transitions", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.ListTransitions)))
+	mux.HandleFunc("GET /api/forms/{id}/revisions", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.ListRevisions)))
+	mux.HandleFunc("GET /api/forms/{id}/revisions/diff", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.DiffRevisions)))
+	mux.HandleFunc("POST /api/forms/{id}/revisions/{number}/rollback", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Rollback)))
+	mux.HandleFunc("PUT /api/forms/{id}/quiz", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.SetQuiz)))
+	mux.HandleFunc("PUT /api/forms/{id}/template", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.SetTemplate)))
+	mux.HandleFunc("DELETE /api/forms/{id}/template", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.RemoveTemplate)))
+	mux.HandleFunc("GET /api/templates", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Templates)))
+	mux.HandleFunc("GET /api/forms/{id}/definition", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Definition)))
+	mux.HandleFunc("POST /api/forms/import", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.Import)))
+	mux.HandleFunc("POST /api/forms/{id}/collaborators", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.InviteCollaborator)))
+	mux.HandleFunc("GET /api/forms/{id}/collaborators", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.ListCollaborators)))
+	mux.HandleFunc("PUT /api/forms/{id}/collaborators/{email}", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.UpdateCollaborator)))
+	mux.HandleFunc("DELETE /api/forms/{id}/collaborators/{email}", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(formHandler.RemoveCollaborator)))
+	mux.HandleFunc("POST /api/forms/{id}/responses", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(responseHandler.Submit)))
+	mux.HandleFunc("GET /api/forms/{id}/responses", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(responseHandler.List)))
+	mux.HandleFunc("GET /api/forms/{id}/responses/export", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(responseHandler.Export)))
+	mux.HandleFunc("GET /api/forms/{id}/responses/{responseId}", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(responseHandler.Get)))
+	mux.HandleFunc("GET /api/forms/{id}/responses/{responseId}/score", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(responseHandler.Score)))
+	mux.HandleFunc("PUT /api/forms/{id}/responses/{responseId}/answers/{questionId}/grade", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(responseHandler.Grade)))
+	mux.HandleFunc("GET /api/forms/{id}/gradebook", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(responseHandler.Gradebook)))
+	mux.HandleFunc("GET /api/forms/{id}/summary", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(responseHandler.Summary)))
+	mux.HandleFunc("POST /api/forms/{id}/share-links", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(shareHandler.Create)))
+	mux.HandleFunc("GET /api/forms/{id}/share-links", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(shareHandler.List)))
+	mux.HandleFunc("DELETE /api/forms/{id}/share-links/{linkId}", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(shareHandler.Delete)))
 	mux.HandleFunc("POST /api/users", basicMiddleware.RecoverMiddleware(userHandler.Create))
 
+	// share links are public, they sign in users when a token is sent but do not require one
+	mux.HandleFunc("GET /f/{slug}", basicMiddleware.RecoverMiddleware(shareMiddleware.HandlerFunc(shareHandler.View)))
+	mux.HandleFunc("POST /f/{slug}/responses", basicMiddleware.RecoverMiddleware(shareMiddleware.HandlerFunc(shareHandler.Submit)))
+
 	mux.HandleFunc("GET /api/oauth/{provider}", basicMiddleware.RecoverMiddleware(authHandler.Login))
 	mux.HandleFunc("GET /api/oauth/{provider}/callback", basicMiddleware.RecoverMiddleware(authHandler.Callback))
 	mux.HandleFunc("GET /api/oauth/debug/token", basicMiddleware.RecoverMiddleware(authHandler.DebugToken))
 
 	// [ADDED] Add the new refresh token endpoint
 	mux.HandleFunc("POST /api/auth/refresh", basicMiddleware.RecoverMiddleware(jwtHandler.Refresh))
+	mux.HandleFunc("POST /api/auth/logout", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(jwtHandler.Logout)))
+	mux.HandleFunc("POST /api/auth/logout-all", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(jwtHandler.LogoutAll)))
+	mux.HandleFunc("GET /.well-known/jwks.json", basicMiddleware.RecoverMiddleware(jwtHandler.JWKS))
 
 	mux.HandleFunc("GET /api/bookmarks", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(bookmarkHandler.Toggle)))
 	//mux.HandleFunc("POST /api/bookmarks", basicMiddleware.RecoverMiddleware(jwtMiddleware.HandlerFunc(bookmarkHandler.UserBookmarksCount)))
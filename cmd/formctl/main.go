@@ -0,0 +1,141 @@
+// Command formctl keeps the forms on a server in sync with a directory of
+// form definition files.
+//
+//	formctl [-server URL] [-token TOKEN] plan [-exit-code] DIR
+//	formctl [-server URL] [-token TOKEN] apply [-yes] DIR
+//
+// plan shows which forms would be created, updated or deleted and how the
+// forms on the server differ from the files. apply shows the same plan and
+// makes the changes after confirmation. Which form each file manages is
+// recorded in formctl.state.json in DIR, commit it together with the files.
+//
+// The token is a JWT access token of the user the forms belong to. It
+// defaults to the FORMCTL_TOKEN environment variable, the server to
+// FORMCTL_SERVER or http://localhost:8080.
+package main
+
+import (
+	"awesomeProject/internal/formsync"
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// exitChanges is the exit code of plan -exit-code when there are changes.
+const exitChanges = 2
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("formctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	server := flags.String("server", envOr("FORMCTL_SERVER", defaultServer), "base URL of the server")
+	token := flags.String("token", os.Getenv("FORMCTL_TOKEN"), "JWT access token")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: formctl [-server URL] [-token TOKEN] plan [-exit-code] DIR")
+		fmt.Fprintln(stderr, "       formctl [-server URL] [-token TOKEN] apply [-yes] DIR")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 1
+	}
+
+	command := flag.NewFlagSet(flags.Arg(0), flag.ContinueOnError)
+	command.SetOutput(stderr)
+	exitCode := command.Bool("exit-code", false, "exit with 2 when there are changes")
+	yes := command.Bool("yes", false, "apply without asking for confirmation")
+	if err := command.Parse(flags.Args()[1:]); err != nil {
+		return 1
+	}
+	if command.NArg() != 1 || (flags.Arg(0) != "plan" && flags.Arg(0) != "apply") {
+		flags.Usage()
+		return 1
+	}
+	if *token == "" {
+		fmt.Fprintln(stderr, "formctl: no token, use -token or FORMCTL_TOKEN")
+		return 1
+	}
+	dir := command.Arg(0)
+
+	client := formsync.NewClient(*server, *token, &http.Client{Timeout: 30 * time.Second})
+	plan, err := makePlan(ctx, client, dir)
+	if err != nil {
+		fmt.Fprintf(stderr, "formctl: %v\n", err)
+		return 1
+	}
+	if err := plan.Write(stdout); err != nil {
+		fmt.Fprintf(stderr, "formctl: %v\n", err)
+		return 1
+	}
+
+	if flags.Arg(0) == "plan" {
+		if *exitCode && plan.HasChanges() {
+			return exitChanges
+		}
+		return 0
+	}
+
+	if !plan.HasChanges() {
+		return 0
+	}
+	if !*yes && !confirm(stdin, stdout) {
+		fmt.Fprintln(stdout, "Apply cancelled.")
+		return 1
+	}
+	err = plan.Apply(ctx, client, dir, func(change formsync.Change) {
+		fmt.Fprintf(stdout, "%s: %sd form %s\n", change.Path, change.Action, change.FormID)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "formctl: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func makePlan(ctx context.Context, client *formsync.Client, dir string) (*formsync.Plan, error) {
+	forms, err := formsync.LoadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	state, err := formsync.LoadState(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", formsync.StateFile, err)
+	}
+	return formsync.MakePlan(ctx, client, forms, state)
+}
+
+// confirm asks like terraform does, only "yes" applies.
+func confirm(stdin io.Reader, stdout io.Writer) bool {
+	fmt.Fprint(stdout, "\nApply these changes? Only 'yes' will be accepted: ")
+	answer, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false
+	}
+	return strings.TrimSpace(answer) == "yes"
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,168 @@
+// Command jwtkeys manages the keys the backend signs JWT access tokens with.
+//
+//	jwtkeys [-file PATH] list
+//	jwtkeys [-file PATH] generate [-alg ALG] [-activate-in DURATION]
+//	jwtkeys [-file PATH] retire KID
+//
+// generate adds a key, creating the file if needed. New tokens are signed
+// with the newest active key while tokens signed with older keys stay
+// valid, so keys can be rotated without signing anyone out. Keys are RS256
+// by default; EdDSA keys are smaller, and HS256 keys can only be verified by
+// the backend itself since they are not published at /.well-known/jwks.json.
+//
+// New keys only sign tokens after -activate-in, six minutes by default.
+// Servers reload the file every minute and services verifying tokens against
+// the JWKS cache it for five minutes, so by then all of them know the key.
+// The first key of a file signs right away, there are no tokens yet that
+// anyone could fail to verify. Retire the old key once the tokens signed with
+// it expired.
+//
+// The file defaults to the JWT_KEYS_FILE environment variable or
+// jwt_keys.json, like the backend.
+package main
+
+import (
+	"awesomeProject/internal/jwt"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	defaultFile = "jwt_keys.json"
+	// defaultActivateIn outlasts the file reload of the servers and the
+	// JWKS cache of other services.
+	defaultActivateIn = 6 * time.Minute
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("jwtkeys", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	file := flags.String("file", envOr("JWT_KEYS_FILE", defaultFile), "key file")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: jwtkeys [-file PATH] list")
+		fmt.Fprintln(stderr, "       jwtkeys [-file PATH] generate [-alg ALG] [-activate-in DURATION]")
+		fmt.Fprintln(stderr, "       jwtkeys [-file PATH] retire KID")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 1
+	}
+
+	command := flag.NewFlagSet(flags.Arg(0), flag.ContinueOnError)
+	command.SetOutput(stderr)
+	activateIn := command.Duration("activate-in", defaultActivateIn, "wait this long before the new key signs tokens")
+	alg := command.String("alg", jwt.AlgRS256, "algorithm of the new key: "+strings.Join(jwt.Algorithms, ", "))
+	if err := command.Parse(flags.Args()[1:]); err != nil {
+		return 1
+	}
+
+	var err error
+	switch {
+	case flags.Arg(0) == "list" && command.NArg() == 0:
+		err = list(*file, stdout)
+	case flags.Arg(0) == "generate" && command.NArg() == 0:
+		err = generate(*file, *alg, *activateIn, stdout)
+	case flags.Arg(0) == "retire" && command.NArg() == 1:
+		err = retire(*file, command.Arg(0), stdout)
+	default:
+		flags.Usage()
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "jwtkeys: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func list(file string, stdout io.Writer) error {
+	keys, err := jwt.LoadKeySet(file)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	signing, _ := keys.Signing(now)
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KID\tALG\tCREATED\tACTIVE\tSTATE")
+	for _, key := range keys.Keys {
+		state := "accepted"
+		switch {
+		case key.RetiredAt != nil:
+			state = "retired " + key.RetiredAt.Format(time.RFC3339)
+		case key.ID == signing.ID:
+			state = "signing"
+		case key.ActiveAt.After(now):
+			state = "pending"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", key.ID, key.Algorithm, key.CreatedAt.Format(time.RFC3339), key.ActiveAt.Format(time.RFC3339), state)
+	}
+	return w.Flush()
+}
+
+func generate(file, alg string, activateIn time.Duration, stdout io.Writer) error {
+	keys, err := jwt.LoadKeySet(file)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	now := time.Now().Truncate(time.Second)
+	if len(keys.Keys) == 0 {
+		activateIn = 0
+	}
+	key, err := keys.Generate(alg, now, now.Add(activateIn))
+	if err != nil {
+		return err
+	}
+	err = keys.Save(file)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Generated %s key %s, signing from %s.\n", key.Algorithm, key.ID, key.ActiveAt.Format(time.RFC3339))
+	return nil
+}
+
+func retire(file, kid string, stdout io.Writer) error {
+	keys, err := jwt.LoadKeySet(file)
+	if err != nil {
+		return err
+	}
+
+	err = keys.Retire(kid, time.Now().Truncate(time.Second))
+	if errors.Is(err, jwt.ErrLastKey) {
+		return fmt.Errorf("%w, retire it once a newer key is active", err)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", kid, err)
+	}
+	err = keys.Save(file)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Retired key %s, tokens signed with it are no longer accepted.\n", kid)
+	return nil
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -17,7 +17,7 @@ import (
 )
 
 type jwtService interface {
-	New(ctx context.Context, userID uuid.UUID, email string) (string, error)
+	New(ctx context.Context, userID uuid.UUID, email string, familyID uuid.UUID) (string, error)
 }
 type userService interface {
 	Create(ctx context.Context, email string) (user.User, error)
@@ -33,7 +33,11 @@ type OAuthProvider interface {
 
 // [ADDED] Refresh Token 服務的接口
 type refreshTokenService interface {
-	Create(ctx context.Context, userID uuid.UUID, time pgtype.Timestamptz) (jwt.Jwt, error)
+	Create(ctx context.Context, userID uuid.UUID, time pgtype.Timestamptz) (jwt.RefreshToken, error)
+}
+
+type invitationService interface {
+	AttachInvitations(ctx context.Context, userID uuid.UUID, email string) error
 }
 
 type Handler struct {
@@ -43,10 +47,11 @@ type Handler struct {
 	userService userService
 	provider    map[string]OAuthProvider
 	rtService   refreshTokenService // [ADDED]
+	invitations invitationService
 }
 
 // [MODIFIED] 注入 rtService
-func NewHandler(logger *zap.Logger, baseURL string, jwtService jwtService, userService userService, rtService refreshTokenService) *Handler {
+func NewHandler(logger *zap.Logger, baseURL string, jwtService jwtService, userService userService, rtService refreshTokenService, invitations invitationService) *Handler {
 	clientID := os.Getenv("GOOGLE_CLIENT_ID")
 	clientSecret := os.Getenv("GOOGLE_CLIENT_SECRET")
 
@@ -56,6 +61,7 @@ func NewHandler(logger *zap.Logger, baseURL string, jwtService jwtService, userS
 		jwtService:  jwtService,
 		userService: userService,
 		rtService:   rtService, // [ADDED]
+		invitations: invitations,
 		provider: map[string]OAuthProvider{
 			"google": oauthprovider.NewGoogleConfig(
 				clientID,
@@ -148,13 +154,10 @@ func (h *Handler) Callback(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// 創建 Access Token
-	jwtToken, err := h.jwtService.New(r.Context(), dbUser.ID, dbUser.Email)
+	// 將以此 email 發出的表單邀請綁定到使用者，失敗不影響登入
+	err = h.invitations.AttachInvitations(r.Context(), dbUser.ID, dbUser.Email)
 	if err != nil {
-		redirectTo = fmt.Sprintf("%s?error=%s", redirectTo, err)
-		h.logger.Error("Failed to create JWT token", zap.Error(err))
-		http.Redirect(w, r, redirectTo, http.StatusTemporaryRedirect)
-		return
+		h.logger.Error("Failed to attach form invitations", zap.Error(err), zap.String("email", dbUser.Email))
 	}
 
 	// 檢查用戶是否存在，若不存在則創建
@@ -186,8 +189,19 @@ func (h *Handler) Callback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 創建 Access Token
+	// the access token belongs to the session of the refresh token, so
+	// logging out ends both
+	jwtToken, err := h.jwtService.New(r.Context(), dbUser.ID, dbUser.Email, newRefreshToken.FamilyID)
+	if err != nil {
+		redirectTo = fmt.Sprintf("%s?error=%s", redirectTo, err)
+		h.logger.Error("Failed to create JWT token", zap.Error(err))
+		http.Redirect(w, r, redirectTo, http.StatusTemporaryRedirect)
+		return
+	}
+
 	// [MODIFIED] 在重定向 URL 中同時包含 access_token 和 refresh_token
-	redirectTo = fmt.Sprintf("%s?access_token=%s&refresh_token=%s", redirectTo, jwtToken, newRefreshToken.ID.String())
+	redirectTo = fmt.Sprintf("%s?access_token=%s&refresh_token=%s", redirectTo, jwtToken, newRefreshToken.Token)
 
 	http.Redirect(w, r, redirectTo, http.StatusTemporaryRedirect)
 	h.logger.Info("OAuth2 callback successful", zap.String("user_email", userInfo.Email))
@@ -1,6 +1,7 @@
 package bookmark
 
 import (
+	"awesomeProject/internal/pagination"
 	"context"
 	"encoding/json"
 	"net/http"
@@ -31,6 +32,15 @@ type ToggleResponse struct {
 type GetFormResponse struct {
 	FormID    string    `json:"form_id"`
 	CreatedAt time.Time `json:"createdAt"`
+	// BookmarkedRevision is nil for bookmarks made before revisions were recorded.
+	BookmarkedRevision *int32 `json:"bookmarked_revision,omitempty"`
+	LatestRevision     int32  `json:"latest_revision"`
+	ChangedSince       bool   `json:"changed_since_bookmark"`
+}
+
+type GetFormsResponse struct {
+	Forms      []GetFormResponse `json:"forms"`
+	NextCursor string            `json:"next_cursor,omitempty"`
 }
 
 type ExistResponse struct {
@@ -51,7 +61,7 @@ type FormBookmarksCount struct {
 
 type Store interface {
 	ToggleBookmark(ctx context.Context, userID, formID uuid.UUID) (bool, error)
-	GetFormsByUserID(ctx context.Context, userID uuid.UUID) ([]GetFormsByUserIDRow, error)
+	GetFormsByUserID(ctx context.Context, userID uuid.UUID, page pagination.Params) ([]GetFormsByUserIDRow, string, error)
 	SpecificForm(ctx context.Context, userID, formID uuid.UUID) (bool, error)
 	CountBookmark(ctx context.Context, userID uuid.UUID) (int64, error)
 	FormCount(ctx context.Context, formID uuid.UUID) (int64, error)
@@ -134,19 +144,34 @@ func (h *Handler) GetFormsByUserID(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Validation failed", http.StatusBadRequest)
 		return
 	}
+	page, err := pagination.ParseParams(r.URL.Query())
+	if err != nil {
+		h.logger.Warn("Invalid pagination", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	userID, err := uuid.Parse(req.UserID)
-	forms, err := h.store.GetFormsByUserID(ctx, userID)
+	forms, next, err := h.store.GetFormsByUserID(ctx, userID, page)
 	if err != nil {
 		h.logger.Error("Fail to get forms", zap.Error(err))
 		http.Error(w, "Fail to get forms", http.StatusBadRequest)
 		return
 	}
-	var resp []GetFormResponse
+	resp := GetFormsResponse{
+		Forms:      make([]GetFormResponse, 0, len(forms)),
+		NextCursor: next,
+	}
 	for _, form := range forms {
-		resp = append(resp, GetFormResponse{
-			FormID:    form.FormID.String(),
-			CreatedAt: form.CreatedAt.Time,
-		})
+		item := GetFormResponse{
+			FormID:         form.FormID.String(),
+			CreatedAt:      form.CreatedAt.Time,
+			LatestRevision: form.LatestRevision,
+		}
+		if form.BookmarkedRevision.Valid {
+			item.BookmarkedRevision = &form.BookmarkedRevision.Int32
+			item.ChangedSince = form.BookmarkedRevision.Int32 < form.LatestRevision
+		}
+		resp.Forms = append(resp.Forms, item)
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
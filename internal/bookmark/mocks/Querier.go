@@ -146,9 +146,9 @@ func (_m *Querier) FormCount(ctx context.Context, formID uuid.UUID) (int64, erro
 	return r0, r1
 }
 
-// GetFormsByUserID provides a mock function with given fields: ctx, userID
-func (_m *Querier) GetFormsByUserID(ctx context.Context, userID uuid.UUID) ([]bookmark.GetFormsByUserIDRow, error) {
-	ret := _m.Called(ctx, userID)
+// GetFormsByUserID provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetFormsByUserID(ctx context.Context, arg bookmark.GetFormsByUserIDParams) ([]bookmark.GetFormsByUserIDRow, error) {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetFormsByUserID")
@@ -156,19 +156,19 @@ func (_m *Querier) GetFormsByUserID(ctx context.Context, userID uuid.UUID) ([]bo
 
 	var r0 []bookmark.GetFormsByUserIDRow
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]bookmark.GetFormsByUserIDRow, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, bookmark.GetFormsByUserIDParams) ([]bookmark.GetFormsByUserIDRow, error)); ok {
+		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []bookmark.GetFormsByUserIDRow); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, bookmark.GetFormsByUserIDParams) []bookmark.GetFormsByUserIDRow); ok {
+		r0 = rf(ctx, arg)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]bookmark.GetFormsByUserIDRow)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, bookmark.GetFormsByUserIDParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -4,14 +4,23 @@ SELECT
     f.title,
     f.description,
     f.author_id,
-    f.created_at
+    f.created_at,
+    b.created_at AS bookmarked_at,
+    br.number AS bookmarked_revision,
+    COALESCE((SELECT MAX(r.number) FROM form_revisions r WHERE r.form_id = f.id), 0)::int AS latest_revision
 FROM bookmarks b
 JOIN forms f ON b.form_id = f.id
-WHERE b.user_id = $1;
+LEFT JOIN form_revisions br ON br.id = b.revision_id
+WHERE b.user_id = sqlc.arg(user_id)
+  AND f.deleted_at IS NULL
+  AND (sqlc.narg(cursor_form_id)::uuid IS NULL
+       OR (b.created_at, b.form_id) < (sqlc.arg(cursor_bookmarked_at)::timestamptz, sqlc.narg(cursor_form_id)::uuid))
+ORDER BY b.created_at DESC, b.form_id DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: Create :one
-INSERT INTO bookmarks (form_id, user_id)
-VALUES ($1, $2)
+INSERT INTO bookmarks (form_id, user_id, revision_id)
+VALUES ($1, $2, (SELECT r.id FROM form_revisions r WHERE r.form_id = $1 ORDER BY r.number DESC LIMIT 1))
 RETURNING *;
 
 -- name: Delete :exec
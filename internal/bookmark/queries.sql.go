@@ -27,9 +27,9 @@ func (q *Queries) CountBookmark(ctx context.Context, userID uuid.UUID) (int64, e
 }
 
 const create = `-- name: Create :one
-INSERT INTO bookmarks (form_id, user_id)
-VALUES ($1, $2)
-RETURNING form_id, user_id, created_at
+INSERT INTO bookmarks (form_id, user_id, revision_id)
+VALUES ($1, $2, (SELECT r.id FROM form_revisions r WHERE r.form_id = $1 ORDER BY r.number DESC LIMIT 1))
+RETURNING form_id, user_id, created_at, revision_id
 `
 
 type CreateParams struct {
@@ -40,7 +40,12 @@ type CreateParams struct {
 func (q *Queries) Create(ctx context.Context, arg CreateParams) (Bookmark, error) {
 	row := q.db.QueryRow(ctx, create, arg.FormID, arg.UserID)
 	var i Bookmark
-	err := row.Scan(&i.FormID, &i.UserID, &i.CreatedAt)
+	err := row.Scan(
+		&i.FormID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.RevisionID,
+	)
 	return i, err
 }
 
@@ -95,22 +100,46 @@ SELECT
     f.title,
     f.description,
     f.author_id,
-    f.created_at
+    f.created_at,
+    b.created_at AS bookmarked_at,
+    br.number AS bookmarked_revision,
+    COALESCE((SELECT MAX(r.number) FROM form_revisions r WHERE r.form_id = f.id), 0)::int AS latest_revision
 FROM bookmarks b
 JOIN forms f ON b.form_id = f.id
+LEFT JOIN form_revisions br ON br.id = b.revision_id
 WHERE b.user_id = $1
+  AND f.deleted_at IS NULL
+  AND ($2::uuid IS NULL
+       OR (b.created_at, b.form_id) < ($3::timestamptz, $2::uuid))
+ORDER BY b.created_at DESC, b.form_id DESC
+LIMIT $4
 `
 
+type GetFormsByUserIDParams struct {
+	UserID             uuid.UUID
+	CursorFormID       pgtype.UUID
+	CursorBookmarkedAt pgtype.Timestamptz
+	PageLimit          int32
+}
+
 type GetFormsByUserIDRow struct {
-	FormID      uuid.UUID
-	Title       string
-	Description pgtype.Text
-	AuthorID    pgtype.Text
-	CreatedAt   pgtype.Timestamptz
+	FormID             uuid.UUID
+	Title              string
+	Description        pgtype.Text
+	AuthorID           pgtype.Text
+	CreatedAt          pgtype.Timestamptz
+	BookmarkedAt       pgtype.Timestamptz
+	BookmarkedRevision pgtype.Int4
+	LatestRevision     int32
 }
 
-func (q *Queries) GetFormsByUserID(ctx context.Context, userID uuid.UUID) ([]GetFormsByUserIDRow, error) {
-	rows, err := q.db.Query(ctx, getFormsByUserID, userID)
+func (q *Queries) GetFormsByUserID(ctx context.Context, arg GetFormsByUserIDParams) ([]GetFormsByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, getFormsByUserID,
+		arg.UserID,
+		arg.CursorFormID,
+		arg.CursorBookmarkedAt,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -124,6 +153,9 @@ func (q *Queries) GetFormsByUserID(ctx context.Context, userID uuid.UUID) ([]Get
 			&i.Description,
 			&i.AuthorID,
 			&i.CreatedAt,
+			&i.BookmarkedAt,
+			&i.BookmarkedRevision,
+			&i.LatestRevision,
 		); err != nil {
 			return nil, err
 		}
@@ -1,7 +1,8 @@
 CREATE TABLE IF NOT EXISTS bookmarks
 (
-    form_id UUID REFERENCES forms (id),
+    form_id UUID REFERENCES forms (id) ON DELETE CASCADE,
     user_id UUID REFERENCES users (id),
     created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revision_id UUID REFERENCES form_revisions (id) ON DELETE SET NULL,
     PRIMARY KEY (form_id, user_id)
 )
\ No newline at end of file
@@ -1,9 +1,12 @@
 package bookmark
 
 import (
+	"awesomeProject/internal/pagination"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
 
@@ -12,7 +15,7 @@ type Querier interface {
 	Create(ctx context.Context, arg CreateParams) (Bookmark, error)
 	Delete(ctx context.Context, arg DeleteParams) error
 	Exist(ctx context.Context, arg ExistParams) (bool, error)
-	GetFormsByUserID(ctx context.Context, userID uuid.UUID) ([]GetFormsByUserIDRow, error)
+	GetFormsByUserID(ctx context.Context, arg GetFormsByUserIDParams) ([]GetFormsByUserIDRow, error)
 	CountBookmark(ctx context.Context, userID uuid.UUID) (int64, error)
 	FormCount(ctx context.Context, formID uuid.UUID) (int64, error)
 }
@@ -29,13 +32,44 @@ func NewService(logger *zap.Logger, queries Querier) *Service {
 	}
 }
 
-func (s Service) GetFormsByUserID(ctx context.Context, userID uuid.UUID) ([]GetFormsByUserIDRow, error) {
-	forms, err := s.queries.GetFormsByUserID(ctx, userID)
+// bookmarkSort identifies the newest-first ordering of bookmarks in cursors.
+const bookmarkSort = "bookmarked_at:desc"
+
+// GetFormsByUserID returns one page of the user's bookmarked forms, most
+// recently bookmarked first, and the cursor of the next page.
+func (s Service) GetFormsByUserID(ctx context.Context, userID uuid.UUID, page pagination.Params) ([]GetFormsByUserIDRow, string, error) {
+	params := GetFormsByUserIDParams{
+		UserID:    userID,
+		PageLimit: page.FetchLimit(),
+	}
+
+	cursor, err := page.CursorFor(bookmarkSort)
+	if err != nil {
+		return nil, "", err
+	}
+	if cursor != nil {
+		bookmarkedAt, err := time.Parse(time.RFC3339Nano, cursor.Key)
+		if err != nil {
+			return nil, "", pagination.ErrInvalidCursor
+		}
+		params.CursorFormID = pgtype.UUID{Bytes: cursor.ID, Valid: true}
+		params.CursorBookmarkedAt = pgtype.Timestamptz{Time: bookmarkedAt, Valid: true}
+	}
+
+	forms, err := s.queries.GetFormsByUserID(ctx, params)
 	if err != nil {
 		s.logger.Error("Failed to get bookmarked forms", zap.Error(err))
-		return nil, err
+		return nil, "", err
 	}
-	return forms, nil
+
+	forms, next := pagination.Trim(forms, page, func(row GetFormsByUserIDRow) pagination.Cursor {
+		return pagination.Cursor{
+			Sort: bookmarkSort,
+			Key:  row.BookmarkedAt.Time.Format(time.RFC3339Nano),
+			ID:   row.FormID,
+		}
+	})
+	return forms, next, nil
 }
 
 func (s Service) ToggleBookmark(ctx context.Context, userID, formID uuid.UUID) (bool, error) {
@@ -4,7 +4,26 @@ CREATE TABLE IF NOT EXISTS jwt (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     user_id UUID NOT NULL references users(id),
     expiration_time TIMESTAMPTZ NOT NULL DEFAULT now(),
-    is_available bool NOT NULL DEFAULT true
+    is_available bool NOT NULL DEFAULT true,
+    family_id UUID NOT NULL,
+    parent_id UUID references jwt(id) ON DELETE SET NULL,
+    token_hash BYTEA NOT NULL UNIQUE,
+    revoked_at TIMESTAMPTZ
+    );
+
+CREATE INDEX IF NOT EXISTS jwt_family_idx ON jwt (family_id);
+
+CREATE TABLE IF NOT EXISTS jwt_revocations (
+    jti UUID PRIMARY KEY,
+    user_id UUID NOT NULL references users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL
+    );
+
+CREATE INDEX IF NOT EXISTS jwt_revocations_expires_at_idx ON jwt_revocations (expires_at);
+
+CREATE TABLE IF NOT EXISTS jwt_user_revocations (
+    user_id UUID PRIMARY KEY references users(id) ON DELETE CASCADE,
+    revoked_before TIMESTAMPTZ NOT NULL
     );CREATE TABLE IF NOT EXISTS users (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     email TEXT NOT NULL UNIQUE,
@@ -14,11 +33,117 @@ CREATE TABLE IF NOT EXISTS jwt (
     title TEXT NOT NULL,
     description TEXT,
     author_id TEXT references users(id),
-    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
-    );CREATE TABLE IF NOT EXISTS bookmarks
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    status TEXT NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'published', 'closed', 'archived')),
+    deleted_at TIMESTAMPTZ,
+    version INT NOT NULL DEFAULT 1,
+    is_quiz BOOLEAN NOT NULL DEFAULT false,
+    reveal_answers TEXT NOT NULL DEFAULT 'never' CHECK (reveal_answers IN ('never', 'after_submission', 'after_close')),
+    template_visibility TEXT CHECK (template_visibility IN ('private', 'team', 'public'))
+    );
+
+CREATE TABLE IF NOT EXISTS form_sections
+(
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    form_id     UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    title       TEXT        NOT NULL,
+    description TEXT,
+    position    INT         NOT NULL,
+    rules       JSONB       NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS questions
+(
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    form_id     UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    type        TEXT        NOT NULL CHECK (type IN ('short_text', 'paragraph', 'single_choice', 'multiple_choice', 'number', 'date')),
+    title       TEXT        NOT NULL,
+    description TEXT,
+    required    BOOLEAN     NOT NULL DEFAULT false,
+    position    INT         NOT NULL,
+    settings    JSONB       NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    section_id  UUID REFERENCES form_sections (id) ON DELETE SET NULL,
+    rules       JSONB       NOT NULL DEFAULT '{}',
+    points      DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (points >= 0),
+    answer_key  JSONB
+);
+
+CREATE TABLE IF NOT EXISTS form_collaborators
+(
+    form_id    UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    email      TEXT        NOT NULL,
+    user_id    UUID REFERENCES users (id) ON DELETE CASCADE,
+    role       TEXT        NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    invited_by UUID REFERENCES users (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (form_id, email)
+);
+
+CREATE TABLE IF NOT EXISTS form_search_documents
 (
-    form_id UUID REFERENCES forms (id),
+    form_id    UUID PRIMARY KEY REFERENCES forms (id) ON DELETE CASCADE,
+    document   TSVECTOR    NOT NULL,
+    body       TEXT        NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS form_status_transitions
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    form_id     UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    from_status TEXT        NOT NULL,
+    to_status   TEXT        NOT NULL,
+    changed_by  UUID REFERENCES users (id) ON DELETE SET NULL,
+    changed_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS form_revisions
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    form_id    UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    number     INT         NOT NULL,
+    snapshot   JSONB       NOT NULL,
+    created_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (form_id, number)
+);CREATE TABLE IF NOT EXISTS bookmarks
+(
+    form_id UUID REFERENCES forms (id) ON DELETE CASCADE,
     user_id UUID REFERENCES users (id),
     created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revision_id UUID REFERENCES form_revisions (id) ON DELETE SET NULL,
     PRIMARY KEY (form_id, user_id)
-)
\ No newline at end of file
+)CREATE TABLE IF NOT EXISTS form_responses
+(
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    form_id       UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    respondent_id UUID REFERENCES users (id),
+    submitted_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS answers
+(
+    response_id UUID  NOT NULL REFERENCES form_responses (id) ON DELETE CASCADE,
+    question_id UUID  NOT NULL REFERENCES questions (id) ON DELETE CASCADE,
+    value       JSONB NOT NULL,
+    points      DOUBLE PRECISION,
+    graded_by   UUID REFERENCES users (id) ON DELETE SET NULL,
+    graded_at   TIMESTAMPTZ,
+    PRIMARY KEY (response_id, question_id)
+)CREATE TABLE IF NOT EXISTS form_share_links
+(
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    form_id          UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    slug             TEXT        NOT NULL UNIQUE,
+    access_code_hash TEXT,
+    require_sign_in  BOOLEAN     NOT NULL DEFAULT false,
+    expires_at       TIMESTAMPTZ,
+    created_by       UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS form_share_links_form_idx ON form_share_links (form_id, created_at)
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS questions_search_refresh ON questions;
+DROP TRIGGER IF EXISTS forms_search_refresh ON forms;
+DROP FUNCTION IF EXISTS questions_search_trigger();
+DROP FUNCTION IF EXISTS forms_search_trigger();
+DROP FUNCTION IF EXISTS refresh_form_search_document(UUID);
+DROP TABLE IF EXISTS form_search_documents;
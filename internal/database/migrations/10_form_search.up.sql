@@ -0,0 +1,88 @@
+CREATE TABLE IF NOT EXISTS form_search_documents
+(
+    form_id    UUID PRIMARY KEY REFERENCES forms (id) ON DELETE CASCADE,
+    document   TSVECTOR    NOT NULL,
+    body       TEXT        NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS form_search_documents_document_idx ON form_search_documents USING GIN (document);
+
+-- refresh_form_search_document rebuilds the search document of one form from
+-- the form and every text attached to it. Tables that attach text to forms
+-- only need a trigger calling this function to become searchable.
+CREATE OR REPLACE FUNCTION refresh_form_search_document(target UUID) RETURNS void AS
+$$
+DECLARE
+    form_title       TEXT;
+    form_description TEXT;
+    question_text    TEXT;
+BEGIN
+    SELECT title, coalesce(description, '')
+    INTO form_title, form_description
+    FROM forms
+    WHERE id = target;
+    IF NOT FOUND THEN
+        RETURN;
+    END IF;
+
+    SELECT coalesce(string_agg(q.title || ' ' || coalesce(q.description, ''), E'\n' ORDER BY q.position), '')
+    INTO question_text
+    FROM questions q
+    WHERE q.form_id = target;
+
+    INSERT INTO form_search_documents (form_id, document, body, updated_at)
+    VALUES (target,
+            setweight(to_tsvector('simple', form_title), 'A') ||
+            setweight(to_tsvector('simple', form_description), 'B') ||
+            setweight(to_tsvector('simple', question_text), 'C'),
+            concat_ws(E'\n', nullif(form_description, ''), nullif(question_text, '')),
+            now())
+    ON CONFLICT (form_id) DO UPDATE
+        SET document   = excluded.document,
+            body       = excluded.body,
+            updated_at = excluded.updated_at;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION forms_search_trigger() RETURNS trigger AS
+$$
+BEGIN
+    PERFORM refresh_form_search_document(NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION questions_search_trigger() RETURNS trigger AS
+$$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        PERFORM refresh_form_search_document(NEW.form_id);
+    ELSIF TG_OP = 'DELETE' THEN
+        PERFORM refresh_form_search_document(OLD.form_id);
+    ELSE
+        PERFORM refresh_form_search_document(OLD.form_id);
+        IF NEW.form_id <> OLD.form_id THEN
+            PERFORM refresh_form_search_document(NEW.form_id);
+        END IF;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS forms_search_refresh ON forms;
+CREATE TRIGGER forms_search_refresh
+    AFTER INSERT OR UPDATE OF title, description
+    ON forms
+    FOR EACH ROW
+EXECUTE FUNCTION forms_search_trigger();
+
+DROP TRIGGER IF EXISTS questions_search_refresh ON questions;
+CREATE TRIGGER questions_search_refresh
+    AFTER INSERT OR UPDATE OF form_id, title, description, position OR DELETE
+    ON questions
+    FOR EACH ROW
+EXECUTE FUNCTION questions_search_trigger();
+
+SELECT refresh_form_search_document(id)
+FROM forms;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS form_status_transitions;
+ALTER TABLE forms DROP COLUMN IF EXISTS status;
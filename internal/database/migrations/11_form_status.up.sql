@@ -0,0 +1,18 @@
+-- forms created before lifecycle states existed stay live
+ALTER TABLE forms
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'published'
+        CHECK (status IN ('draft', 'published', 'closed', 'archived'));
+ALTER TABLE forms
+    ALTER COLUMN status SET DEFAULT 'draft';
+
+CREATE TABLE IF NOT EXISTS form_status_transitions
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    form_id     UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    from_status TEXT        NOT NULL,
+    to_status   TEXT        NOT NULL,
+    changed_by  UUID REFERENCES users (id) ON DELETE SET NULL,
+    changed_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS form_status_transitions_form_id_idx ON form_status_transitions (form_id, changed_at);
@@ -0,0 +1,7 @@
+ALTER TABLE bookmarks
+    DROP CONSTRAINT IF EXISTS bookmarks_form_id_fkey;
+ALTER TABLE bookmarks
+    ADD CONSTRAINT bookmarks_form_id_fkey FOREIGN KEY (form_id) REFERENCES forms (id);
+
+DROP INDEX IF EXISTS forms_deleted_at_idx;
+ALTER TABLE forms DROP COLUMN IF EXISTS deleted_at;
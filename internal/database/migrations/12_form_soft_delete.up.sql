@@ -0,0 +1,10 @@
+ALTER TABLE forms
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS forms_deleted_at_idx ON forms (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- purging a form from the trash removes its bookmarks with it
+ALTER TABLE bookmarks
+    DROP CONSTRAINT IF EXISTS bookmarks_form_id_fkey;
+ALTER TABLE bookmarks
+    ADD CONSTRAINT bookmarks_form_id_fkey FOREIGN KEY (form_id) REFERENCES forms (id) ON DELETE CASCADE;
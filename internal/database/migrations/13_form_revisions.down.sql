@@ -0,0 +1,2 @@
+ALTER TABLE bookmarks DROP COLUMN IF EXISTS revision_id;
+DROP TABLE IF EXISTS form_revisions;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS form_revisions
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    form_id    UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    number     INT         NOT NULL,
+    snapshot   JSONB       NOT NULL,
+    created_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (form_id, number)
+);
+
+-- the current state of existing forms becomes their first revision
+INSERT INTO form_revisions (form_id, number, snapshot, created_by, created_at)
+SELECT id, 1, jsonb_build_object('title', title, 'description', coalesce(description, '')), author_id, created_at
+FROM forms
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE bookmarks
+    ADD COLUMN IF NOT EXISTS revision_id UUID REFERENCES form_revisions (id) ON DELETE SET NULL;
@@ -0,0 +1 @@
+ALTER TABLE forms DROP COLUMN IF EXISTS version;
@@ -0,0 +1,4 @@
+ALTER TABLE questions
+    DROP COLUMN IF EXISTS rules,
+    DROP COLUMN IF EXISTS section_id;
+DROP TABLE IF EXISTS form_sections;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS form_sections
+(
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    form_id     UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    title       TEXT        NOT NULL,
+    description TEXT,
+    position    INT         NOT NULL,
+    rules       JSONB       NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS form_sections_form_id_position_idx ON form_sections (form_id, position);
+
+-- questions without a section come before the first section
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS section_id UUID REFERENCES form_sections (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS rules      JSONB NOT NULL DEFAULT '{}';
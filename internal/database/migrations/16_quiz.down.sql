@@ -0,0 +1,10 @@
+ALTER TABLE answers
+    DROP COLUMN IF EXISTS graded_at,
+    DROP COLUMN IF EXISTS graded_by,
+    DROP COLUMN IF EXISTS points;
+ALTER TABLE questions
+    DROP COLUMN IF EXISTS answer_key,
+    DROP COLUMN IF EXISTS points;
+ALTER TABLE forms
+    DROP COLUMN IF EXISTS reveal_answers,
+    DROP COLUMN IF EXISTS is_quiz;
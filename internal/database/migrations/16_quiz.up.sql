@@ -0,0 +1,15 @@
+ALTER TABLE forms
+    ADD COLUMN IF NOT EXISTS is_quiz        BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS reveal_answers TEXT    NOT NULL DEFAULT 'never'
+        CHECK (reveal_answers IN ('never', 'after_submission', 'after_close'));
+
+-- answer_key is NULL for questions that are graded by hand
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS points     DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (points >= 0),
+    ADD COLUMN IF NOT EXISTS answer_key JSONB;
+
+-- points is NULL until the answer is graded
+ALTER TABLE answers
+    ADD COLUMN IF NOT EXISTS points    DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS graded_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS graded_at TIMESTAMPTZ;
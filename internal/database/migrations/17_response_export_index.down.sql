@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS form_responses_export_idx;
+CREATE INDEX IF NOT EXISTS form_responses_form_id_submitted_at_idx ON form_responses (form_id, submitted_at);
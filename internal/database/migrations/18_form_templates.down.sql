@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS forms_templates_idx;
+ALTER TABLE forms
+    DROP COLUMN IF EXISTS template_visibility;
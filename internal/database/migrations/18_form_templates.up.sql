@@ -0,0 +1,7 @@
+-- template_visibility is NULL for forms that are not templates
+ALTER TABLE forms
+    ADD COLUMN IF NOT EXISTS template_visibility TEXT
+        CHECK (template_visibility IN ('private', 'team', 'public'));
+
+CREATE INDEX IF NOT EXISTS forms_templates_idx ON forms (created_at DESC, id DESC)
+    WHERE template_visibility IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,5 @@
+DELETE FROM form_responses WHERE respondent_id IS NULL;
+ALTER TABLE form_responses
+    ALTER COLUMN respondent_id SET NOT NULL;
+
+DROP TABLE IF EXISTS form_share_links;
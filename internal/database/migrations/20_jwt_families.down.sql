@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS jwt_family_idx;
+ALTER TABLE jwt
+    DROP COLUMN IF EXISTS parent_id,
+    DROP COLUMN IF EXISTS family_id;
@@ -0,0 +1,12 @@
+-- refresh tokens rotated from the same login form a family, which is
+-- revoked as a whole when one of its used tokens is presented again
+ALTER TABLE jwt
+    ADD COLUMN IF NOT EXISTS family_id UUID,
+    ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES jwt (id) ON DELETE SET NULL;
+
+UPDATE jwt SET family_id = id WHERE family_id IS NULL;
+
+ALTER TABLE jwt
+    ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS jwt_family_idx ON jwt (family_id);
@@ -0,0 +1,2 @@
+ALTER TABLE jwt
+    DROP COLUMN IF EXISTS token_hash;
@@ -0,0 +1,6 @@
+-- refresh tokens used to be the row id, which anyone reading the table
+-- could present; they are all invalidated and only hashes are stored now
+DELETE FROM jwt;
+
+ALTER TABLE jwt
+    ADD COLUMN IF NOT EXISTS token_hash BYTEA NOT NULL UNIQUE;
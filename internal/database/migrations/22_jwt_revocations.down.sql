@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS jwt_user_revocations;
+DROP TABLE IF EXISTS jwt_revocations;
+ALTER TABLE jwt
+    DROP COLUMN IF EXISTS revoked_at;
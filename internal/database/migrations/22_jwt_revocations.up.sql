@@ -0,0 +1,21 @@
+-- refresh tokens revoked by logging out, unlike used ones, are no sign of
+-- theft when presented again
+ALTER TABLE jwt
+    ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
+
+-- access tokens revoked before they expire, by their jti claim
+CREATE TABLE IF NOT EXISTS jwt_revocations
+(
+    jti        UUID PRIMARY KEY,
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS jwt_revocations_expires_at_idx ON jwt_revocations (expires_at);
+
+-- every access token of the user issued before revoked_before is revoked
+CREATE TABLE IF NOT EXISTS jwt_user_revocations
+(
+    user_id        UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    revoked_before TIMESTAMPTZ NOT NULL
+);
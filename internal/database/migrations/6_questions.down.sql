@@ -0,0 +1 @@
+DROP TABLE IF EXISTS questions;
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS questions
+(
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    form_id     UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    type        TEXT        NOT NULL CHECK (type IN ('short_text', 'paragraph', 'single_choice', 'multiple_choice', 'number', 'date')),
+    title       TEXT        NOT NULL,
+    description TEXT,
+    required    BOOLEAN     NOT NULL DEFAULT false,
+    position    INT         NOT NULL,
+    settings    JSONB       NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS questions_form_id_position_idx ON questions (form_id, position);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS answers;
+DROP TABLE IF EXISTS form_responses;
\ No newline at end of file
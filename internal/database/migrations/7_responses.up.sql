@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS form_responses
+(
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    form_id       UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    respondent_id UUID        NOT NULL REFERENCES users (id),
+    submitted_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS form_responses_form_id_submitted_at_idx ON form_responses (form_id, submitted_at);
+
+CREATE TABLE IF NOT EXISTS answers
+(
+    response_id UUID  NOT NULL REFERENCES form_responses (id) ON DELETE CASCADE,
+    question_id UUID  NOT NULL REFERENCES questions (id) ON DELETE CASCADE,
+    value       JSONB NOT NULL,
+    PRIMARY KEY (response_id, question_id)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS form_collaborators
+(
+    form_id    UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    email      TEXT        NOT NULL,
+    user_id    UUID REFERENCES users (id) ON DELETE CASCADE,
+    role       TEXT        NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    invited_by UUID REFERENCES users (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (form_id, email)
+);
+
+CREATE INDEX IF NOT EXISTS form_collaborators_user_id_idx ON form_collaborators (user_id);
+CREATE INDEX IF NOT EXISTS form_collaborators_pending_email_idx ON form_collaborators (email) WHERE user_id IS NULL;
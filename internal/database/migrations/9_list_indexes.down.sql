@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS bookmarks_user_id_created_at_idx;
+DROP INDEX IF EXISTS forms_title_id_idx;
+DROP INDEX IF EXISTS forms_created_at_id_idx;
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS forms_created_at_id_idx ON forms (created_at, id);
+CREATE INDEX IF NOT EXISTS forms_title_id_idx ON forms (title, id);
+CREATE INDEX IF NOT EXISTS bookmarks_user_id_created_at_idx ON bookmarks (user_id, created_at, form_id);
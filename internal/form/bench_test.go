@@ -0,0 +1,56 @@
+package form_test
+
+import (
+	"awesomeProject/internal/form"
+	"awesomeProject/internal/form/mocks"
+	"awesomeProject/internal/jwt"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// BenchmarkList compares the per-form IsBookmarked lookup the form listing
+// used to do with the joined query it uses now. Every store call waits for
+// roundTrip to stand in for a database round trip.
+func BenchmarkList(b *testing.B) {
+	const roundTrip = 50 * time.Microsecond
+	userID := uuid.New()
+
+	for _, n := range []int{20, 100, 500} {
+		rows := make([]form.ListWithBookmarksRow, n)
+		for i := range rows {
+			rows[i] = form.ListWithBookmarksRow{ID: uuid.New(), Title: fmt.Sprintf("form %d", i)}
+		}
+
+		store := &mocks.Store{}
+		store.On("List", mock.Anything, mock.Anything, mock.Anything).After(roundTrip).Return(rows, "", nil)
+		store.On("IsBookmarked", mock.Anything, mock.Anything, mock.Anything).After(roundTrip).Return(true, nil)
+
+		b.Run(fmt.Sprintf("n+1/%d", n), func(b *testing.B) {
+			ctx := context.Background()
+			for b.Loop() {
+				forms, _, _ := store.List(ctx, userID, form.ListOptions{})
+				for _, f := range forms {
+					_, _ = store.IsBookmarked(ctx, f.ID, userID)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("joined/%d", n), func(b *testing.B) {
+			handler := form.NewHandler(zap.NewNop(), validator.New(), store)
+			for b.Loop() {
+				r := httptest.NewRequest(http.MethodGet, "/api/forms", nil)
+				r = r.WithContext(context.WithValue(r.Context(), jwt.UserContextKey, userID))
+				handler.List(httptest.NewRecorder(), r)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+// Package definition reads and writes form definitions: YAML or JSON
+// documents that describe a form with its sections, questions, rules and
+// quiz settings, so forms can be kept in version control and imported again.
+//
+// A document looks like this:
+//
+//	schema_version: 2
+//	title: Course feedback
+//	sections:
+//	  - key: details
+//	    title: Details
+//	questions:
+//	  - key: rating
+//	    type: single_choice
+//	    title: How was the course?
+//	    settings:
+//	      choices: [Good, Bad]
+//	  - key: why
+//	    section: details
+//	    type: paragraph
+//	    title: What went wrong?
+//	    rules:
+//	      show_if: {question: rating, op: equals, value: Bad}
+//
+// Sections and questions are named by a key that is unique within the
+// document. Rules, jumps and the section of a question refer to these keys.
+// Exported documents use the IDs of the sections and questions as keys, so
+// importing them again updates those sections and questions instead of
+// creating new ones.
+//
+// # Schema versions
+//
+// schema_version is required. A new version is introduced whenever a field
+// is renamed, moved or changes its meaning; adding an optional field does
+// not need one. Documents of older versions are upgraded to the current
+// version when they are parsed, so files written for an older version stay
+// importable. Exports always use the current version.
+//
+//   - Version 1 is the format from before sections, rules and quizzes. The
+//     choices of a question were listed in options instead of
+//     settings.choices.
+//   - Version 2 adds sections, rules, quiz, points and answer_key and moves
+//     options to settings.choices.
+package definition
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CurrentVersion is the schema version written by Encode.
+const CurrentVersion = 2
+
+// End is the jump target that skips all remaining sections.
+const End = "end"
+
+var ErrInvalid = errors.New("invalid form definition")
+
+type Document struct {
+	SchemaVersion int `yaml:"schema_version" json:"schema_version"`
+	// ID names the form to update on import. Documents without an ID
+	// create a new form.
+	ID          string     `yaml:"id,omitempty" json:"id,omitempty"`
+	Title       string     `yaml:"title" json:"title"`
+	Description string     `yaml:"description,omitempty" json:"description,omitempty"`
+	Quiz        *Quiz      `yaml:"quiz,omitempty" json:"quiz,omitempty"`
+	Sections    []Section  `yaml:"sections,omitempty" json:"sections,omitempty"`
+	Questions   []Question `yaml:"questions" json:"questions"`
+}
+
+// Quiz turns the form into a quiz, see form.QuizSettings.
+type Quiz struct {
+	RevealAnswers string `yaml:"reveal_answers" json:"reveal_answers"`
+}
+
+type Section struct {
+	Key         string        `yaml:"key" json:"key"`
+	Title       string        `yaml:"title" json:"title"`
+	Description string        `yaml:"description,omitempty" json:"description,omitempty"`
+	Rules       *SectionRules `yaml:"rules,omitempty" json:"rules,omitempty"`
+}
+
+type SectionRules struct {
+	ShowIf *Condition `yaml:"show_if,omitempty" json:"show_if,omitempty"`
+	Jumps  []Jump     `yaml:"jumps,omitempty" json:"jumps,omitempty"`
+}
+
+// Jump continues with the section whose key is To, or with the end of the
+// form when To is End.
+type Jump struct {
+	If *Condition `yaml:"if,omitempty" json:"if,omitempty"`
+	To string     `yaml:"to" json:"to"`
+}
+
+type Question struct {
+	Key string `yaml:"key" json:"key"`
+	// Section is the key of the section of the question. Questions without
+	// a section come before the first section.
+	Section     string         `yaml:"section,omitempty" json:"section,omitempty"`
+	Type        string         `yaml:"type" json:"type"`
+	Title       string         `yaml:"title" json:"title"`
+	Description string         `yaml:"description,omitempty" json:"description,omitempty"`
+	Required    bool           `yaml:"required,omitempty" json:"required,omitempty"`
+	Settings    *Settings      `yaml:"settings,omitempty" json:"settings,omitempty"`
+	Rules       *QuestionRules `yaml:"rules,omitempty" json:"rules,omitempty"`
+	Points      float64        `yaml:"points,omitempty" json:"points,omitempty"`
+	AnswerKey   any            `yaml:"answer_key,omitempty" json:"answer_key,omitempty"`
+}
+
+// Settings are the per-type settings of a question, see
+// form.QuestionSettings.
+type Settings struct {
+	Choices []string `yaml:"choices,omitempty" json:"choices,omitempty"`
+	Min     *float64 `yaml:"min,omitempty" json:"min,omitempty"`
+	Max     *float64 `yaml:"max,omitempty" json:"max,omitempty"`
+	MinDate string   `yaml:"min_date,omitempty" json:"min_date,omitempty"`
+	MaxDate string   `yaml:"max_date,omitempty" json:"max_date,omitempty"`
+	Pattern string   `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+}
+
+type QuestionRules struct {
+	ShowIf    *Condition `yaml:"show_if,omitempty" json:"show_if,omitempty"`
+	HideIf    *Condition `yaml:"hide_if,omitempty" json:"hide_if,omitempty"`
+	RequireIf *Condition `yaml:"require_if,omitempty" json:"require_if,omitempty"`
+}
+
+// Condition is logic.Condition with the question named by its key.
+type Condition struct {
+	Question string      `yaml:"question,omitempty" json:"question,omitempty"`
+	Op       string      `yaml:"op,omitempty" json:"op,omitempty"`
+	Value    any         `yaml:"value,omitempty" json:"value,omitempty"`
+	All      []Condition `yaml:"all,omitempty" json:"all,omitempty"`
+	Any      []Condition `yaml:"any,omitempty" json:"any,omitempty"`
+	Not      *Condition  `yaml:"not,omitempty" json:"not,omitempty"`
+}
+
+// FieldError is a problem with one field of a document. Path names the
+// field like questions[2].settings.min. Line is the line of the field in the
+// parsed file, or 0 when it is not known.
+type FieldError struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) String() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// ValidationError lists every problem found in a document.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, field := range e.Errors {
+		messages = append(messages, field.String())
+	}
+	return ErrInvalid.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalid
+}
+
+// Add records a problem with the field at path.
+func (e *ValidationError) Add(path, format string, args ...any) {
+	e.Errors = append(e.Errors, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err returns e if it holds any problem and nil otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate checks the structure of the document: required fields, unique
+// keys and references to keys that exist. Whether types, settings and rules
+// make sense for a form is checked when the document is imported.
+func (d Document) Validate() error {
+	var problems ValidationError
+
+	if d.SchemaVersion != CurrentVersion {
+		problems.Add("schema_version", "must be %d, upgrade the document first", CurrentVersion)
+	}
+	if strings.TrimSpace(d.Title) == "" {
+		problems.Add("title", "is required")
+	}
+
+	sections := make(map[string]string, len(d.Sections))
+	for i, section := range d.Sections {
+		path := fmt.Sprintf("sections[%d]", i)
+		checkKey(&problems, path, section.Key, sections)
+		if strings.TrimSpace(section.Title) == "" {
+			problems.Add(path+".title", "is required")
+		}
+	}
+	questions := make(map[string]string, len(d.Questions))
+	for i, question := range d.Questions {
+		path := fmt.Sprintf("questions[%d]", i)
+		checkKey(&problems, path, question.Key, questions)
+		if _, ok := sections[question.Key]; ok && question.Key != "" {
+			problems.Add(path+".key", "%q is already the key of a section", question.Key)
+		}
+		if question.Type == "" {
+			problems.Add(path+".type", "is required")
+		}
+		if strings.TrimSpace(question.Title) == "" {
+			problems.Add(path+".title", "is required")
+		}
+		if _, ok := sections[question.Section]; question.Section != "" && !ok {
+			problems.Add(path+".section", "no section has the key %q", question.Section)
+		}
+	}
+
+	for i, section := range d.Sections {
+		if section.Rules == nil {
+			continue
+		}
+		path := fmt.Sprintf("sections[%d].rules", i)
+		checkCondition(&problems, path+".show_if", section.Rules.ShowIf, questions)
+		for j, jump := range section.Rules.Jumps {
+			jumpPath := fmt.Sprintf("%s.jumps[%d]", path, j)
+			checkCondition(&problems, jumpPath+".if", jump.If, questions)
+			if _, ok := sections[jump.To]; jump.To != End && !ok {
+				problems.Add(jumpPath+".to", "must be %q or the key of a section", End)
+			}
+		}
+	}
+	for i, question := range d.Questions {
+		if question.Rules == nil {
+			continue
+		}
+		path := fmt.Sprintf("questions[%d].rules", i)
+		checkCondition(&problems, path+".show_if", question.Rules.ShowIf, questions)
+		checkCondition(&problems, path+".hide_if", question.Rules.HideIf, questions)
+		checkCondition(&problems, path+".require_if", question.Rules.RequireIf, questions)
+	}
+
+	return problems.Err()
+}
+
+// checkKey records key in seen, keyed to the path of its item.
+func checkKey(problems *ValidationError, path, key string, seen map[string]string) {
+	if key == "" {
+		problems.Add(path+".key", "is required")
+		return
+	}
+	if first, ok := seen[key]; ok {
+		problems.Add(path+".key", "%q is already the key of %s", key, first)
+		return
+	}
+	seen[key] = path
+}
+
+// checkCondition only checks the question keys. Operators and values are
+// checked with the rules of the form on import.
+func checkCondition(problems *ValidationError, path string, condition *Condition, questions map[string]string) {
+	if condition == nil {
+		return
+	}
+	if _, ok := questions[condition.Question]; condition.Question != "" && !ok {
+		problems.Add(path+".question", "no question has the key %q", condition.Question)
+	}
+	for i := range condition.All {
+		checkCondition(problems, fmt.Sprintf("%s.all[%d]", path, i), &condition.All[i], questions)
+	}
+	for i := range condition.Any {
+		checkCondition(problems, fmt.Sprintf("%s.any[%d]", path, i), &condition.Any[i], questions)
+	}
+	checkCondition(problems, path+".not", condition.Not, questions)
+}
@@ -0,0 +1,171 @@
+package definition_test
+
+import (
+	"awesomeProject/internal/form/definition"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const survey = `schema_version: 2
+title: Course feedback
+sections:
+  - key: details
+    title: Details
+questions:
+  - key: rating
+    type: single_choice
+    title: How was the course?
+    settings:
+      choices: [Good, Bad]
+  - key: why
+    section: details
+    type: paragraph
+    title: What went wrong?
+    rules:
+      show_if: {question: rating, op: equals, value: Bad}
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectError []definition.FieldError
+	}{
+		{
+			name:  "YAML document",
+			input: survey,
+		},
+		{
+			name: "JSON document",
+			input: `{
+	"schema_version": 2,
+	"title": "Course feedback",
+	"questions": [{"key": "rating", "type": "number", "title": "Rating", "settings": {"min": 1, "max": 5}}]
+}`,
+		},
+		{
+			name: "Unknown field",
+			input: `schema_version: 2
+title: Course feedback
+questions:
+  - key: rating
+    type: number
+    title: Rating
+    colour: red
+`,
+			expectError: []definition.FieldError{{Path: "questions[0].colour", Line: 7, Message: "unknown field"}},
+		},
+		{
+			name: "Wrong type in a JSON document",
+			input: `{"schema_version": 2, "title": "Course feedback",
+ "questions": [{"key": "rating", "type": "number", "title": "Rating",
+   "required": 1}]}`,
+			expectError: []definition.FieldError{{Path: "questions[0].required", Line: 3, Message: "must be true or false, got `1`"}},
+		},
+		{
+			name: "Broken references",
+			input: `schema_version: 2
+title: Course feedback
+questions:
+  - key: rating
+    type: number
+    title: Rating
+  - key: rating
+    section: missing
+    type: number
+    title: Again
+    rules:
+      hide_if:
+        any:
+          - {question: nope, op: answered}
+`,
+			expectError: []definition.FieldError{
+				{Path: "questions[1].key", Line: 7, Message: `"rating" is already the key of questions[0]`},
+				{Path: "questions[1].section", Line: 8, Message: `no section has the key "missing"`},
+				{Path: "questions[1].rules.hide_if.any[0].question", Line: 14, Message: `no question has the key "nope"`},
+			},
+		},
+		{
+			name:        "Missing schema version",
+			input:       "title: Course feedback\nquestions: []\n",
+			expectError: []definition.FieldError{{Path: "schema_version", Line: 1, Message: "is required"}},
+		},
+		{
+			name:        "Newer schema version",
+			input:       "schema_version: 3\ntitle: Course feedback\nquestions: []\n",
+			expectError: []definition.FieldError{{Path: "schema_version", Line: 1, Message: "version 3 is newer than the supported version 2"}},
+		},
+		{
+			name:        "Syntax error",
+			input:       "schema_version: 2\ntitle: Course feedback\n  questions: []\n",
+			expectError: []definition.FieldError{{Line: 3, Message: "invalid syntax: mapping values are not allowed in this context"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := definition.Parse([]byte(tt.input))
+			if tt.expectError == nil {
+				require.NoError(t, err)
+				assert.Equal(t, definition.CurrentVersion, file.Document.SchemaVersion)
+				return
+			}
+
+			assert.ErrorIs(t, err, definition.ErrInvalid)
+			var problems *definition.ValidationError
+			require.True(t, errors.As(err, &problems))
+			assert.Equal(t, tt.expectError, problems.Errors)
+		})
+	}
+}
+
+func TestParse_UpgradesVersion1(t *testing.T) {
+	file, err := definition.Parse([]byte(`schema_version: 1
+title: Old survey
+questions:
+  - key: colour
+    type: single_choice
+    title: Favourite colour
+    options: [Red, Green]
+    required: true
+`))
+	require.NoError(t, err)
+
+	question := file.Document.Questions[0]
+	require.NotNil(t, question.Settings)
+	assert.Equal(t, []string{"Red", "Green"}, question.Settings.Choices)
+	assert.True(t, question.Required)
+	assert.Equal(t, 7, file.Line("questions[0].settings.choices"))
+
+	_, err = definition.Parse([]byte(`schema_version: 1
+title: Old survey
+questions:
+  - key: colour
+    type: single_choice
+    title: Favourite colour
+    options: [Red]
+    settings: {choices: [Blue]}
+`))
+	var problems *definition.ValidationError
+	require.True(t, errors.As(err, &problems))
+	assert.Equal(t, []definition.FieldError{{Path: "questions[0].options", Line: 7, Message: "cannot be used together with settings.choices"}}, problems.Errors)
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	file, err := definition.Parse([]byte(survey))
+	require.NoError(t, err)
+
+	for _, format := range []definition.Format{definition.YAML, definition.JSON} {
+		t.Run(string(format), func(t *testing.T) {
+			data, err := definition.Encode(file.Document, format)
+			require.NoError(t, err)
+
+			parsed, err := definition.Parse(data)
+			require.NoError(t, err)
+			assert.Equal(t, file.Document, parsed.Document)
+		})
+	}
+}
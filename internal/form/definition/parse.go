@@ -0,0 +1,350 @@
+package definition
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	YAML Format = "yaml"
+	JSON Format = "json"
+)
+
+func (f Format) IsValid() bool {
+	return f == YAML || f == JSON
+}
+
+func (f Format) ContentType() string {
+	if f == JSON {
+		return "application/json"
+	}
+	return "application/yaml"
+}
+
+// File is a parsed document together with the lines of its fields, so
+// problems found later can still point into the file.
+type File struct {
+	Document Document
+	lines    map[string]int
+}
+
+// Line returns the line of the field at path, or of the closest parent
+// field when the field itself is missing from the file. Fields missing at
+// the top level point at the start of the document. It returns 0 when the
+// line is not known.
+func (f *File) Line(path string) int {
+	for {
+		if line, ok := f.lines[path]; ok {
+			return line
+		}
+		if path == "" {
+			return 0
+		}
+		path = parent(path)
+	}
+}
+
+// Locate fills in the lines of the problems in a *ValidationError. Other
+// errors are returned unchanged.
+func (f *File) Locate(err error) error {
+	var problems *ValidationError
+	if errors.As(err, &problems) {
+		for i := range problems.Errors {
+			if problems.Errors[i].Line == 0 {
+				problems.Errors[i].Line = f.Line(problems.Errors[i].Path)
+			}
+		}
+	}
+	return err
+}
+
+// Parse reads a YAML or JSON document, upgrades it to the current schema
+// version and validates it. All problems are reported as a
+// *ValidationError.
+func Parse(data []byte) (*File, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, syntaxError(err)
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, &ValidationError{Errors: []FieldError{{Line: root.Line, Message: "document must be a mapping"}}}
+	}
+	doc := root.Content[0]
+
+	if err := upgrade(doc); err != nil {
+		return nil, err
+	}
+
+	// Upgrades move fields around, so decode a fresh rendering of the
+	// document and map its lines back through the paths of the fields. The
+	// nodes keep the lines they had in the original file.
+	file := &File{lines: make(map[string]int)}
+	file.lines[""] = doc.Line
+	index(doc, "", file.lines)
+	block(doc)
+	rendered, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var renderedRoot yaml.Node
+	if err := yaml.Unmarshal(rendered, &renderedRoot); err != nil {
+		return nil, err
+	}
+	paths := make(map[int]string)
+	indexLines(renderedRoot.Content[0], "", paths)
+
+	decoder := yaml.NewDecoder(bytes.NewReader(rendered))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file.Document); err != nil {
+		return nil, file.Locate(decodeError(err, paths))
+	}
+
+	if err := file.Document.Validate(); err != nil {
+		return nil, file.Locate(err)
+	}
+	return file, nil
+}
+
+// Encode writes the document in the given format. YAML is the default.
+func Encode(doc Document, format Format) ([]byte, error) {
+	if format == JSON {
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// upgrades[v] upgrades a document from schema version v to v+1.
+var upgrades = map[int]func(doc *yaml.Node) error{
+	1: upgradeV1,
+}
+
+func upgrade(doc *yaml.Node) error {
+	_, value := field(doc, "schema_version")
+	if value == nil {
+		return &ValidationError{Errors: []FieldError{{Path: "schema_version", Line: doc.Line, Message: "is required"}}}
+	}
+	version, err := strconv.Atoi(value.Value)
+	if err != nil || value.Kind != yaml.ScalarNode || version < 1 {
+		return &ValidationError{Errors: []FieldError{{Path: "schema_version", Line: value.Line, Message: "must be a positive number"}}}
+	}
+	if version > CurrentVersion {
+		return &ValidationError{Errors: []FieldError{{
+			Path:    "schema_version",
+			Line:    value.Line,
+			Message: fmt.Sprintf("version %d is newer than the supported version %d", version, CurrentVersion),
+		}}}
+	}
+
+	for ; version < CurrentVersion; version++ {
+		if err := upgrades[version](doc); err != nil {
+			return err
+		}
+	}
+	value.Value = strconv.Itoa(CurrentVersion)
+	value.Tag = "!!int"
+	value.Style = 0
+	return nil
+}
+
+// upgradeV1 moves the options of questions to settings.choices.
+func upgradeV1(doc *yaml.Node) error {
+	var problems ValidationError
+	_, questions := field(doc, "questions")
+	if questions == nil || questions.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for i, question := range questions.Content {
+		if question.Kind != yaml.MappingNode {
+			continue
+		}
+		key, options := field(question, "options")
+		if options == nil {
+			continue
+		}
+		path := fmt.Sprintf("questions[%d]", i)
+		settingsKey, settings := field(question, "settings")
+		if settings == nil {
+			settingsKey = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "settings", Line: key.Line}
+			settings = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: key.Line}
+			question.Content = append(question.Content, settingsKey, settings)
+		}
+		if settings.Kind != yaml.MappingNode {
+			problems.Errors = append(problems.Errors, FieldError{Path: path + ".settings", Line: settings.Line, Message: "must be a mapping"})
+			continue
+		}
+		if _, choices := field(settings, "choices"); choices != nil {
+			problems.Errors = append(problems.Errors, FieldError{Path: path + ".options", Line: key.Line, Message: "cannot be used together with settings.choices"})
+			continue
+		}
+		removeField(question, "options")
+		settings.Content = append(settings.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "choices", Line: key.Line},
+			options,
+		)
+	}
+	return problems.Err()
+}
+
+// field returns the key and value nodes of a field of a mapping.
+func field(mapping *yaml.Node, name string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func removeField(mapping *yaml.Node, name string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// index records the line of every field and list item under node.
+func index(node *yaml.Node, path string, lines map[string]int) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			child := join(path, node.Content[i].Value)
+			lines[child] = node.Content[i].Line
+			index(node.Content[i+1], child, lines)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			lines[child] = item.Line
+			index(item, child, lines)
+		}
+	}
+}
+
+// indexLines is the inverse of index. When several fields start on the
+// same line, like the first field of a list item, the deepest one wins.
+func indexLines(node *yaml.Node, path string, paths map[int]string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			child := join(path, node.Content[i].Value)
+			paths[node.Content[i].Line] = child
+			indexLines(node.Content[i+1], child, paths)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			paths[item.Line] = child
+			indexLines(item, child, paths)
+		}
+	}
+}
+
+// block switches every mapping and list to block style, so every field of
+// the rendered document starts on a line of its own.
+func block(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle
+	for _, child := range node.Content {
+		block(child)
+	}
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func parent(path string) string {
+	i := strings.LastIndexAny(path, ".[")
+	if i < 0 {
+		return ""
+	}
+	return path[:i]
+}
+
+var (
+	lineMessage  = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	unknownField = regexp.MustCompile(`^field (\S+) not found in type`)
+	wrongType    = regexp.MustCompile(`^cannot unmarshal !!\w+ (.*) into (\S+)$`)
+)
+
+func syntaxError(err error) error {
+	message := strings.TrimPrefix(err.Error(), "yaml: ")
+	line := 0
+	if match := lineMessage.FindStringSubmatch(message); match != nil {
+		line, _ = strconv.Atoi(match[1])
+		message = match[2]
+	}
+	return &ValidationError{Errors: []FieldError{{Line: line, Message: "invalid syntax: " + message}}}
+}
+
+// decodeError turns the errors of the strict decoder into problems with
+// the paths of the fields. paths maps the lines of the rendered document to
+// paths.
+func decodeError(err error, paths map[int]string) error {
+	var typeError *yaml.TypeError
+	if !errors.As(err, &typeError) {
+		return syntaxError(err)
+	}
+
+	var problems ValidationError
+	for _, message := range typeError.Errors {
+		path := ""
+		if match := lineMessage.FindStringSubmatch(message); match != nil {
+			line, _ := strconv.Atoi(match[1])
+			path = paths[line]
+			message = match[2]
+		}
+		if match := unknownField.FindStringSubmatch(message); match != nil {
+			message = "unknown field"
+		} else if match := wrongType.FindStringSubmatch(message); match != nil {
+			message = "must be " + describe(match[2]) + ", got " + match[1]
+		}
+		problems.Errors = append(problems.Errors, FieldError{Path: path, Message: message})
+	}
+	return &problems
+}
+
+// describe names the Go type a field is decoded into the way a document
+// author would.
+func describe(goType string) string {
+	switch {
+	case goType == "string":
+		return "a string"
+	case goType == "bool":
+		return "true or false"
+	case strings.HasPrefix(goType, "int"), strings.HasPrefix(goType, "float"):
+		return "a number"
+	case strings.HasPrefix(goType, "[]"):
+		return "a list"
+	case strings.HasPrefix(goType, "map"), strings.HasPrefix(goType, "definition."):
+		return "a mapping"
+	}
+	return goType
+}
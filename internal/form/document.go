@@ -0,0 +1,570 @@
+package form
+
+import (
+	"awesomeProject/internal/form/definition"
+	"awesomeProject/internal/form/logic"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Definition exports the form with its sections, questions, rules and quiz
+// settings as a definition document, see DocumentOf. Only editors may export
+// a form, since the document includes the answer keys.
+func (s *Service) Definition(ctx context.Context, formID uuid.UUID) (Form, definition.Document, error) {
+	f, err := s.authorizeContent(ctx, formID, RoleEditor)
+	if err != nil {
+		return Form{}, definition.Document{}, err
+	}
+
+	sections, questions, err := s.structure(ctx, formID)
+	if err != nil {
+		return Form{}, definition.Document{}, err
+	}
+
+	doc, err := DocumentOf(f, sections, questions)
+	if err != nil {
+		s.logger.Error("Failed to export form", zap.String("form_id", formID.String()), zap.Error(err))
+		return Form{}, definition.Document{}, err
+	}
+
+	return f, doc, nil
+}
+
+// Import creates a form from a definition document, or updates the form
+// named by the ID of the document to match it. Sections and questions
+// whose key is the ID of one of the form are updated, all others are
+// created, and sections and questions the document does not list are
+// deleted together with their answers. Everything happens in one
+// transaction and is recorded as a new revision. created reports whether a
+// new form was made. version works like in Update.
+//
+// Problems with the document are returned as a *definition.ValidationError
+// with the paths of the offending fields.
+func (s *Service) Import(ctx context.Context, doc definition.Document, userID uuid.UUID, version *int32) (result Form, created bool, err error) {
+	err = doc.Validate()
+	if err != nil {
+		return Form{}, false, err
+	}
+
+	var current *Form
+	var sections []FormSection
+	var questions []Question
+	if doc.ID != "" {
+		id, err := uuid.Parse(doc.ID)
+		if err != nil {
+			return Form{}, false, &definition.ValidationError{Errors: []definition.FieldError{{Path: "id", Message: "must be the ID of a form"}}}
+		}
+		f, err := s.authorizeEdit(ctx, id)
+		if err != nil {
+			return Form{}, false, err
+		}
+		if version != nil && f.Version != *version {
+			return Form{}, false, ErrVersionMismatch
+		}
+		current = &f
+		sections, questions, err = s.structure(ctx, id)
+		if err != nil {
+			return Form{}, false, err
+		}
+	}
+
+	plan, err := planImport(doc, sections, questions)
+	if err != nil {
+		return Form{}, false, err
+	}
+
+	err = s.tx.InTx(ctx, func(q Querier) error {
+		if current == nil {
+			result, err = q.CreateCopy(ctx, CreateCopyParams{
+				Title:         doc.Title,
+				Description:   pgtype.Text{String: doc.Description, Valid: true},
+				AuthorID:      pgtype.Text{String: userID.String(), Valid: true},
+				IsQuiz:        plan.quiz.Enabled,
+				RevealAnswers: string(plan.quiz.RevealAnswers),
+			})
+		} else {
+			result, err = q.UpdateDefinition(ctx, UpdateDefinitionParams{
+				Title:           doc.Title,
+				Description:     pgtype.Text{String: doc.Description, Valid: true},
+				IsQuiz:          plan.quiz.Enabled,
+				RevealAnswers:   string(plan.quiz.RevealAnswers),
+				ID:              current.ID,
+				ExpectedVersion: versionParam(version),
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				// the form changed or went to the trash since it was authorized
+				if version != nil {
+					return ErrVersionMismatch
+				}
+				return ErrNotFound
+			}
+			if err != nil {
+				return err
+			}
+
+			// The update locked the form, and every change to its sections
+			// and questions bumps its version, so they cannot change from
+			// here on. Plan again against what they are now, or sections and
+			// questions added since they were first read would be deleted.
+			sections, questions, err = s.withQueries(q).structure(ctx, current.ID)
+			if err != nil {
+				return err
+			}
+			plan, err = planImport(doc, sections, questions)
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.withQueries(q).applyImport(ctx, result, plan, userID)
+	})
+	if err != nil {
+		var invalid *definition.ValidationError
+		if !errors.Is(err, ErrVersionMismatch) && !errors.Is(err, ErrNotFound) && !errors.As(err, &invalid) {
+			s.logger.Error("Failed to import form", zap.Error(err))
+		}
+		return Form{}, false, err
+	}
+
+	s.logger.Info("Imported form", zap.String("form_id", result.ID.String()), zap.Bool("created", current == nil), zap.Int("questions", len(plan.questions)))
+
+	return result, current == nil, nil
+}
+
+// applyImport replaces the sections and questions of the form with those
+// of the plan and records the revision.
+func (s *Service) applyImport(ctx context.Context, f Form, plan importPlan, userID uuid.UUID) error {
+	keepSections := make([]uuid.UUID, 0, len(plan.sections))
+	for _, section := range plan.sections {
+		keepSections = append(keepSections, section.ID)
+	}
+	keepQuestions := make([]uuid.UUID, 0, len(plan.questions))
+	for _, question := range plan.questions {
+		keepQuestions = append(keepQuestions, question.ID)
+	}
+
+	_, err := s.queries.DeleteQuestionsExcept(ctx, DeleteQuestionsExceptParams{FormID: f.ID, KeepIds: keepQuestions})
+	if err != nil {
+		return err
+	}
+	_, err = s.queries.DeleteSectionsExcept(ctx, DeleteSectionsExceptParams{FormID: f.ID, KeepIds: keepSections})
+	if err != nil {
+		return err
+	}
+
+	for _, section := range plan.sections {
+		affected, err := s.queries.ImportSection(ctx, ImportSectionParams{
+			ID:          section.ID,
+			FormID:      f.ID,
+			Title:       section.Title,
+			Description: section.Description,
+			Position:    section.Position,
+			Rules:       section.Rules,
+		})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return fmt.Errorf("section %s belongs to another form", section.ID)
+		}
+	}
+	for _, question := range plan.questions {
+		affected, err := s.queries.ImportQuestion(ctx, ImportQuestionParams{
+			ID:          question.ID,
+			FormID:      f.ID,
+			Type:        question.Type,
+			Title:       question.Title,
+			Description: question.Description,
+			Required:    question.Required,
+			Position:    question.Position,
+			Settings:    question.Settings,
+			SectionID:   question.SectionID,
+			Rules:       question.Rules,
+			Points:      question.Points,
+			AnswerKey:   question.AnswerKey,
+		})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return fmt.Errorf("question %s belongs to another form", question.ID)
+		}
+	}
+
+	_, err = s.recordRevision(ctx, f, userID)
+	return err
+}
+
+// importPlan is a document converted to rows, with the IDs the sections and
+// questions will have.
+type importPlan struct {
+	quiz      QuizSettings
+	sections  []FormSection
+	questions []Question
+}
+
+// planImport converts doc to rows and checks them like the question and
+// section endpoints would. Keys that are IDs of existing sections or
+// questions keep them, other keys get new IDs.
+func planImport(doc definition.Document, sections []FormSection, questions []Question) (importPlan, error) {
+	var problems definition.ValidationError
+	plan := importPlan{quiz: QuizSettings{RevealAnswers: RevealNever}}
+
+	if doc.Quiz != nil {
+		plan.quiz.Enabled = true
+		if doc.Quiz.RevealAnswers != "" {
+			plan.quiz.RevealAnswers = RevealPolicy(doc.Quiz.RevealAnswers)
+		}
+		if !plan.quiz.RevealAnswers.IsValid() {
+			problems.Add("quiz.reveal_answers", "unknown reveal policy %q", doc.Quiz.RevealAnswers)
+		}
+	}
+
+	existingSections := make(map[uuid.UUID]bool, len(sections))
+	for _, section := range sections {
+		existingSections[section.ID] = true
+	}
+	existingQuestions := make(map[uuid.UUID]bool, len(questions))
+	for _, question := range questions {
+		existingQuestions[question.ID] = true
+	}
+	// keys maps the keys of the document to IDs, paths maps IDs back to the
+	// paths of their items for errors of the rules.
+	keys := make(map[string]uuid.UUID, len(doc.Sections)+len(doc.Questions))
+	paths := make(map[uuid.UUID]string, len(doc.Sections)+len(doc.Questions))
+	resolve := func(key, path string, existing map[uuid.UUID]bool) {
+		id, err := uuid.Parse(key)
+		if err != nil || !existing[id] {
+			id = uuid.New()
+		}
+		keys[key] = id
+		paths[id] = path
+	}
+	for i, section := range doc.Sections {
+		resolve(section.Key, fmt.Sprintf("sections[%d]", i), existingSections)
+	}
+	for i, question := range doc.Questions {
+		resolve(question.Key, fmt.Sprintf("questions[%d]", i), existingQuestions)
+	}
+
+	for i, section := range doc.Sections {
+		rules, err := json.Marshal(sectionRulesOf(section.Rules, keys))
+		if err != nil {
+			return importPlan{}, err
+		}
+		plan.sections = append(plan.sections, FormSection{
+			ID:          keys[section.Key],
+			Title:       section.Title,
+			Description: pgtype.Text{String: section.Description, Valid: section.Description != ""},
+			Position:    int32(i),
+			Rules:       rules,
+		})
+	}
+
+	for i, question := range doc.Questions {
+		path := fmt.Sprintf("questions[%d]", i)
+		input := QuestionInput{
+			Type:        QuestionType(question.Type),
+			Title:       question.Title,
+			Description: question.Description,
+			Required:    question.Required,
+			Settings:    settingsOf(question.Settings),
+			Rules:       questionRulesOf(question.Rules, keys),
+			Points:      question.Points,
+		}
+		if question.AnswerKey != nil {
+			answerKey, err := json.Marshal(question.AnswerKey)
+			if err != nil {
+				problems.Add(path+".answer_key", "cannot be stored as JSON")
+				continue
+			}
+			input.AnswerKey = answerKey
+		}
+
+		if !input.Type.IsValid() {
+			problems.Add(path+".type", "unknown question type %q", question.Type)
+			continue
+		}
+		err := input.Settings.Validate(input.Type)
+		if err != nil {
+			problems.Add(path+".settings", "%s", detail(err, ErrInvalidQuestion))
+			continue
+		}
+		settings, err := json.Marshal(input.Settings)
+		if err != nil {
+			return importPlan{}, err
+		}
+		rules, err := json.Marshal(input.Rules)
+		if err != nil {
+			return importPlan{}, err
+		}
+		err = input.validateScoring(settings)
+		if err != nil {
+			field := ".answer_key"
+			if input.Points < 0 {
+				field = ".points"
+			}
+			problems.Add(path+field, "%s", strings.TrimPrefix(detail(err, ErrInvalidQuestion), "answer key: "))
+			continue
+		}
+
+		var sectionID pgtype.UUID
+		if question.Section != "" {
+			sectionID = pgtype.UUID{Bytes: keys[question.Section], Valid: true}
+		}
+		plan.questions = append(plan.questions, Question{
+			ID:          keys[question.Key],
+			Type:        string(input.Type),
+			Title:       input.Title,
+			Description: pgtype.Text{String: input.Description, Valid: input.Description != ""},
+			Required:    input.Required,
+			Position:    int32(i),
+			Settings:    settings,
+			SectionID:   sectionID,
+			Rules:       rules,
+			Points:      input.Points,
+			AnswerKey:   answerKeyParam(input.AnswerKey),
+		})
+	}
+	if err := problems.Err(); err != nil {
+		return importPlan{}, err
+	}
+
+	structure, err := BuildLogic(plan.sections, plan.questions)
+	if err != nil {
+		return importPlan{}, err
+	}
+	err = structure.Validate()
+	if err != nil {
+		return importPlan{}, ruleError(err, keys, paths)
+	}
+
+	return plan, nil
+}
+
+// ruleError turns an error of logic.Form.Validate, which names sections and
+// questions by ID, into a problem at the path of the rule with the keys of
+// the document in the message.
+func ruleError(err error, keys map[string]uuid.UUID, paths map[uuid.UUID]string) error {
+	if !errors.Is(err, logic.ErrInvalidRule) {
+		return err
+	}
+
+	pairs := make([]string, 0, 2*len(keys))
+	for key, id := range keys {
+		pairs = append(pairs, id.String(), strconv.Quote(key))
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	// the message is "<section|question> <id> <rule>: invalid rule: <detail>"
+	path := ""
+	message := err.Error()
+	if _, rest, ok := strings.Cut(message, " "); ok {
+		if id, rest, ok := strings.Cut(rest, " "); ok {
+			if parsed, err := uuid.Parse(id); err == nil {
+				rule, detail, _ := strings.Cut(rest, ": ")
+				path = paths[parsed] + ".rules." + rule
+				if number, ok := strings.CutPrefix(rule, "jump "); ok {
+					n, _ := strconv.Atoi(number)
+					path = fmt.Sprintf("%s.rules.jumps[%d]", paths[parsed], n-1)
+				}
+				message = strings.TrimPrefix(detail, logic.ErrInvalidRule.Error()+": ")
+			}
+		}
+	}
+
+	return &definition.ValidationError{Errors: []definition.FieldError{{Path: path, Message: replacer.Replace(message)}}}
+}
+
+// detail strips the sentinel from the message of err.
+func detail(err, sentinel error) string {
+	return strings.TrimPrefix(err.Error(), sentinel.Error()+": ")
+}
+
+// DocumentOf converts a form with its sections and questions to a
+// definition document. Sections and questions are keyed by their IDs, so
+// importing the document again updates them in place.
+func DocumentOf(f Form, sections []FormSection, questions []Question) (definition.Document, error) {
+	doc := definition.Document{
+		SchemaVersion: definition.CurrentVersion,
+		ID:            f.ID.String(),
+		Title:         f.Title,
+		Description:   f.Description.String,
+		Sections:      make([]definition.Section, 0, len(sections)),
+		Questions:     make([]definition.Question, 0, len(questions)),
+	}
+	if f.IsQuiz {
+		doc.Quiz = &definition.Quiz{RevealAnswers: f.RevealAnswers}
+	}
+
+	// keys are IDs, except for the zero ID that means no question
+	keyOf := func(id uuid.UUID) string {
+		if id == uuid.Nil {
+			return ""
+		}
+		return id.String()
+	}
+
+	for _, section := range sections {
+		rules, err := section.ParseRules()
+		if err != nil {
+			return definition.Document{}, fmt.Errorf("decode rules of section %s: %w", section.ID, err)
+		}
+		result := definition.Section{
+			Key:         section.ID.String(),
+			Title:       section.Title,
+			Description: section.Description.String,
+		}
+		if rules.ShowIf != nil || len(rules.Jumps) > 0 {
+			result.Rules = &definition.SectionRules{ShowIf: conditionDocument(rules.ShowIf, keyOf)}
+			for _, jump := range rules.Jumps {
+				result.Rules.Jumps = append(result.Rules.Jumps, definition.Jump{If: conditionDocument(jump.If, keyOf), To: jump.To})
+			}
+		}
+		doc.Sections = append(doc.Sections, result)
+	}
+
+	for _, question := range questions {
+		rules, err := question.ParseRules()
+		if err != nil {
+			return definition.Document{}, fmt.Errorf("decode rules of question %s: %w", question.ID, err)
+		}
+		var settings QuestionSettings
+		if len(question.Settings) > 0 {
+			err = json.Unmarshal(question.Settings, &settings)
+			if err != nil {
+				return definition.Document{}, fmt.Errorf("decode settings of question %s: %w", question.ID, err)
+			}
+		}
+		result := definition.Question{
+			Key:         question.ID.String(),
+			Type:        question.Type,
+			Title:       question.Title,
+			Description: question.Description.String,
+			Required:    question.Required,
+			Points:      question.Points,
+		}
+		if question.SectionID.Valid {
+			result.Section = uuid.UUID(question.SectionID.Bytes).String()
+		}
+		if settings.Choices != nil || settings.Min != nil || settings.Max != nil || settings.MinDate != "" || settings.MaxDate != "" || settings.Pattern != "" {
+			result.Settings = &definition.Settings{
+				Choices: settings.Choices,
+				Min:     settings.Min,
+				Max:     settings.Max,
+				MinDate: settings.MinDate,
+				MaxDate: settings.MaxDate,
+				Pattern: settings.Pattern,
+			}
+		}
+		if rules.ShowIf != nil || rules.HideIf != nil || rules.RequireIf != nil {
+			result.Rules = &definition.QuestionRules{
+				ShowIf:    conditionDocument(rules.ShowIf, keyOf),
+				HideIf:    conditionDocument(rules.HideIf, keyOf),
+				RequireIf: conditionDocument(rules.RequireIf, keyOf),
+			}
+		}
+		if !IsEmptyAnswer(question.AnswerKey) {
+			err = json.Unmarshal(question.AnswerKey, &result.AnswerKey)
+			if err != nil {
+				return definition.Document{}, fmt.Errorf("decode answer key of question %s: %w", question.ID, err)
+			}
+		}
+		doc.Questions = append(doc.Questions, result)
+	}
+
+	return doc, nil
+}
+
+func conditionDocument(c *logic.Condition, keyOf func(uuid.UUID) string) *definition.Condition {
+	if c == nil {
+		return nil
+	}
+	result := &definition.Condition{
+		Question: keyOf(c.Question),
+		Op:       string(c.Op),
+		Not:      conditionDocument(c.Not, keyOf),
+	}
+	if len(c.Value) > 0 {
+		// the value was stored from valid JSON
+		_ = json.Unmarshal(c.Value, &result.Value)
+	}
+	for i := range c.All {
+		result.All = append(result.All, *conditionDocument(&c.All[i], keyOf))
+	}
+	for i := range c.Any {
+		result.Any = append(result.Any, *conditionDocument(&c.Any[i], keyOf))
+	}
+	return result
+}
+
+func conditionOf(c *definition.Condition, keys map[string]uuid.UUID) *logic.Condition {
+	if c == nil {
+		return nil
+	}
+	result := &logic.Condition{
+		Question: keys[c.Question],
+		Op:       logic.Op(c.Op),
+		Not:      conditionOf(c.Not, keys),
+	}
+	if c.Value != nil {
+		// values come from a parsed document and always encode
+		result.Value, _ = json.Marshal(c.Value)
+	}
+	for i := range c.All {
+		result.All = append(result.All, *conditionOf(&c.All[i], keys))
+	}
+	for i := range c.Any {
+		result.Any = append(result.Any, *conditionOf(&c.Any[i], keys))
+	}
+	return result
+}
+
+func sectionRulesOf(rules *definition.SectionRules, keys map[string]uuid.UUID) logic.SectionRules {
+	if rules == nil {
+		return logic.SectionRules{}
+	}
+	result := logic.SectionRules{ShowIf: conditionOf(rules.ShowIf, keys)}
+	for _, jump := range rules.Jumps {
+		to := jump.To
+		if to != definition.End {
+			to = keys[to].String()
+		}
+		result.Jumps = append(result.Jumps, logic.Jump{If: conditionOf(jump.If, keys), To: to})
+	}
+	return result
+}
+
+func questionRulesOf(rules *definition.QuestionRules, keys map[string]uuid.UUID) logic.QuestionRules {
+	if rules == nil {
+		return logic.QuestionRules{}
+	}
+	return logic.QuestionRules{
+		ShowIf:    conditionOf(rules.ShowIf, keys),
+		HideIf:    conditionOf(rules.HideIf, keys),
+		RequireIf: conditionOf(rules.RequireIf, keys),
+	}
+}
+
+func settingsOf(settings *definition.Settings) QuestionSettings {
+	if settings == nil {
+		return QuestionSettings{}
+	}
+	return QuestionSettings{
+		Choices: settings.Choices,
+		Min:     settings.Min,
+		Max:     settings.Max,
+		MinDate: settings.MinDate,
+		MaxDate: settings.MaxDate,
+		Pattern: settings.Pattern,
+	}
+}
@@ -0,0 +1,62 @@
+package form
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ETag returns the entity tag of the current version of the form. Every
+// change to the form, its sections or its questions bumps its version, so
+// the tag changes with it and also covers the definition of the form.
+func ETag(f Form) string {
+	return `"` + strconv.FormatInt(int64(f.Version), 10) + `"`
+}
+
+// ifMatch reads the If-Match header of r. It returns nil when the header is
+// missing or "*", which both leave the change unconditional. Only a single
+// strong tag is supported; anything else can never match and is reported as
+// ErrVersionMismatch.
+func ifMatch(r *http.Request) (*int32, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+
+	version, ok := parseETag(value)
+	if !ok {
+		return nil, ErrVersionMismatch
+	}
+	return &version, nil
+}
+
+// notModified reports whether the If-None-Match header of r names the
+// current version of f. Weak tags match too, as required for GET.
+func notModified(r *http.Request, f Form) bool {
+	value := strings.TrimSpace(r.Header.Get("If-None-Match"))
+	if value == "" {
+		return false
+	}
+	if value == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(value, ",") {
+		version, ok := parseETag(strings.TrimPrefix(strings.TrimSpace(tag), "W/"))
+		if ok && version == f.Version {
+			return true
+		}
+	}
+	return false
+}
+
+func parseETag(tag string) (int32, bool) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int32(version), true
+}
@@ -1,10 +1,20 @@
 package form
 
 import (
+	"awesomeProject/internal/form/definition"
+	"awesomeProject/internal/form/logic"
 	"awesomeProject/internal/jwt"
+	"awesomeProject/internal/pagination"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -17,6 +27,17 @@ type Request struct {
 	Description string `json:"description" validate:"required"`
 }
 
+// CopyRequest optionally renames a copied form. Empty fields keep the title
+// and description of the original.
+type CopyRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type TemplateRequest struct {
+	Visibility string `json:"visibility" validate:"required,oneof=private team public"`
+}
+
 type UpdateRequest struct {
 	ID          string `json:"id" validate:"required"`
 	Title       string `json:"title" validate:"required"`
@@ -27,21 +48,192 @@ type DeleteRequest struct {
 	ID string `json:"id" validate:"required"`
 }
 
+type QuestionRequest struct {
+	Type        string              `json:"type" validate:"required,oneof=short_text paragraph single_choice multiple_choice number date"`
+	Title       string              `json:"title" validate:"required"`
+	Description string              `json:"description"`
+	Required    bool                `json:"required"`
+	Position    *int32              `json:"position" validate:"omitempty,min=0"`
+	Settings    QuestionSettings    `json:"settings"`
+	SectionID   *uuid.UUID          `json:"section_id"`
+	Rules       logic.QuestionRules `json:"rules"`
+	Points      float64             `json:"points" validate:"min=0"`
+	AnswerKey   json.RawMessage     `json:"answer_key"`
+}
+
+type SectionRequest struct {
+	Title       string             `json:"title" validate:"required"`
+	Description string             `json:"description"`
+	Position    *int32             `json:"position" validate:"omitempty,min=0"`
+	Rules       logic.SectionRules `json:"rules"`
+}
+
+type QuizRequest struct {
+	IsQuiz        bool   `json:"is_quiz"`
+	RevealAnswers string `json:"reveal_answers" validate:"omitempty,oneof=never after_submission after_close"`
+}
+
+type ValidateAnswersRequest struct {
+	Answers map[string]json.RawMessage `json:"answers" validate:"required"`
+}
+
+type CollaboratorRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=viewer editor owner"`
+}
+
+type UpdateCollaboratorRequest struct {
+	Role string `json:"role" validate:"required,oneof=viewer editor owner"`
+}
+
 type Response struct {
-	ID           string    `json:"id"`
-	Title        string    `json:"title"`
-	Description  string    `json:"description"`
-	IsBookmarked bool      `json:"is_bookmarked"`
-	CreatedAt    time.Time `json:"createdAt"`
+	ID            string     `json:"id"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	IsBookmarked  bool       `json:"is_bookmarked"`
+	Bookmarks     int64      `json:"bookmark_count"`
+	Status        string     `json:"status"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty"`
+	IsQuiz        bool       `json:"is_quiz,omitempty"`
+	RevealAnswers string     `json:"reveal_answers,omitempty"`
+	Template      string     `json:"template_visibility,omitempty"`
+}
+
+// DefinitionErrorResponse lists every problem of an imported definition
+// with the path of the field in the document and its line, when known.
+type DefinitionErrorResponse struct {
+	Error  string                  `json:"error"`
+	Errors []definition.FieldError `json:"errors"`
+}
+
+type ListResponse struct {
+	Forms      []Response `json:"forms"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// SearchResult is a form matching a search. TitleHighlight and Snippet wrap the
+// matched words in <mark> tags; the surrounding text is not HTML escaped.
+type SearchResult struct {
+	ID             string    `json:"id"`
+	Title          string    `json:"title"`
+	Description    string    `json:"description"`
+	TitleHighlight string    `json:"title_highlight"`
+	Snippet        string    `json:"snippet"`
+	Rank           float32   `json:"rank"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type SearchResponse struct {
+	Results    []SearchResult `json:"results"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// QuestionResponse leaves out form_id where the form is not to be named,
+// see share.PublicFormResponse.
+type QuestionResponse struct {
+	ID          string              `json:"id"`
+	FormID      string              `json:"form_id,omitempty"`
+	Type        string              `json:"type"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Required    bool                `json:"required"`
+	Position    int32               `json:"position"`
+	Settings    QuestionSettings    `json:"settings"`
+	SectionID   *string             `json:"section_id"`
+	Rules       logic.QuestionRules `json:"rules"`
+	Points      float64             `json:"points"`
+	AnswerKey   json.RawMessage     `json:"answer_key,omitempty"`
+	CreatedAt   time.Time           `json:"createdAt"`
+	UpdatedAt   time.Time           `json:"updatedAt"`
+}
+
+// SectionResponse leaves out form_id like QuestionResponse.
+type SectionResponse struct {
+	ID          string             `json:"id"`
+	FormID      string             `json:"form_id,omitempty"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Position    int32              `json:"position"`
+	Rules       logic.SectionRules `json:"rules"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt"`
+}
+
+// ValidateAnswersResponse tells the client which sections and questions to
+// show for the answers so far and which answers are rejected.
+type ValidateAnswersResponse struct {
+	Valid    bool              `json:"valid"`
+	Errors   map[string]string `json:"errors"`
+	Path     []string          `json:"sections"`
+	Shown    []string          `json:"shown_questions"`
+	Required []string          `json:"required_questions"`
+}
+
+type TransitionResponse struct {
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ChangedBy  *string   `json:"changed_by"`
+	ChangedAt  time.Time `json:"changedAt"`
+}
+
+type RevisionResponse struct {
+	Number    int32           `json:"number"`
+	Snapshot  json.RawMessage `json:"snapshot"`
+	CreatedBy *string         `json:"created_by"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+type DiffResponse struct {
+	From    int32         `json:"from"`
+	To      int32         `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+type CollaboratorResponse struct {
+	FormID    string    `json:"form_id"`
+	Email     string    `json:"email"`
+	UserID    *string   `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 //go:generate mockery --name=Store
 type Store interface {
 	Create(ctx context.Context, name, description string, authorId uuid.UUID) (Form, error)
-	List(ctx context.Context) ([]Form, error)
-	Update(ctx context.Context, id uuid.UUID, name, description string) (Form, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, userID uuid.UUID, opts ListOptions) ([]ListWithBookmarksRow, string, error)
+	Search(ctx context.Context, userID uuid.UUID, query string, page pagination.Params) ([]SearchRow, string, error)
+	Transition(ctx context.Context, formID uuid.UUID, action Action) (Form, error)
+	ListTransitions(ctx context.Context, formID uuid.UUID) ([]FormStatusTransition, error)
+	ListRevisions(ctx context.Context, formID uuid.UUID) ([]FormRevision, error)
+	DiffRevisions(ctx context.Context, formID uuid.UUID, from, to int32) ([]FieldChange, error)
+	Rollback(ctx context.Context, formID uuid.UUID, number int32) (Form, error)
+	SetQuiz(ctx context.Context, id uuid.UUID, settings QuizSettings, version *int32) (Form, error)
+	CreateFromTemplate(ctx context.Context, templateID, authorID uuid.UUID, opts CopyOptions) (Form, error)
+	Duplicate(ctx context.Context, formID, userID uuid.UUID, opts CopyOptions) (Form, error)
+	SetTemplate(ctx context.Context, id uuid.UUID, visibility TemplateVisibility, version *int32) (Form, error)
+	ListTemplates(ctx context.Context, userID uuid.UUID, page pagination.Params) ([]Form, string, error)
+	Definition(ctx context.Context, formID uuid.UUID) (Form, definition.Document, error)
+	Import(ctx context.Context, doc definition.Document, userID uuid.UUID, version *int32) (Form, bool, error)
+	Visible(ctx context.Context, formID uuid.UUID) (Form, error)
+	Update(ctx context.Context, id uuid.UUID, name, description string, version *int32) (Form, error)
+	Delete(ctx context.Context, id uuid.UUID, version *int32) error
+	Restore(ctx context.Context, id uuid.UUID) (Form, error)
+	ListTrash(ctx context.Context, userID uuid.UUID, page pagination.Params) ([]Form, string, error)
 	IsBookmarked(ctx context.Context, formId, userId uuid.UUID) (bool, error)
+	CreateQuestion(ctx context.Context, formID uuid.UUID, input QuestionInput) (Question, error)
+	ListQuestions(ctx context.Context, formID uuid.UUID) ([]Question, error)
+	UpdateQuestion(ctx context.Context, formID, questionID uuid.UUID, input QuestionInput) (Question, error)
+	DeleteQuestion(ctx context.Context, formID, questionID uuid.UUID) error
+	CreateSection(ctx context.Context, formID uuid.UUID, input SectionInput) (FormSection, error)
+	ListSections(ctx context.Context, formID uuid.UUID) ([]FormSection, error)
+	UpdateSection(ctx context.Context, formID, sectionID uuid.UUID, input SectionInput) (FormSection, error)
+	DeleteSection(ctx context.Context, formID, sectionID uuid.UUID) error
+	ValidateAnswers(ctx context.Context, formID uuid.UUID, answers map[uuid.UUID]json.RawMessage) (logic.Result, map[string]string, error)
+	InviteCollaborator(ctx context.Context, formID uuid.UUID, email string, role Role) (FormCollaborator, error)
+	ListCollaborators(ctx context.Context, formID uuid.UUID) ([]FormCollaborator, error)
+	UpdateCollaborator(ctx context.Context, formID uuid.UUID, email string, role Role) (FormCollaborator, error)
+	RemoveCollaborator(ctx context.Context, formID uuid.UUID, email string) error
 }
 
 type Handler struct {
@@ -58,9 +250,16 @@ func NewHandler(logger *zap.Logger, validator *validator.Validate, store Store)
 	}
 }
 
+// Create creates an empty form, or a copy of a template when the
+// from_template query parameter holds its ID.
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	if r.URL.Query().Has("from_template") {
+		h.createFromTemplate(w, r)
+		return
+	}
+
 	var req Request
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
@@ -93,6 +292,7 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		ID:          newForm.ID.String(),
 		Title:       newForm.Title,
 		Description: newForm.Description.String,
+		Status:      newForm.Status,
 		CreatedAt:   newForm.CreatedAt.Time,
 	}
 
@@ -105,30 +305,224 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *Handler) createFromTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	templateID, err := uuid.Parse(r.URL.Query().Get("from_template"))
+	if err != nil {
+		h.logger.Warn("Invalid template ID", zap.String("template_id", r.URL.Query().Get("from_template")))
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	// the body is optional
+	var req CopyRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userID := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	newForm, err := h.store.CreateFromTemplate(ctx, templateID, userID, CopyOptions{
+		Title:       req.Title,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.writeError(w, "Failed to create form from template", err)
+		return
+	}
+
+	h.writeForm(w, newForm, http.StatusCreated)
+}
+
+// Duplicate copies a form with its sections and questions. The body may
+// rename the copy.
+func (h *Handler) Duplicate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	// the body is optional
+	var req CopyRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userID := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	newForm, err := h.store.Duplicate(ctx, formID, userID, CopyOptions{
+		Title:       req.Title,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.writeError(w, "Failed to duplicate form", err)
+		return
+	}
+
+	h.writeForm(w, newForm, http.StatusCreated)
+}
+
+// writeForm answers a request that created or replaced a whole form, like
+// a copy or an import.
+func (h *Handler) writeForm(w http.ResponseWriter, newForm Form, status int) {
+	resp := Response{
+		ID:            newForm.ID.String(),
+		Title:         newForm.Title,
+		Description:   newForm.Description.String,
+		Status:        newForm.Status,
+		CreatedAt:     newForm.CreatedAt.Time,
+		IsQuiz:        newForm.IsQuiz,
+		RevealAnswers: newForm.RevealAnswers,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", ETag(newForm))
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// maxDefinitionSize bounds the body of POST /api/forms/import.
+const maxDefinitionSize = 1 << 20
+
+// Definition exports the form as a definition document, in YAML unless the
+// format query parameter asks for json. See the definition package for the
+// format.
+func (h *Handler) Definition(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	format := definition.YAML
+	if value := r.URL.Query().Get("format"); value != "" {
+		format = definition.Format(value)
+		if !format.IsValid() {
+			h.logger.Warn("Invalid definition format", zap.String("format", value))
+			http.Error(w, "Invalid format, use yaml or json", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, doc, err := h.store.Definition(ctx, id)
+	if err != nil {
+		h.writeError(w, "Failed to export form", err)
+		return
+	}
+
+	w.Header().Set("ETag", ETag(result))
+	if notModified(r, result) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	body, err := definition.Encode(doc, format)
+	if err != nil {
+		h.logger.Error("Failed to encode definition", zap.Error(err))
+		http.Error(w, "Failed to encode definition", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", result.ID.String()+"."+string(format)))
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(body)
+	if err != nil {
+		h.logger.Error("Failed to write response", zap.Error(err))
+	}
+}
+
+// Import creates a form from a YAML or JSON definition document, or updates
+// the form named by the id field of the document. It answers 201 for a new
+// form and 200 for an update. If-Match applies to updates like in Update.
+// Problems with the document are listed with their paths, see
+// DefinitionErrorResponse.
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxDefinitionSize))
+	if err != nil {
+		h.logger.Warn("Failed to read request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	file, err := definition.Parse(body)
+	if err != nil {
+		h.writeError(w, "Invalid form definition", err)
+		return
+	}
+
+	version, err := ifMatch(r)
+	if err != nil {
+		h.writeError(w, "Failed to import form", err)
+		return
+	}
+
+	userID := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	result, created, err := h.store.Import(ctx, file.Document, userID, version)
+	if err != nil {
+		h.writeError(w, "Failed to import form", file.Locate(err))
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	h.writeForm(w, result, status)
+}
+
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	forms, err := h.store.List(ctx)
+	opts, err := parseListOptions(r.URL.Query())
+	if err != nil {
+		h.logger.Warn("Invalid list query", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
+	userId := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	forms, next, err := h.store.List(ctx, userId, opts)
 	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			h.logger.Warn("Invalid cursor", zap.Error(err))
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
 		h.logger.Error("Failed to list forms", zap.Error(err))
 		http.Error(w, "Failed to list forms", http.StatusInternalServerError)
 		return
 	}
-	userId := ctx.Value(jwt.UserContextKey).(uuid.UUID)
-	var resp []Response
+	resp := ListResponse{
+		Forms:      make([]Response, 0, len(forms)),
+		NextCursor: next,
+	}
 	for _, form := range forms {
-		bookmarked, err := h.store.IsBookmarked(ctx, form.ID, userId)
-		if err != nil {
-			h.logger.Error("Failed to check bookmark", zap.Error(err))
-			http.Error(w, "Failed to check bookmark", http.StatusInternalServerError)
-			return
-		}
-		resp = append(resp, Response{
+		resp.Forms = append(resp.Forms, Response{
 			ID:           form.ID.String(),
 			Title:        form.Title,
 			Description:  form.Description.String,
-			IsBookmarked: bookmarked,
+			IsBookmarked: form.IsBookmarked,
+			Bookmarks:    form.BookmarkCount,
+			Status:       form.Status,
 			CreatedAt:    form.CreatedAt.Time,
 		})
 	}
@@ -142,6 +536,105 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	query := r.URL.Query().Get("q")
+	if strings.TrimSpace(query) == "" {
+		h.logger.Warn("Missing search query")
+		http.Error(w, "Missing search query", http.StatusBadRequest)
+		return
+	}
+
+	page, err := pagination.ParseParams(r.URL.Query())
+	if err != nil {
+		h.logger.Warn("Invalid pagination", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	results, next, err := h.store.Search(ctx, userID, query, page)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			h.logger.Warn("Invalid cursor", zap.Error(err))
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		h.logger.Error("Failed to search forms", zap.Error(err))
+		http.Error(w, "Failed to search forms", http.StatusInternalServerError)
+		return
+	}
+
+	resp := SearchResponse{
+		Results:    make([]SearchResult, 0, len(results)),
+		NextCursor: next,
+	}
+	for _, result := range results {
+		resp.Results = append(resp.Results, SearchResult{
+			ID:             result.ID.String(),
+			Title:          result.Title,
+			Description:    result.Description.String,
+			TitleHighlight: result.TitleHighlight,
+			Snippet:        result.Snippet,
+			Rank:           result.Rank,
+			CreatedAt:      result.CreatedAt.Time,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// Get returns a single form with its current version as ETag. A request
+// whose If-None-Match names that version gets 304 Not Modified, so clients
+// can poll for changes cheaply.
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.store.Visible(ctx, formID)
+	if err != nil {
+		h.writeError(w, "Failed to get form", err)
+		return
+	}
+
+	w.Header().Set("ETag", ETag(result))
+	if notModified(r, result) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	resp := Response{
+		ID:            result.ID.String(),
+		Title:         result.Title,
+		Description:   result.Description.String,
+		Status:        result.Status,
+		CreatedAt:     result.CreatedAt.Time,
+		IsQuiz:        result.IsQuiz,
+		RevealAnswers: result.RevealAnswers,
+		Template:      result.TemplateVisibility.String,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
 func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	var req UpdateRequest
@@ -159,11 +652,20 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	id, err := uuid.Parse(req.ID)
-	updateForm, err := h.store.Update(ctx, id, req.Title, req.Description)
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", req.ID))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+	version, err := ifMatch(r)
+	if err != nil {
+		h.writeError(w, "Failed to update form", err)
+		return
+	}
+	updateForm, err := h.store.Update(ctx, id, req.Title, req.Description, version)
 
 	if err != nil {
-		h.logger.Error("Failed to update form", zap.Error(err))
-		http.Error(w, "Failed to update form", http.StatusInternalServerError)
+		h.writeError(w, "Failed to update form", err)
 		return
 	}
 
@@ -171,9 +673,11 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		ID:          updateForm.ID.String(),
 		Title:       updateForm.Title,
 		Description: updateForm.Description.String,
+		Status:      updateForm.Status,
 		CreatedAt:   updateForm.CreatedAt.Time,
 	}
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", ETag(updateForm))
 	w.WriteHeader(http.StatusOK)
 	err = json.NewEncoder(w).Encode(resp)
 	if err != nil {
@@ -200,13 +704,1239 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	id, err := uuid.Parse(req.ID)
-	err = h.store.Delete(ctx, id)
 	if err != nil {
-		h.logger.Error("Failed to delete form", zap.Error(err))
-		http.Error(w, "Failed to delete form", http.StatusBadRequest)
+		h.logger.Warn("Invalid form ID", zap.String("form_id", req.ID))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+	version, err := ifMatch(r)
+	if err != nil {
+		h.writeError(w, "Failed to delete form", err)
+		return
+	}
+	err = h.store.Delete(ctx, id, version)
+	if err != nil {
+		h.writeError(w, "Failed to delete form", err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 }
+
+func (h *Handler) CreateQuestion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	var req QuestionRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// validate request
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.logger.Error("Validation failed", zap.Error(err))
+		http.Error(w, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	question, err := h.store.CreateQuestion(ctx, formID, req.toInput())
+	if err != nil {
+		h.writeError(w, "Failed to create question", err)
+		return
+	}
+
+	resp, err := NewQuestionResponse(question)
+	if err != nil {
+		h.logger.Error("Failed to decode question", zap.Error(err))
+		http.Error(w, "Failed to create question", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) ListQuestions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	questions, err := h.store.ListQuestions(ctx, formID)
+	if err != nil {
+		h.writeError(w, "Failed to list questions", err)
+		return
+	}
+
+	resp := make([]QuestionResponse, 0, len(questions))
+	for _, question := range questions {
+		item, err := NewQuestionResponse(question)
+		if err != nil {
+			h.logger.Error("Failed to decode question", zap.Error(err))
+			http.Error(w, "Failed to list questions", http.StatusInternalServerError)
+			return
+		}
+		resp = append(resp, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+	questionID, err := uuid.Parse(r.PathValue("questionId"))
+	if err != nil {
+		h.logger.Warn("Invalid question ID", zap.String("question_id", r.PathValue("questionId")))
+		http.Error(w, "Invalid question ID", http.StatusBadRequest)
+		return
+	}
+
+	var req QuestionRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// validate request
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.logger.Error("Validation failed", zap.Error(err))
+		http.Error(w, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	question, err := h.store.UpdateQuestion(ctx, formID, questionID, req.toInput())
+	if err != nil {
+		h.writeError(w, "Failed to update question", err)
+		return
+	}
+
+	resp, err := NewQuestionResponse(question)
+	if err != nil {
+		h.logger.Error("Failed to decode question", zap.Error(err))
+		http.Error(w, "Failed to update question", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) DeleteQuestion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+	questionID, err := uuid.Parse(r.PathValue("questionId"))
+	if err != nil {
+		h.logger.Warn("Invalid question ID", zap.String("question_id", r.PathValue("questionId")))
+		http.Error(w, "Invalid question ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.store.DeleteQuestion(ctx, formID, questionID)
+	if err != nil {
+		h.writeError(w, "Failed to delete question", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) CreateSection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	var req SectionRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// validate request
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.logger.Error("Validation failed", zap.Error(err))
+		http.Error(w, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	section, err := h.store.CreateSection(ctx, formID, req.toInput())
+	if err != nil {
+		h.writeError(w, "Failed to create section", err)
+		return
+	}
+
+	resp, err := NewSectionResponse(section)
+	if err != nil {
+		h.logger.Error("Failed to decode section", zap.Error(err))
+		http.Error(w, "Failed to create section", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) ListSections(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	sections, err := h.store.ListSections(ctx, formID)
+	if err != nil {
+		h.writeError(w, "Failed to list sections", err)
+		return
+	}
+
+	resp := make([]SectionResponse, 0, len(sections))
+	for _, section := range sections {
+		item, err := NewSectionResponse(section)
+		if err != nil {
+			h.logger.Error("Failed to decode section", zap.Error(err))
+			http.Error(w, "Failed to list sections", http.StatusInternalServerError)
+			return
+		}
+		resp = append(resp, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) UpdateSection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+	sectionID, err := uuid.Parse(r.PathValue("sectionId"))
+	if err != nil {
+		h.logger.Warn("Invalid section ID", zap.String("section_id", r.PathValue("sectionId")))
+		http.Error(w, "Invalid section ID", http.StatusBadRequest)
+		return
+	}
+
+	var req SectionRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// validate request
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.logger.Error("Validation failed", zap.Error(err))
+		http.Error(w, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	section, err := h.store.UpdateSection(ctx, formID, sectionID, req.toInput())
+	if err != nil {
+		h.writeError(w, "Failed to update section", err)
+		return
+	}
+
+	resp, err := NewSectionResponse(section)
+	if err != nil {
+		h.logger.Error("Failed to decode section", zap.Error(err))
+		http.Error(w, "Failed to update section", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) DeleteSection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+	sectionID, err := uuid.Parse(r.PathValue("sectionId"))
+	if err != nil {
+		h.logger.Warn("Invalid section ID", zap.String("section_id", r.PathValue("sectionId")))
+		http.Error(w, "Invalid section ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.store.DeleteSection(ctx, formID, sectionID)
+	if err != nil {
+		h.writeError(w, "Failed to delete section", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ValidateAnswers checks answers against the questions and rules of the form
+// without submitting them. Clients can call it while the respondent fills in
+// the form to know which sections and questions to show next.
+func (h *Handler) ValidateAnswers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	var req ValidateAnswersRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// validate request
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.logger.Error("Validation failed", zap.Error(err))
+		http.Error(w, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	answers := make(map[uuid.UUID]json.RawMessage, len(req.Answers))
+	for key, value := range req.Answers {
+		questionID, err := uuid.Parse(key)
+		if err != nil {
+			h.logger.Warn("Invalid question ID in answers", zap.String("question_id", key))
+			http.Error(w, "Invalid question ID", http.StatusBadRequest)
+			return
+		}
+		answers[questionID] = value
+	}
+
+	result, problems, err := h.store.ValidateAnswers(ctx, formID, answers)
+	if err != nil {
+		h.writeError(w, "Failed to validate answers", err)
+		return
+	}
+
+	resp := ValidateAnswersResponse{
+		Valid:    len(problems) == 0,
+		Errors:   problems,
+		Path:     make([]string, 0, len(result.Path)),
+		Shown:    sortedIDs(result.Shown),
+		Required: sortedIDs(result.Required),
+	}
+	for _, sectionID := range result.Path {
+		// questions without a section are on the path as the zero ID
+		if sectionID != uuid.Nil {
+			resp.Path = append(resp.Path, sectionID.String())
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) Trash(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	page, err := pagination.ParseParams(r.URL.Query())
+	if err != nil {
+		h.logger.Warn("Invalid pagination", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	forms, next, err := h.store.ListTrash(ctx, userID, page)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			h.logger.Warn("Invalid cursor", zap.Error(err))
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		h.logger.Error("Failed to list trash", zap.Error(err))
+		http.Error(w, "Failed to list trash", http.StatusInternalServerError)
+		return
+	}
+
+	resp := ListResponse{
+		Forms:      make([]Response, 0, len(forms)),
+		NextCursor: next,
+	}
+	for _, form := range forms {
+		deletedAt := form.DeletedAt.Time
+		resp.Forms = append(resp.Forms, Response{
+			ID:          form.ID.String(),
+			Title:       form.Title,
+			Description: form.Description.String,
+			Status:      form.Status,
+			CreatedAt:   form.CreatedAt.Time,
+			DeletedAt:   &deletedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	restored, err := h.store.Restore(ctx, formID)
+	if err != nil {
+		h.writeError(w, "Failed to restore form", err)
+		return
+	}
+
+	resp := Response{
+		ID:          restored.ID.String(),
+		Title:       restored.Title,
+		Description: restored.Description.String,
+		Status:      restored.Status,
+		CreatedAt:   restored.CreatedAt.Time,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", ETag(restored))
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) Publish(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, ActionPublish)
+}
+
+func (h *Handler) Close(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, ActionClose)
+}
+
+func (h *Handler) Reopen(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, ActionReopen)
+}
+
+func (h *Handler) Archive(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, ActionArchive)
+}
+
+func (h *Handler) transition(w http.ResponseWriter, r *http.Request, action Action) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.store.Transition(ctx, formID, action)
+	if err != nil {
+		h.writeError(w, "Failed to change form status", err)
+		return
+	}
+
+	resp := Response{
+		ID:          result.ID.String(),
+		Title:       result.Title,
+		Description: result.Description.String,
+		Status:      result.Status,
+		CreatedAt:   result.CreatedAt.Time,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", ETag(result))
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) ListTransitions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	transitions, err := h.store.ListTransitions(ctx, formID)
+	if err != nil {
+		h.writeError(w, "Failed to list form status transitions", err)
+		return
+	}
+
+	resp := make([]TransitionResponse, 0, len(transitions))
+	for _, transition := range transitions {
+		item := TransitionResponse{
+			FromStatus: transition.FromStatus,
+			ToStatus:   transition.ToStatus,
+			ChangedAt:  transition.ChangedAt.Time,
+		}
+		if transition.ChangedBy.Valid {
+			changedBy := uuid.UUID(transition.ChangedBy.Bytes).String()
+			item.ChangedBy = &changedBy
+		}
+		resp = append(resp, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	revisions, err := h.store.ListRevisions(ctx, formID)
+	if err != nil {
+		h.writeError(w, "Failed to list form revisions", err)
+		return
+	}
+
+	resp := make([]RevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		item := RevisionResponse{
+			Number:    revision.Number,
+			Snapshot:  revision.Snapshot,
+			CreatedAt: revision.CreatedAt.Time,
+		}
+		if revision.CreatedBy.Valid {
+			createdBy := uuid.UUID(revision.CreatedBy.Bytes).String()
+			item.CreatedBy = &createdBy
+		}
+		resp = append(resp, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// DiffRevisions handles GET /api/forms/{id}/revisions/diff?from=1&to=2.
+func (h *Handler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+	from, err := parseRevisionNumber(r.URL.Query().Get("from"))
+	if err != nil {
+		h.logger.Warn("Invalid revision", zap.String("from", r.URL.Query().Get("from")))
+		http.Error(w, "Invalid from revision", http.StatusBadRequest)
+		return
+	}
+	to, err := parseRevisionNumber(r.URL.Query().Get("to"))
+	if err != nil {
+		h.logger.Warn("Invalid revision", zap.String("to", r.URL.Query().Get("to")))
+		http.Error(w, "Invalid to revision", http.StatusBadRequest)
+		return
+	}
+
+	changes, err := h.store.DiffRevisions(ctx, formID, from, to)
+	if err != nil {
+		h.writeError(w, "Failed to diff form revisions", err)
+		return
+	}
+
+	resp := DiffResponse{
+		From:    from,
+		To:      to,
+		Changes: changes,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) Rollback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+	number, err := parseRevisionNumber(r.PathValue("number"))
+	if err != nil {
+		h.logger.Warn("Invalid revision", zap.String("number", r.PathValue("number")))
+		http.Error(w, "Invalid revision", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.store.Rollback(ctx, formID, number)
+	if err != nil {
+		h.writeError(w, "Failed to roll back form", err)
+		return
+	}
+
+	resp := Response{
+		ID:          result.ID.String(),
+		Title:       result.Title,
+		Description: result.Description.String,
+		Status:      result.Status,
+		CreatedAt:   result.CreatedAt.Time,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", ETag(result))
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// SetQuiz turns quiz mode on or off. Like Update it honours If-Match.
+func (h *Handler) SetQuiz(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	var req QuizRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// validate request
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.logger.Error("Validation failed", zap.Error(err))
+		http.Error(w, "Validation failed", http.StatusBadRequest)
+		return
+	}
+	settings := QuizSettings{Enabled: req.IsQuiz, RevealAnswers: RevealPolicy(req.RevealAnswers)}
+	if settings.RevealAnswers == "" {
+		settings.RevealAnswers = RevealNever
+	}
+
+	version, err := ifMatch(r)
+	if err != nil {
+		h.writeError(w, "Failed to change quiz settings", err)
+		return
+	}
+	result, err := h.store.SetQuiz(ctx, formID, settings, version)
+	if err != nil {
+		h.writeError(w, "Failed to change quiz settings", err)
+		return
+	}
+
+	resp := Response{
+		ID:            result.ID.String(),
+		Title:         result.Title,
+		Description:   result.Description.String,
+		Status:        result.Status,
+		CreatedAt:     result.CreatedAt.Time,
+		IsQuiz:        result.IsQuiz,
+		RevealAnswers: result.RevealAnswers,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", ETag(result))
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// SetTemplate makes the form a template. Like Update it honours If-Match.
+func (h *Handler) SetTemplate(w http.ResponseWriter, r *http.Request) {
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	var req TemplateRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// validate request
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.logger.Error("Validation failed", zap.Error(err))
+		http.Error(w, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	h.setTemplate(w, r, formID, TemplateVisibility(req.Visibility))
+}
+
+// RemoveTemplate takes the form out of the template gallery. Forms created
+// from it are not affected.
+func (h *Handler) RemoveTemplate(w http.ResponseWriter, r *http.Request) {
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	h.setTemplate(w, r, formID, TemplateNone)
+}
+
+func (h *Handler) setTemplate(w http.ResponseWriter, r *http.Request, formID uuid.UUID, visibility TemplateVisibility) {
+	version, err := ifMatch(r)
+	if err != nil {
+		h.writeError(w, "Failed to change template visibility", err)
+		return
+	}
+	result, err := h.store.SetTemplate(r.Context(), formID, visibility, version)
+	if err != nil {
+		h.writeError(w, "Failed to change template visibility", err)
+		return
+	}
+
+	resp := Response{
+		ID:            result.ID.String(),
+		Title:         result.Title,
+		Description:   result.Description.String,
+		Status:        result.Status,
+		CreatedAt:     result.CreatedAt.Time,
+		IsQuiz:        result.IsQuiz,
+		RevealAnswers: result.RevealAnswers,
+		Template:      result.TemplateVisibility.String,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", ETag(result))
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// Templates lists the templates available to the caller, newest first.
+func (h *Handler) Templates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	page, err := pagination.ParseParams(r.URL.Query())
+	if err != nil {
+		h.logger.Warn("Invalid pagination", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	forms, next, err := h.store.ListTemplates(ctx, userID, page)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			h.logger.Warn("Invalid cursor", zap.Error(err))
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		h.logger.Error("Failed to list templates", zap.Error(err))
+		http.Error(w, "Failed to list templates", http.StatusInternalServerError)
+		return
+	}
+
+	resp := ListResponse{
+		Forms:      make([]Response, 0, len(forms)),
+		NextCursor: next,
+	}
+	for _, form := range forms {
+		resp.Forms = append(resp.Forms, Response{
+			ID:            form.ID.String(),
+			Title:         form.Title,
+			Description:   form.Description.String,
+			Status:        form.Status,
+			CreatedAt:     form.CreatedAt.Time,
+			IsQuiz:        form.IsQuiz,
+			RevealAnswers: form.RevealAnswers,
+			Template:      form.TemplateVisibility.String,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) InviteCollaborator(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	var req CollaboratorRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// validate request
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.logger.Error("Validation failed", zap.Error(err))
+		http.Error(w, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	collaborator, err := h.store.InviteCollaborator(ctx, formID, req.Email, Role(req.Role))
+	if err != nil {
+		h.writeError(w, "Failed to invite collaborator", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(newCollaboratorResponse(collaborator))
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) ListCollaborators(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	collaborators, err := h.store.ListCollaborators(ctx, formID)
+	if err != nil {
+		h.writeError(w, "Failed to list collaborators", err)
+		return
+	}
+
+	resp := make([]CollaboratorResponse, 0, len(collaborators))
+	for _, collaborator := range collaborators {
+		resp = append(resp, newCollaboratorResponse(collaborator))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) UpdateCollaborator(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateCollaboratorRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// validate request
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.logger.Error("Validation failed", zap.Error(err))
+		http.Error(w, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	collaborator, err := h.store.UpdateCollaborator(ctx, formID, r.PathValue("email"), Role(req.Role))
+	if err != nil {
+		h.writeError(w, "Failed to update collaborator", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(newCollaboratorResponse(collaborator))
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) RemoveCollaborator(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.store.RemoveCollaborator(ctx, formID, r.PathValue("email"))
+	if err != nil {
+		h.writeError(w, "Failed to remove collaborator", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, message string, err error) {
+	var problems *definition.ValidationError
+	switch {
+	case errors.As(err, &problems):
+		h.logger.Warn(message, zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		err = json.NewEncoder(w).Encode(DefinitionErrorResponse{Error: definition.ErrInvalid.Error(), Errors: problems.Errors})
+		if err != nil {
+			h.logger.Error("Failed to encode response", zap.Error(err))
+		}
+	case errors.Is(err, ErrNotFound):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, "Not found", http.StatusNotFound)
+	case errors.Is(err, ErrForbidden):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	case errors.Is(err, ErrInvalidQuestion), errors.Is(err, ErrInvalidRole), errors.Is(err, ErrInvalidRule), errors.Is(err, ErrInvalidQuiz),
+		errors.Is(err, ErrInvalidTemplate):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrConflict):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, "Already a collaborator", http.StatusConflict)
+	case errors.Is(err, ErrVersionMismatch):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+	case errors.Is(err, ErrReadOnly), errors.Is(err, ErrInvalidAction), errors.Is(err, ErrCopyConflict):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		h.logger.Error(message, zap.Error(err))
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+// parseListOptions reads the query of GET /api/forms:
+//
+//	limit, cursor                  page size and position, see pagination.ParseParams
+//	sort=created_at|title          ordering column, created_at by default
+//	order=asc|desc                 desc by default for created_at, asc for title
+//	author=me                      only forms created by the caller
+//	bookmarked=true                only forms bookmarked by the caller
+//	created_after, created_before  RFC 3339 timestamps or YYYY-MM-DD dates
+func parseListOptions(query url.Values) (ListOptions, error) {
+	page, err := pagination.ParseParams(query)
+	if err != nil {
+		return ListOptions{}, err
+	}
+
+	opts := ListOptions{
+		Page:   page,
+		SortBy: SortByCreatedAt,
+	}
+	if value := query.Get("sort"); value != "" {
+		opts.SortBy = SortField(value)
+		if !opts.SortBy.IsValid() {
+			return ListOptions{}, fmt.Errorf("invalid sort %q", value)
+		}
+	}
+
+	switch query.Get("order") {
+	case "":
+		opts.Descending = opts.SortBy == SortByCreatedAt
+	case "asc":
+		opts.Descending = false
+	case "desc":
+		opts.Descending = true
+	default:
+		return ListOptions{}, fmt.Errorf("invalid order %q", query.Get("order"))
+	}
+
+	switch query.Get("author") {
+	case "":
+	case "me":
+		opts.AuthorOnly = true
+	default:
+		return ListOptions{}, fmt.Errorf("invalid author %q", query.Get("author"))
+	}
+
+	if value := query.Get("bookmarked"); value != "" {
+		opts.Bookmarked, err = strconv.ParseBool(value)
+		if err != nil {
+			return ListOptions{}, fmt.Errorf("invalid bookmarked %q", value)
+		}
+	}
+
+	opts.CreatedAfter, err = parseTimeParam(query, "created_after")
+	if err != nil {
+		return ListOptions{}, err
+	}
+	opts.CreatedBefore, err = parseTimeParam(query, "created_before")
+	if err != nil {
+		return ListOptions{}, err
+	}
+
+	return opts, nil
+}
+
+func parseTimeParam(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s %q", name, value)
+}
+
+func sortedIDs(ids map[uuid.UUID]bool) []string {
+	result := make([]string, 0, len(ids))
+	for id := range ids {
+		result = append(result, id.String())
+	}
+	sort.Strings(result)
+	return result
+}
+
+func parseRevisionNumber(value string) (int32, error) {
+	number, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	if number < 1 {
+		return 0, fmt.Errorf("revision must be positive, got %d", number)
+	}
+	return int32(number), nil
+}
+
+func (req QuestionRequest) toInput() QuestionInput {
+	return QuestionInput{
+		Type:        QuestionType(req.Type),
+		Title:       req.Title,
+		Description: req.Description,
+		Required:    req.Required,
+		Position:    req.Position,
+		Settings:    req.Settings,
+		SectionID:   req.SectionID,
+		Rules:       req.Rules,
+		Points:      req.Points,
+		AnswerKey:   req.AnswerKey,
+	}
+}
+
+func (req SectionRequest) toInput() SectionInput {
+	return SectionInput{
+		Title:       req.Title,
+		Description: req.Description,
+		Position:    req.Position,
+		Rules:       req.Rules,
+	}
+}
+
+// NewQuestionResponse renders a question the way every handler returns it,
+// answer keys included when the question still has one.
+func NewQuestionResponse(question Question) (QuestionResponse, error) {
+	settings, err := question.ParseSettings()
+	if err != nil {
+		return QuestionResponse{}, err
+	}
+
+	rules, err := question.ParseRules()
+	if err != nil {
+		return QuestionResponse{}, err
+	}
+
+	resp := QuestionResponse{
+		ID:          question.ID.String(),
+		FormID:      question.FormID.String(),
+		Type:        question.Type,
+		Title:       question.Title,
+		Description: question.Description.String,
+		Required:    question.Required,
+		Position:    question.Position,
+		Settings:    settings,
+		Rules:       rules,
+		Points:      question.Points,
+		AnswerKey:   question.AnswerKey,
+		CreatedAt:   question.CreatedAt.Time,
+		UpdatedAt:   question.UpdatedAt.Time,
+	}
+	if question.SectionID.Valid {
+		sectionID := uuid.UUID(question.SectionID.Bytes).String()
+		resp.SectionID = &sectionID
+	}
+	return resp, nil
+}
+
+// NewSectionResponse renders a section the way every handler returns it.
+func NewSectionResponse(section FormSection) (SectionResponse, error) {
+	rules, err := section.ParseRules()
+	if err != nil {
+		return SectionResponse{}, err
+	}
+
+	return SectionResponse{
+		ID:          section.ID.String(),
+		FormID:      section.FormID.String(),
+		Title:       section.Title,
+		Description: section.Description.String,
+		Position:    section.Position,
+		Rules:       rules,
+		CreatedAt:   section.CreatedAt.Time,
+		UpdatedAt:   section.UpdatedAt.Time,
+	}, nil
+}
+
+func newCollaboratorResponse(collaborator FormCollaborator) CollaboratorResponse {
+	resp := CollaboratorResponse{
+		FormID:    collaborator.FormID.String(),
+		Email:     collaborator.Email,
+		Role:      collaborator.Role,
+		CreatedAt: collaborator.CreatedAt.Time,
+	}
+	if collaborator.UserID.Valid {
+		userID := uuid.UUID(collaborator.UserID.Bytes).String()
+		resp.UserID = &userID
+	}
+
+	return resp
+}
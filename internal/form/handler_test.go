@@ -4,6 +4,7 @@ import (
 	"awesomeProject/internal/form"
 	"awesomeProject/internal/form/mocks"
 	"awesomeProject/internal/jwt"
+	"awesomeProject/internal/pagination"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -11,6 +12,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -119,7 +121,7 @@ func TestHandler_Create(t *testing.T) {
 }
 
 func TestHandler_List(t *testing.T) {
-	forms := []form.Form{
+	forms := []form.ListWithBookmarksRow{
 		{
 			ID:          uuid.New(),
 			Title:       "title1",
@@ -143,6 +145,7 @@ func TestHandler_List(t *testing.T) {
 		name         string
 		formID       uuid.UUID
 		userID       uuid.UUID // ID of the user making the request
+		query        string
 		setMock      func(store *mocks.Store, formID uuid.UUID)
 		expectStatus int
 	}{
@@ -151,20 +154,57 @@ func TestHandler_List(t *testing.T) {
 			formID: uuid.New(),
 			userID: uuid.New(),
 			setMock: func(store *mocks.Store, formID uuid.UUID) {
-				store.On("IsBookmarked", mock.Anything, mock.Anything, mock.Anything).Return(
-					true, nil)
 				store.On("List", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
-					forms, nil)
+					forms, "", nil)
 			},
 			expectStatus: 200,
 		},
+		{
+			name:   "Filters and sort are passed to the store",
+			formID: uuid.New(),
+			userID: uuid.New(),
+			query:  "?limit=2&sort=title&order=desc&author=me&bookmarked=true&created_after=2024-01-01",
+			setMock: func(store *mocks.Store, formID uuid.UUID) {
+				store.On("List", mock.Anything, mock.Anything, mock.MatchedBy(func(opts form.ListOptions) bool {
+					return opts.Page.Limit == 2 && opts.SortBy == form.SortByTitle && opts.Descending &&
+						opts.AuthorOnly && opts.Bookmarked && opts.CreatedAfter.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+				})).Return(forms[:2], "next", nil)
+			},
+			expectStatus: 200,
+		},
+		{
+			name:         "Invalid limit",
+			formID:       uuid.New(),
+			userID:       uuid.New(),
+			query:        "?limit=0",
+			setMock:      func(store *mocks.Store, formID uuid.UUID) {},
+			expectStatus: 400,
+		},
+		{
+			name:         "Invalid sort",
+			formID:       uuid.New(),
+			userID:       uuid.New(),
+			query:        "?sort=author",
+			setMock:      func(store *mocks.Store, formID uuid.UUID) {},
+			expectStatus: 400,
+		},
+		{
+			name:   "Cursor from another ordering",
+			formID: uuid.New(),
+			userID: uuid.New(),
+			setMock: func(store *mocks.Store, formID uuid.UUID) {
+				store.On("List", mock.Anything, mock.Anything, mock.Anything).Return(
+					nil, "", pagination.ErrInvalidCursor)
+			},
+			expectStatus: 400,
+		},
 		{
 			name:   "database error",
 			formID: uuid.New(),
 			userID: uuid.New(),
 			setMock: func(store *mocks.Store, formID uuid.UUID) {
 				store.On("List", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
-					[]form.Form{}, errors.New("database error"))
+					[]form.ListWithBookmarksRow{}, "", errors.New("database error"))
 			},
 			expectStatus: 500,
 		},
@@ -178,7 +218,7 @@ func TestHandler_List(t *testing.T) {
 
 			handler := form.NewHandler(logger, v, store)
 			var rawBody []byte
-			r := httptest.NewRequest(http.MethodGet, "/api/forms", bytes.NewBuffer(rawBody))
+			r := httptest.NewRequest(http.MethodGet, "/api/forms"+tt.query, bytes.NewBuffer(rawBody))
 			w := httptest.NewRecorder()
 
 			r = r.WithContext(context.WithValue(r.Context(), jwt.UserContextKey, tt.userID))
@@ -198,8 +238,10 @@ func TestHandler_Update(t *testing.T) {
 		userID       uuid.UUID          // ID of the user making the request
 		reqBody      form.UpdateRequest // Customize based on actual request structure
 		customBody   []byte             // Optional raw body for more complex cases
+		ifMatch      string
 		setMock      func(store *mocks.Store, formID uuid.UUID)
 		expectStatus int
+		expectETag   string
 	}{
 		{
 			name:   "Successful form update",
@@ -211,7 +253,7 @@ func TestHandler_Update(t *testing.T) {
 				Description: "This is an updated form",
 			},
 			setMock: func(store *mocks.Store, formID uuid.UUID) {
-				store.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(form.Form{
+				store.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(form.Form{
 					ID:          testformID,
 					Title:       "Update Form",
 					Description: pgtype.Text{String: "This is an updated form", Valid: true},
@@ -239,10 +281,72 @@ func TestHandler_Update(t *testing.T) {
 				Description: "This is a test form",
 			},
 			setMock: func(store *mocks.Store, formID uuid.UUID) {
-				store.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(form.Form{}, errors.New("database error"))
+				store.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(form.Form{}, errors.New("database error"))
 			},
 			expectStatus: 500,
 		},
+		{
+			name:   "Not the author of the form",
+			formID: uuid.New(),
+			userID: uuid.New(),
+			reqBody: form.UpdateRequest{
+				ID:          testformID.String(),
+				Title:       "Test Form",
+				Description: "This is a test form",
+			},
+			setMock: func(store *mocks.Store, formID uuid.UUID) {
+				store.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(form.Form{}, form.ErrForbidden)
+			},
+			expectStatus: 403,
+		},
+		{
+			name:   "Update with a matching If-Match",
+			formID: uuid.New(),
+			userID: uuid.New(),
+			reqBody: form.UpdateRequest{
+				ID:          testformID.String(),
+				Title:       "Update Form",
+				Description: "This is an updated form",
+			},
+			ifMatch: `"3"`,
+			setMock: func(store *mocks.Store, formID uuid.UUID) {
+				store.On("Update", mock.Anything, testformID, "Update Form", "This is an updated form", ptr(int32(3))).Return(form.Form{
+					ID:      testformID,
+					Title:   "Update Form",
+					Version: 4,
+				}, nil)
+			},
+			expectStatus: 200,
+			expectETag:   `"4"`,
+		},
+		{
+			name:   "Stale If-Match",
+			formID: uuid.New(),
+			userID: uuid.New(),
+			reqBody: form.UpdateRequest{
+				ID:          testformID.String(),
+				Title:       "Update Form",
+				Description: "This is an updated form",
+			},
+			ifMatch: `"2"`,
+			setMock: func(store *mocks.Store, formID uuid.UUID) {
+				store.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(form.Form{}, form.ErrVersionMismatch)
+			},
+			expectStatus: 412,
+		},
+		{
+			name:   "Weak If-Match never matches",
+			formID: uuid.New(),
+			userID: uuid.New(),
+			reqBody: form.UpdateRequest{
+				ID:          testformID.String(),
+				Title:       "Update Form",
+				Description: "This is an updated form",
+			},
+			ifMatch:      `W/"3"`,
+			setMock:      func(store *mocks.Store, formID uuid.UUID) {},
+			expectStatus: 412,
+		},
 		{
 			name:   "Invalid description type",
 			formID: uuid.New(),
@@ -281,11 +385,191 @@ func TestHandler_Update(t *testing.T) {
 			}
 
 			r := httptest.NewRequest(http.MethodPost, "/api/forms", bytes.NewBuffer(rawBody))
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
 			w := httptest.NewRecorder()
 
 			r = r.WithContext(context.WithValue(r.Context(), jwt.UserContextKey, tt.userID))
 			handler.Update(w, r)
 
+			assert.Equalf(t, tt.expectStatus, w.Result().StatusCode, "Expected status code to match, Expected %d, got %d", tt.expectStatus, w.Result().StatusCode)
+			if tt.expectETag != "" {
+				assert.Equal(t, tt.expectETag, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestHandler_Get(t *testing.T) {
+	testFormID := uuid.New()
+	tests := []struct {
+		name         string
+		ifNoneMatch  string
+		setMock      func(store *mocks.Store)
+		expectStatus int
+	}{
+		{
+			name: "Returns the form with its ETag",
+			setMock: func(store *mocks.Store) {
+				store.On("Visible", mock.Anything, testFormID).Return(form.Form{ID: testFormID, Version: 5}, nil)
+			},
+			expectStatus: 200,
+		},
+		{
+			name:        "Unchanged form is not modified",
+			ifNoneMatch: `W/"4", "5"`,
+			setMock: func(store *mocks.Store) {
+				store.On("Visible", mock.Anything, testFormID).Return(form.Form{ID: testFormID, Version: 5}, nil)
+			},
+			expectStatus: 304,
+		},
+		{
+			name:        "Changed form is returned",
+			ifNoneMatch: `"4"`,
+			setMock: func(store *mocks.Store) {
+				store.On("Visible", mock.Anything, testFormID).Return(form.Form{ID: testFormID, Version: 5}, nil)
+			},
+			expectStatus: 200,
+		},
+		{
+			name: "Form does not exist",
+			setMock: func(store *mocks.Store) {
+				store.On("Visible", mock.Anything, testFormID).Return(form.Form{}, form.ErrNotFound)
+			},
+			expectStatus: 404,
+		},
+	}
+
+	logger := zaptest.NewLogger(t)
+	v := validator.New()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := mocks.NewStore(t)
+			tt.setMock(store)
+
+			handler := form.NewHandler(logger, v, store)
+
+			r := httptest.NewRequest(http.MethodGet, "/api/forms/"+testFormID.String(), nil)
+			r.SetPathValue("id", testFormID.String())
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+
+			r = r.WithContext(context.WithValue(r.Context(), jwt.UserContextKey, uuid.New()))
+			handler.Get(w, r)
+
+			assert.Equal(t, tt.expectStatus, w.Result().StatusCode)
+			if tt.expectStatus == 200 || tt.expectStatus == 304 {
+				assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestHandler_CreateQuestion(t *testing.T) {
+	testFormID := uuid.New()
+	tests := []struct {
+		name         string
+		formID       string
+		userID       uuid.UUID            // ID of the user making the request
+		reqBody      form.QuestionRequest // Customize based on actual request structure
+		customBody   []byte               // Optional raw body for more complex cases
+		setMock      func(store *mocks.Store)
+		expectStatus int
+	}{
+		{
+			name:   "Successful question creation",
+			formID: testFormID.String(),
+			userID: uuid.New(),
+			reqBody: form.QuestionRequest{
+				Type:     "single_choice",
+				Title:    "Favourite colour",
+				Settings: form.QuestionSettings{Choices: []string{"red", "blue"}},
+			},
+			setMock: func(store *mocks.Store) {
+				store.On("CreateQuestion", mock.Anything, testFormID, mock.Anything).Return(form.Question{
+					ID:       uuid.New(),
+					FormID:   testFormID,
+					Type:     "single_choice",
+					Title:    "Favourite colour",
+					Settings: []byte(`{"choices":["red","blue"]}`),
+				}, nil)
+			},
+			expectStatus: 201,
+		},
+		{
+			name:   "Unknown question type",
+			formID: testFormID.String(),
+			userID: uuid.New(),
+			reqBody: form.QuestionRequest{
+				Type:  "checkbox_grid",
+				Title: "Unsupported",
+			},
+			setMock:      func(store *mocks.Store) {},
+			expectStatus: 400,
+		},
+		{
+			name:   "Invalid settings",
+			formID: testFormID.String(),
+			userID: uuid.New(),
+			reqBody: form.QuestionRequest{
+				Type:  "single_choice",
+				Title: "No choices",
+			},
+			setMock: func(store *mocks.Store) {
+				store.On("CreateQuestion", mock.Anything, testFormID, mock.Anything).Return(form.Question{}, form.ErrInvalidQuestion)
+			},
+			expectStatus: 400,
+		},
+		{
+			name:   "Form not found",
+			formID: testFormID.String(),
+			userID: uuid.New(),
+			reqBody: form.QuestionRequest{
+				Type:  "short_text",
+				Title: "Name",
+			},
+			setMock: func(store *mocks.Store) {
+				store.On("CreateQuestion", mock.Anything, testFormID, mock.Anything).Return(form.Question{}, form.ErrNotFound)
+			},
+			expectStatus: 404,
+		},
+		{
+			name:         "Invalid form ID",
+			formID:       "not-a-uuid",
+			userID:       uuid.New(),
+			customBody:   []byte(`{"type":"short_text","title":"Name"}`),
+			setMock:      func(store *mocks.Store) {},
+			expectStatus: 400,
+		},
+	}
+
+	logger := zaptest.NewLogger(t)
+	v := validator.New()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := mocks.NewStore(t)
+			tt.setMock(store)
+
+			handler := form.NewHandler(logger, v, store)
+			var rawBody []byte
+			if tt.customBody != nil {
+				rawBody = tt.customBody
+			} else {
+				rawBody, _ = json.Marshal(tt.reqBody)
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/api/forms/"+tt.formID+"/questions", bytes.NewBuffer(rawBody))
+			r.SetPathValue("id", tt.formID)
+			w := httptest.NewRecorder()
+
+			r = r.WithContext(context.WithValue(r.Context(), jwt.UserContextKey, tt.userID))
+			handler.CreateQuestion(w, r)
+
 			assert.Equalf(t, tt.expectStatus, w.Result().StatusCode, "Expected status code to match, Expected %d, got %d", tt.expectStatus, w.Result().StatusCode)
 		})
 	}
@@ -0,0 +1,72 @@
+package form
+
+import (
+	"awesomeProject/internal/pagination"
+	"fmt"
+	"time"
+)
+
+type SortField string
+
+const (
+	SortByCreatedAt SortField = "created_at"
+	SortByTitle     SortField = "title"
+)
+
+func (f SortField) IsValid() bool {
+	return f == SortByCreatedAt || f == SortByTitle
+}
+
+// ListOptions selects the page, ordering and filters used by Service.List.
+// Zero times mean the created range is open on that side.
+type ListOptions struct {
+	Page          pagination.Params
+	SortBy        SortField
+	Descending    bool
+	AuthorOnly    bool
+	Bookmarked    bool
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// sort identifies the ordering in cursors, so a cursor from one ordering is
+// rejected by another.
+func (o ListOptions) sort() string {
+	if o.Descending {
+		return string(o.SortBy) + ":desc"
+	}
+	return string(o.SortBy) + ":asc"
+}
+
+func (o ListOptions) cursorOf(f ListWithBookmarksRow) pagination.Cursor {
+	cursor := pagination.Cursor{Sort: o.sort(), ID: f.ID}
+	if o.SortBy == SortByTitle {
+		cursor.Key = f.Title
+	} else {
+		cursor.Key = f.CreatedAt.Time.Format(time.RFC3339Nano)
+	}
+	return cursor
+}
+
+// applyCursor fills the keyset columns of the query from the page cursor.
+func (o ListOptions) applyCursor(params *ListWithBookmarksParams) error {
+	cursor, err := o.Page.CursorFor(o.sort())
+	if err != nil || cursor == nil {
+		return err
+	}
+
+	params.CursorID.Bytes = cursor.ID
+	params.CursorID.Valid = true
+	if o.SortBy == SortByTitle {
+		params.CursorTitle = cursor.Key
+		return nil
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, cursor.Key)
+	if err != nil {
+		return fmt.Errorf("%w: %v", pagination.ErrInvalidCursor, err)
+	}
+	params.CursorCreatedAt.Time = createdAt
+	params.CursorCreatedAt.Valid = true
+	return nil
+}
@@ -0,0 +1,102 @@
+package logic
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MessageRequired = "answer is required"
+	MessageHidden   = "question is not shown for these answers"
+)
+
+// Result is the path a respondent takes through a form for a set of answers.
+type Result struct {
+	// Path holds the IDs of the sections the respondent goes through, in order.
+	Path []uuid.UUID
+	// Shown holds the questions the respondent is shown.
+	Shown map[uuid.UUID]bool
+	// Required holds the shown questions that must be answered.
+	Required map[uuid.UUID]bool
+}
+
+// Evaluate walks the sections of the form in order and applies their rules.
+// Conditions only see the answers to questions that were shown before them,
+// so an answer to a hidden or skipped question never changes the path.
+// answers must only contain questions that were actually answered.
+//
+// Rules are expected to have passed Validate. Jumps that do not go forward
+// are ignored rather than looping.
+func Evaluate(f Form, answers map[uuid.UUID]json.RawMessage) Result {
+	result := Result{
+		Shown:    make(map[uuid.UUID]bool),
+		Required: make(map[uuid.UUID]bool),
+	}
+	sections := make(map[string]int, len(f.Sections))
+	for i, section := range f.Sections {
+		sections[section.ID.String()] = i
+	}
+	seen := make(map[uuid.UUID]json.RawMessage)
+
+	for i := 0; i < len(f.Sections); {
+		section := f.Sections[i]
+		if section.Rules.ShowIf != nil && !section.Rules.ShowIf.Holds(seen) {
+			i++
+			continue
+		}
+		result.Path = append(result.Path, section.ID)
+
+		for _, question := range section.Questions {
+			rules := question.Rules
+			if rules.ShowIf != nil && !rules.ShowIf.Holds(seen) {
+				continue
+			}
+			if rules.HideIf != nil && rules.HideIf.Holds(seen) {
+				continue
+			}
+			result.Shown[question.ID] = true
+			if question.Required || (rules.RequireIf != nil && rules.RequireIf.Holds(seen)) {
+				result.Required[question.ID] = true
+			}
+			if answer, ok := answers[question.ID]; ok {
+				seen[question.ID] = answer
+			}
+		}
+
+		next := i + 1
+		for _, jump := range section.Rules.Jumps {
+			if jump.If != nil && !jump.If.Holds(seen) {
+				continue
+			}
+			if jump.To == End {
+				next = len(f.Sections)
+			} else if target, ok := sections[jump.To]; ok && target > i {
+				next = target
+			}
+			break
+		}
+		i = next
+	}
+
+	return result
+}
+
+// Problems lists the questions whose answers do not fit the result: shown
+// required questions without an answer and answers to questions that were
+// not shown. Questions that are not part of the form are left to the caller.
+func (r Result) Problems(f Form, answers map[uuid.UUID]json.RawMessage) map[uuid.UUID]string {
+	problems := make(map[uuid.UUID]string)
+	for _, section := range f.Sections {
+		for _, question := range section.Questions {
+			_, answered := answers[question.ID]
+			switch {
+			case answered && !r.Shown[question.ID]:
+				problems[question.ID] = MessageHidden
+			case !answered && r.Required[question.ID]:
+				problems[question.ID] = MessageRequired
+			}
+		}
+	}
+	return problems
+}
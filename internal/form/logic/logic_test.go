@@ -0,0 +1,260 @@
+package logic_test
+
+import (
+	"awesomeProject/internal/form/logic"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func equals(question uuid.UUID, value string) *logic.Condition {
+	return &logic.Condition{Question: question, Op: logic.OpEquals, Value: json.RawMessage(value)}
+}
+
+func TestEvaluate(t *testing.T) {
+	q1, q2, q3, q4, q5 := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	s1, s2, s3 := uuid.New(), uuid.New(), uuid.New()
+
+	// Q1 "Do you own a car?" jumps past the car section when the answer is No.
+	// Q3 is only shown to car owners who answered Q2 with more than 1 car.
+	survey := logic.Form{Sections: []logic.Section{
+		{
+			ID:        s1,
+			Questions: []logic.Question{{ID: q1, Required: true}},
+			Rules: logic.SectionRules{Jumps: []logic.Jump{
+				{If: equals(q1, `"No"`), To: s3.String()},
+			}},
+		},
+		{
+			ID: s2,
+			Questions: []logic.Question{
+				{ID: q2, Required: true},
+				{ID: q3, Rules: logic.QuestionRules{
+					ShowIf:    &logic.Condition{Question: q2, Op: logic.OpGreaterThan, Value: json.RawMessage(`1`)},
+					RequireIf: &logic.Condition{Question: q2, Op: logic.OpGreaterThan, Value: json.RawMessage(`2`)},
+				}},
+			},
+		},
+		{
+			ID: s3,
+			Questions: []logic.Question{
+				{ID: q4, Rules: logic.QuestionRules{
+					HideIf: &logic.Condition{Any: []logic.Condition{
+						*equals(q1, `"No"`),
+						{Question: q3, Op: logic.OpNotAnswered},
+					}},
+				}},
+				{ID: q5, Rules: logic.QuestionRules{
+					RequireIf: &logic.Condition{Question: q4, Op: logic.OpContains, Value: json.RawMessage(`"other"`)},
+				}},
+			},
+		},
+	}}
+
+	tests := []struct {
+		name           string
+		answers        map[uuid.UUID]json.RawMessage
+		expectPath     []uuid.UUID
+		expectShown    []uuid.UUID
+		expectRequired []uuid.UUID
+		expectProblems map[uuid.UUID]string
+	}{
+		{
+			name:           "No answers",
+			answers:        map[uuid.UUID]json.RawMessage{},
+			expectPath:     []uuid.UUID{s1, s2, s3},
+			expectShown:    []uuid.UUID{q1, q2, q5},
+			expectRequired: []uuid.UUID{q1, q2},
+			expectProblems: map[uuid.UUID]string{q1: logic.MessageRequired, q2: logic.MessageRequired},
+		},
+		{
+			name:           "Jump skips a section",
+			answers:        map[uuid.UUID]json.RawMessage{q1: json.RawMessage(`"No"`)},
+			expectPath:     []uuid.UUID{s1, s3},
+			expectShown:    []uuid.UUID{q1, q5},
+			expectRequired: []uuid.UUID{q1},
+			expectProblems: map[uuid.UUID]string{},
+		},
+		{
+			name: "Answers in a skipped section are rejected",
+			answers: map[uuid.UUID]json.RawMessage{
+				q1: json.RawMessage(`"No"`),
+				q2: json.RawMessage(`3`),
+			},
+			expectPath:     []uuid.UUID{s1, s3},
+			expectShown:    []uuid.UUID{q1, q5},
+			expectRequired: []uuid.UUID{q1},
+			expectProblems: map[uuid.UUID]string{q2: logic.MessageHidden},
+		},
+		{
+			name: "Shown and required depend on earlier answers",
+			answers: map[uuid.UUID]json.RawMessage{
+				q1: json.RawMessage(`"Yes"`),
+				q2: json.RawMessage(`3`),
+			},
+			expectPath:     []uuid.UUID{s1, s2, s3},
+			expectShown:    []uuid.UUID{q1, q2, q3, q5},
+			expectRequired: []uuid.UUID{q1, q2, q3},
+			expectProblems: map[uuid.UUID]string{q3: logic.MessageRequired},
+		},
+		{
+			name: "Contains on a multiple choice answer",
+			answers: map[uuid.UUID]json.RawMessage{
+				q1: json.RawMessage(`"Yes"`),
+				q2: json.RawMessage(`2`),
+				q3: json.RawMessage(`"Sedan"`),
+				q4: json.RawMessage(`["red", "other"]`),
+			},
+			expectPath:     []uuid.UUID{s1, s2, s3},
+			expectShown:    []uuid.UUID{q1, q2, q3, q4, q5},
+			expectRequired: []uuid.UUID{q1, q2, q5},
+			expectProblems: map[uuid.UUID]string{q5: logic.MessageRequired},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := logic.Evaluate(survey, tt.answers)
+
+			assert.Equal(t, tt.expectPath, result.Path)
+			assert.Equal(t, set(tt.expectShown), result.Shown)
+			assert.Equal(t, set(tt.expectRequired), result.Required)
+			assert.Equal(t, tt.expectProblems, result.Problems(survey, tt.answers))
+		})
+	}
+}
+
+func TestCondition_Holds(t *testing.T) {
+	q := uuid.New()
+	tests := []struct {
+		name      string
+		condition logic.Condition
+		answer    string // empty when the question is not answered
+		expect    bool
+	}{
+		{"equals string", logic.Condition{Question: q, Op: logic.OpEquals, Value: json.RawMessage(`"No"`)}, `"No"`, true},
+		{"equals number ignores formatting", logic.Condition{Question: q, Op: logic.OpEquals, Value: json.RawMessage(`3`)}, `3.0`, true},
+		{"equals unanswered", logic.Condition{Question: q, Op: logic.OpEquals, Value: json.RawMessage(`"No"`)}, ``, false},
+		{"not_equals unanswered", logic.Condition{Question: q, Op: logic.OpNotEquals, Value: json.RawMessage(`"No"`)}, ``, true},
+		{"in", logic.Condition{Question: q, Op: logic.OpIn, Value: json.RawMessage(`["a", "b"]`)}, `"b"`, true},
+		{"contains text", logic.Condition{Question: q, Op: logic.OpContains, Value: json.RawMessage(`"car"`)}, `"my car"`, true},
+		{"gt dates", logic.Condition{Question: q, Op: logic.OpGreaterThan, Value: json.RawMessage(`"2024-01-01"`)}, `"2024-06-30"`, true},
+		{"lt number", logic.Condition{Question: q, Op: logic.OpLessThan, Value: json.RawMessage(`18`)}, `21`, false},
+		{"gt mixed types", logic.Condition{Question: q, Op: logic.OpGreaterThan, Value: json.RawMessage(`1`)}, `"2"`, false},
+		{"answered", logic.Condition{Question: q, Op: logic.OpAnswered}, `""`, true},
+		{"not", logic.Condition{Not: &logic.Condition{Question: q, Op: logic.OpAnswered}}, ``, true},
+		{"all", logic.Condition{All: []logic.Condition{
+			{Question: q, Op: logic.OpAnswered},
+			{Question: q, Op: logic.OpEquals, Value: json.RawMessage(`"x"`)},
+		}}, `"y"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answers := map[uuid.UUID]json.RawMessage{}
+			if tt.answer != "" {
+				answers[q] = json.RawMessage(tt.answer)
+			}
+			assert.Equal(t, tt.expect, tt.condition.Holds(answers))
+		})
+	}
+}
+
+func TestForm_Validate(t *testing.T) {
+	q1, q2 := uuid.New(), uuid.New()
+	s1, s2 := uuid.New(), uuid.New()
+	tests := []struct {
+		name        string
+		form        logic.Form
+		expectError bool
+	}{
+		{
+			name: "Valid rules",
+			form: logic.Form{Sections: []logic.Section{
+				{ID: s1, Questions: []logic.Question{{ID: q1}}, Rules: logic.SectionRules{Jumps: []logic.Jump{
+					{If: equals(q1, `"No"`), To: logic.End},
+					{To: s2.String()},
+				}}},
+				{ID: s2, Questions: []logic.Question{{ID: q2, Rules: logic.QuestionRules{ShowIf: equals(q1, `"Yes"`)}}}},
+			}},
+		},
+		{
+			name: "Condition refers to a later question",
+			form: logic.Form{Sections: []logic.Section{
+				{ID: s1, Questions: []logic.Question{
+					{ID: q1, Rules: logic.QuestionRules{ShowIf: equals(q2, `"Yes"`)}},
+					{ID: q2},
+				}},
+			}},
+			expectError: true,
+		},
+		{
+			name: "Condition refers to the question itself",
+			form: logic.Form{Sections: []logic.Section{
+				{ID: s1, Questions: []logic.Question{
+					{ID: q1, Rules: logic.QuestionRules{RequireIf: &logic.Condition{Question: q1, Op: logic.OpAnswered}}},
+				}},
+			}},
+			expectError: true,
+		},
+		{
+			name: "Jump backwards",
+			form: logic.Form{Sections: []logic.Section{
+				{ID: s1, Questions: []logic.Question{{ID: q1}}},
+				{ID: s2, Rules: logic.SectionRules{Jumps: []logic.Jump{{To: s1.String()}}}},
+			}},
+			expectError: true,
+		},
+		{
+			name: "Jump to an unknown section",
+			form: logic.Form{Sections: []logic.Section{
+				{ID: s1, Rules: logic.SectionRules{Jumps: []logic.Jump{{To: uuid.NewString()}}}},
+			}},
+			expectError: true,
+		},
+		{
+			name: "Unknown operator",
+			form: logic.Form{Sections: []logic.Section{
+				{ID: s1, Questions: []logic.Question{
+					{ID: q1},
+					{ID: q2, Rules: logic.QuestionRules{ShowIf: &logic.Condition{Question: q1, Op: "matches", Value: json.RawMessage(`"x"`)}}},
+				}},
+			}},
+			expectError: true,
+		},
+		{
+			name: "Condition with question and all",
+			form: logic.Form{Sections: []logic.Section{
+				{ID: s1, Questions: []logic.Question{
+					{ID: q1},
+					{ID: q2, Rules: logic.QuestionRules{ShowIf: &logic.Condition{
+						Question: q1, Op: logic.OpAnswered,
+						All: []logic.Condition{{Question: q1, Op: logic.OpAnswered}},
+					}}},
+				}},
+			}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.form.Validate()
+			if tt.expectError {
+				assert.ErrorIs(t, err, logic.ErrInvalidRule)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func set(ids []uuid.UUID) map[uuid.UUID]bool {
+	result := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result
+}
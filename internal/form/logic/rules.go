@@ -0,0 +1,318 @@
+// Package logic evaluates the conditional rules of a form: which sections a
+// respondent goes through, which questions they are shown and which of those
+// they have to answer. It only knows about question IDs and raw JSON answers,
+// so it can be used and tested without a database.
+package logic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidRule = errors.New("invalid rule")
+
+// Op compares the answer to a question with the value of a condition.
+type Op string
+
+const (
+	// OpAnswered and OpNotAnswered take no value.
+	OpAnswered    Op = "answered"
+	OpNotAnswered Op = "not_answered"
+	// OpEquals compares the whole answer, OpNotEquals also holds when the
+	// question is not answered.
+	OpEquals    Op = "equals"
+	OpNotEquals Op = "not_equals"
+	// OpIn holds when the answer equals one of the values of an array.
+	OpIn Op = "in"
+	// OpContains holds when a multiple choice answer includes the value or a
+	// text answer contains it.
+	OpContains Op = "contains"
+	// OpGreaterThan and OpLessThan compare numbers, or strings such as
+	// dates formatted as YYYY-MM-DD.
+	OpGreaterThan Op = "gt"
+	OpLessThan    Op = "lt"
+)
+
+// End is the jump target that skips all remaining sections.
+const End = "end"
+
+// Condition is either a comparison of one answer or a combination of other
+// conditions, for example
+//
+//	{"question": "<id>", "op": "equals", "value": "No"}
+//	{"any": [{...}, {...}]}
+//	{"not": {...}}
+type Condition struct {
+	Question uuid.UUID       `json:"question,omitempty"`
+	Op       Op              `json:"op,omitempty"`
+	Value    json.RawMessage `json:"value,omitempty"`
+	All      []Condition     `json:"all,omitempty"`
+	Any      []Condition     `json:"any,omitempty"`
+	Not      *Condition      `json:"not,omitempty"`
+}
+
+// QuestionRules decide whether a question is shown and whether it must be
+// answered. A question with both ShowIf and HideIf is shown when ShowIf holds
+// and HideIf does not.
+type QuestionRules struct {
+	ShowIf    *Condition `json:"show_if,omitempty"`
+	HideIf    *Condition `json:"hide_if,omitempty"`
+	RequireIf *Condition `json:"require_if,omitempty"`
+}
+
+// SectionRules decide whether a section is entered and where to continue
+// after it. The first jump whose condition holds wins; a jump without a
+// condition always applies. Without a matching jump the next section follows.
+type SectionRules struct {
+	ShowIf *Condition `json:"show_if,omitempty"`
+	Jumps  []Jump     `json:"jumps,omitempty"`
+}
+
+// Jump continues with the section whose ID is To, or with the end of the
+// form when To is End. Jumps only go forward.
+type Jump struct {
+	If *Condition `json:"if,omitempty"`
+	To string     `json:"to"`
+}
+
+type Question struct {
+	ID       uuid.UUID
+	Required bool
+	Rules    QuestionRules
+}
+
+type Section struct {
+	ID        uuid.UUID
+	Rules     SectionRules
+	Questions []Question
+}
+
+// Form is the ordered structure of a form as seen by the evaluator.
+type Form struct {
+	Sections []Section
+}
+
+// Validate checks that every rule of the form is well-formed, that
+// conditions only refer to questions that come before the rule and that
+// jumps only go forward.
+func (f Form) Validate() error {
+	earlier := make(map[uuid.UUID]bool)
+	sections := make(map[string]int, len(f.Sections))
+	for i, section := range f.Sections {
+		sections[section.ID.String()] = i
+	}
+
+	for i, section := range f.Sections {
+		if section.Rules.ShowIf != nil {
+			err := section.Rules.ShowIf.validate(earlier)
+			if err != nil {
+				return fmt.Errorf("section %s show_if: %w", section.ID, err)
+			}
+		}
+
+		for _, question := range section.Questions {
+			rules := []struct {
+				name      string
+				condition *Condition
+			}{
+				{"show_if", question.Rules.ShowIf},
+				{"hide_if", question.Rules.HideIf},
+				{"require_if", question.Rules.RequireIf},
+			}
+			for _, rule := range rules {
+				if rule.condition == nil {
+					continue
+				}
+				err := rule.condition.validate(earlier)
+				if err != nil {
+					return fmt.Errorf("question %s %s: %w", question.ID, rule.name, err)
+				}
+			}
+			earlier[question.ID] = true
+		}
+
+		for j, jump := range section.Rules.Jumps {
+			if jump.If != nil {
+				err := jump.If.validate(earlier)
+				if err != nil {
+					return fmt.Errorf("section %s jump %d: %w", section.ID, j+1, err)
+				}
+			}
+			if jump.To == End {
+				continue
+			}
+			target, ok := sections[jump.To]
+			if !ok {
+				return fmt.Errorf("section %s jump %d: %w: unknown section %q", section.ID, j+1, ErrInvalidRule, jump.To)
+			}
+			if target <= i {
+				return fmt.Errorf("section %s jump %d: %w: can only jump to a later section", section.ID, j+1, ErrInvalidRule)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c Condition) validate(earlier map[uuid.UUID]bool) error {
+	forms := 0
+	if c.Op != "" || c.Question != uuid.Nil {
+		forms++
+	}
+	if c.All != nil {
+		forms++
+	}
+	if c.Any != nil {
+		forms++
+	}
+	if c.Not != nil {
+		forms++
+	}
+	if forms != 1 {
+		return fmt.Errorf("%w: a condition needs exactly one of question, all, any or not", ErrInvalidRule)
+	}
+
+	for _, nested := range c.All {
+		err := nested.validate(earlier)
+		if err != nil {
+			return err
+		}
+	}
+	for _, nested := range c.Any {
+		err := nested.validate(earlier)
+		if err != nil {
+			return err
+		}
+	}
+	if c.Not != nil {
+		return c.Not.validate(earlier)
+	}
+	if c.All != nil || c.Any != nil {
+		return nil
+	}
+
+	if !earlier[c.Question] {
+		return fmt.Errorf("%w: question %s does not come before this rule", ErrInvalidRule, c.Question)
+	}
+
+	switch c.Op {
+	case OpAnswered, OpNotAnswered:
+		if len(c.Value) > 0 {
+			return fmt.Errorf("%w: %s takes no value", ErrInvalidRule, c.Op)
+		}
+	case OpEquals, OpNotEquals, OpContains, OpGreaterThan, OpLessThan:
+		if len(c.Value) == 0 {
+			return fmt.Errorf("%w: %s needs a value", ErrInvalidRule, c.Op)
+		}
+	case OpIn:
+		var values []json.RawMessage
+		if err := json.Unmarshal(c.Value, &values); err != nil {
+			return fmt.Errorf("%w: in needs an array of values", ErrInvalidRule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown operator %q", ErrInvalidRule, c.Op)
+	}
+
+	return nil
+}
+
+// Holds evaluates the condition against answers. A missing key means the
+// question was not answered.
+func (c Condition) Holds(answers map[uuid.UUID]json.RawMessage) bool {
+	switch {
+	case c.All != nil:
+		for _, nested := range c.All {
+			if !nested.Holds(answers) {
+				return false
+			}
+		}
+		return true
+	case c.Any != nil:
+		for _, nested := range c.Any {
+			if nested.Holds(answers) {
+				return true
+			}
+		}
+		return false
+	case c.Not != nil:
+		return !c.Not.Holds(answers)
+	}
+
+	answer, answered := answers[c.Question]
+	switch c.Op {
+	case OpAnswered:
+		return answered
+	case OpNotAnswered:
+		return !answered
+	case OpNotEquals:
+		return !answered || !equal(answer, c.Value)
+	}
+	if !answered {
+		return false
+	}
+
+	switch c.Op {
+	case OpEquals:
+		return equal(answer, c.Value)
+	case OpIn:
+		var values []json.RawMessage
+		_ = json.Unmarshal(c.Value, &values)
+		for _, value := range values {
+			if equal(answer, value) {
+				return true
+			}
+		}
+	case OpContains:
+		var choices []json.RawMessage
+		if json.Unmarshal(answer, &choices) == nil {
+			for _, choice := range choices {
+				if equal(choice, c.Value) {
+					return true
+				}
+			}
+			return false
+		}
+		var text, part string
+		if json.Unmarshal(answer, &text) == nil && json.Unmarshal(c.Value, &part) == nil {
+			return strings.Contains(text, part)
+		}
+	case OpGreaterThan:
+		return compare(answer, c.Value) > 0
+	case OpLessThan:
+		return compare(answer, c.Value) < 0
+	}
+	return false
+}
+
+func equal(a, b json.RawMessage) bool {
+	var left, right any
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// compare orders two numbers or two strings. Values that cannot be compared
+// report 0, so neither gt nor lt holds for them.
+func compare(a, b json.RawMessage) int {
+	var x, y float64
+	if json.Unmarshal(a, &x) == nil && json.Unmarshal(b, &y) == nil {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	var s, t string
+	if json.Unmarshal(a, &s) == nil && json.Unmarshal(b, &t) == nil {
+		return strings.Compare(s, t)
+	}
+	return 0
+}
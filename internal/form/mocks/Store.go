@@ -4,7 +4,11 @@ package mocks
 
 import (
 	form "awesomeProject/internal/form"
+	definition "awesomeProject/internal/form/definition"
+	logic "awesomeProject/internal/form/logic"
+	pagination "awesomeProject/internal/pagination"
 	context "context"
+	json "encoding/json"
 
 	mock "github.com/stretchr/testify/mock"
 
@@ -44,17 +48,172 @@ func (_m *Store) Create(ctx context.Context, name string, description string, au
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *Store) Delete(ctx context.Context, id uuid.UUID) error {
-	ret := _m.Called(ctx, id)
+// CreateFromTemplate provides a mock function with given fields: ctx, templateID, authorID, opts
+func (_m *Store) CreateFromTemplate(ctx context.Context, templateID uuid.UUID, authorID uuid.UUID, opts form.CopyOptions) (form.Form, error) {
+	ret := _m.Called(ctx, templateID, authorID, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateFromTemplate")
+	}
+
+	var r0 form.Form
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, form.CopyOptions) (form.Form, error)); ok {
+		return rf(ctx, templateID, authorID, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, form.CopyOptions) form.Form); ok {
+		r0 = rf(ctx, templateID, authorID, opts)
+	} else {
+		r0 = ret.Get(0).(form.Form)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, form.CopyOptions) error); ok {
+		r1 = rf(ctx, templateID, authorID, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateQuestion provides a mock function with given fields: ctx, formID, input
+func (_m *Store) CreateQuestion(ctx context.Context, formID uuid.UUID, input form.QuestionInput) (form.Question, error) {
+	ret := _m.Called(ctx, formID, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateQuestion")
+	}
+
+	var r0 form.Question
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, form.QuestionInput) (form.Question, error)); ok {
+		return rf(ctx, formID, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, form.QuestionInput) form.Question); ok {
+		r0 = rf(ctx, formID, input)
+	} else {
+		r0 = ret.Get(0).(form.Question)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, form.QuestionInput) error); ok {
+		r1 = rf(ctx, formID, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateSection provides a mock function with given fields: ctx, formID, input
+func (_m *Store) CreateSection(ctx context.Context, formID uuid.UUID, input form.SectionInput) (form.FormSection, error) {
+	ret := _m.Called(ctx, formID, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSection")
+	}
+
+	var r0 form.FormSection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, form.SectionInput) (form.FormSection, error)); ok {
+		return rf(ctx, formID, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, form.SectionInput) form.FormSection); ok {
+		r0 = rf(ctx, formID, input)
+	} else {
+		r0 = ret.Get(0).(form.FormSection)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, form.SectionInput) error); ok {
+		r1 = rf(ctx, formID, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Definition provides a mock function with given fields: ctx, formID
+func (_m *Store) Definition(ctx context.Context, formID uuid.UUID) (form.Form, definition.Document, error) {
+	ret := _m.Called(ctx, formID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Definition")
+	}
+
+	var r0 form.Form
+	var r1 definition.Document
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (form.Form, definition.Document, error)); ok {
+		return rf(ctx, formID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) form.Form); ok {
+		r0 = rf(ctx, formID)
+	} else {
+		r0 = ret.Get(0).(form.Form)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) definition.Document); ok {
+		r1 = rf(ctx, formID)
+	} else {
+		r1 = ret.Get(1).(definition.Document)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID) error); ok {
+		r2 = rf(ctx, formID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Delete provides a mock function with given fields: ctx, id, version
+func (_m *Store) Delete(ctx context.Context, id uuid.UUID, version *int32) error {
+	ret := _m.Called(ctx, id, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *int32) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteQuestion provides a mock function with given fields: ctx, formID, questionID
+func (_m *Store) DeleteQuestion(ctx context.Context, formID uuid.UUID, questionID uuid.UUID) error {
+	ret := _m.Called(ctx, formID, questionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteQuestion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, formID, questionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSection provides a mock function with given fields: ctx, formID, sectionID
+func (_m *Store) DeleteSection(ctx context.Context, formID uuid.UUID, sectionID uuid.UUID) error {
+	ret := _m.Called(ctx, formID, sectionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSection")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, formID, sectionID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -62,6 +221,127 @@ func (_m *Store) Delete(ctx context.Context, id uuid.UUID) error {
 	return r0
 }
 
+// DiffRevisions provides a mock function with given fields: ctx, formID, from, to
+func (_m *Store) DiffRevisions(ctx context.Context, formID uuid.UUID, from int32, to int32) ([]form.FieldChange, error) {
+	ret := _m.Called(ctx, formID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiffRevisions")
+	}
+
+	var r0 []form.FieldChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int32, int32) ([]form.FieldChange, error)); ok {
+		return rf(ctx, formID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int32, int32) []form.FieldChange); ok {
+		r0 = rf(ctx, formID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]form.FieldChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int32, int32) error); ok {
+		r1 = rf(ctx, formID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Duplicate provides a mock function with given fields: ctx, formID, userID, opts
+func (_m *Store) Duplicate(ctx context.Context, formID uuid.UUID, userID uuid.UUID, opts form.CopyOptions) (form.Form, error) {
+	ret := _m.Called(ctx, formID, userID, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Duplicate")
+	}
+
+	var r0 form.Form
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, form.CopyOptions) (form.Form, error)); ok {
+		return rf(ctx, formID, userID, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, form.CopyOptions) form.Form); ok {
+		r0 = rf(ctx, formID, userID, opts)
+	} else {
+		r0 = ret.Get(0).(form.Form)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, form.CopyOptions) error); ok {
+		r1 = rf(ctx, formID, userID, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Import provides a mock function with given fields: ctx, doc, userID, version
+func (_m *Store) Import(ctx context.Context, doc definition.Document, userID uuid.UUID, version *int32) (form.Form, bool, error) {
+	ret := _m.Called(ctx, doc, userID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 form.Form
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, definition.Document, uuid.UUID, *int32) (form.Form, bool, error)); ok {
+		return rf(ctx, doc, userID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, definition.Document, uuid.UUID, *int32) form.Form); ok {
+		r0 = rf(ctx, doc, userID, version)
+	} else {
+		r0 = ret.Get(0).(form.Form)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, definition.Document, uuid.UUID, *int32) bool); ok {
+		r1 = rf(ctx, doc, userID, version)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, definition.Document, uuid.UUID, *int32) error); ok {
+		r2 = rf(ctx, doc, userID, version)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// InviteCollaborator provides a mock function with given fields: ctx, formID, email, role
+func (_m *Store) InviteCollaborator(ctx context.Context, formID uuid.UUID, email string, role form.Role) (form.FormCollaborator, error) {
+	ret := _m.Called(ctx, formID, email, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InviteCollaborator")
+	}
+
+	var r0 form.FormCollaborator
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, form.Role) (form.FormCollaborator, error)); ok {
+		return rf(ctx, formID, email, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, form.Role) form.FormCollaborator); ok {
+		r0 = rf(ctx, formID, email, role)
+	} else {
+		r0 = ret.Get(0).(form.FormCollaborator)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, form.Role) error); ok {
+		r1 = rf(ctx, formID, email, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsBookmarked provides a mock function with given fields: ctx, formId, userId
 func (_m *Store) IsBookmarked(ctx context.Context, formId uuid.UUID, userId uuid.UUID) (bool, error) {
 	ret := _m.Called(ctx, formId, userId)
@@ -90,29 +370,455 @@ func (_m *Store) IsBookmarked(ctx context.Context, formId uuid.UUID, userId uuid
 	return r0, r1
 }
 
-// List provides a mock function with given fields: ctx
-func (_m *Store) List(ctx context.Context) ([]form.Form, error) {
-	ret := _m.Called(ctx)
+// List provides a mock function with given fields: ctx, userID, opts
+func (_m *Store) List(ctx context.Context, userID uuid.UUID, opts form.ListOptions) ([]form.ListWithBookmarksRow, string, error) {
+	ret := _m.Called(ctx, userID, opts)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
+	var r0 []form.ListWithBookmarksRow
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, form.ListOptions) ([]form.ListWithBookmarksRow, string, error)); ok {
+		return rf(ctx, userID, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, form.ListOptions) []form.ListWithBookmarksRow); ok {
+		r0 = rf(ctx, userID, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]form.ListWithBookmarksRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, form.ListOptions) string); ok {
+		r1 = rf(ctx, userID, opts)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, form.ListOptions) error); ok {
+		r2 = rf(ctx, userID, opts)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListCollaborators provides a mock function with given fields: ctx, formID
+func (_m *Store) ListCollaborators(ctx context.Context, formID uuid.UUID) ([]form.FormCollaborator, error) {
+	ret := _m.Called(ctx, formID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCollaborators")
+	}
+
+	var r0 []form.FormCollaborator
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]form.FormCollaborator, error)); ok {
+		return rf(ctx, formID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []form.FormCollaborator); ok {
+		r0 = rf(ctx, formID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]form.FormCollaborator)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, formID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListQuestions provides a mock function with given fields: ctx, formID
+func (_m *Store) ListQuestions(ctx context.Context, formID uuid.UUID) ([]form.Question, error) {
+	ret := _m.Called(ctx, formID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListQuestions")
+	}
+
+	var r0 []form.Question
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]form.Question, error)); ok {
+		return rf(ctx, formID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []form.Question); ok {
+		r0 = rf(ctx, formID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]form.Question)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, formID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRevisions provides a mock function with given fields: ctx, formID
+func (_m *Store) ListRevisions(ctx context.Context, formID uuid.UUID) ([]form.FormRevision, error) {
+	ret := _m.Called(ctx, formID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRevisions")
+	}
+
+	var r0 []form.FormRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]form.FormRevision, error)); ok {
+		return rf(ctx, formID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []form.FormRevision); ok {
+		r0 = rf(ctx, formID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]form.FormRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, formID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSections provides a mock function with given fields: ctx, formID
+func (_m *Store) ListSections(ctx context.Context, formID uuid.UUID) ([]form.FormSection, error) {
+	ret := _m.Called(ctx, formID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSections")
+	}
+
+	var r0 []form.FormSection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]form.FormSection, error)); ok {
+		return rf(ctx, formID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []form.FormSection); ok {
+		r0 = rf(ctx, formID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]form.FormSection)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, formID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTemplates provides a mock function with given fields: ctx, userID, page
+func (_m *Store) ListTemplates(ctx context.Context, userID uuid.UUID, page pagination.Params) ([]form.Form, string, error) {
+	ret := _m.Called(ctx, userID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTemplates")
+	}
+
 	var r0 []form.Form
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, pagination.Params) ([]form.Form, string, error)); ok {
+		return rf(ctx, userID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, pagination.Params) []form.Form); ok {
+		r0 = rf(ctx, userID, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]form.Form)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, pagination.Params) string); ok {
+		r1 = rf(ctx, userID, page)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, pagination.Params) error); ok {
+		r2 = rf(ctx, userID, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListTransitions provides a mock function with given fields: ctx, formID
+func (_m *Store) ListTransitions(ctx context.Context, formID uuid.UUID) ([]form.FormStatusTransition, error) {
+	ret := _m.Called(ctx, formID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTransitions")
+	}
+
+	var r0 []form.FormStatusTransition
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]form.Form, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]form.FormStatusTransition, error)); ok {
+		return rf(ctx, formID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []form.FormStatusTransition); ok {
+		r0 = rf(ctx, formID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]form.FormStatusTransition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, formID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTrash provides a mock function with given fields: ctx, userID, page
+func (_m *Store) ListTrash(ctx context.Context, userID uuid.UUID, page pagination.Params) ([]form.Form, string, error) {
+	ret := _m.Called(ctx, userID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTrash")
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []form.Form); ok {
-		r0 = rf(ctx)
+
+	var r0 []form.Form
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, pagination.Params) ([]form.Form, string, error)); ok {
+		return rf(ctx, userID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, pagination.Params) []form.Form); ok {
+		r0 = rf(ctx, userID, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]form.Form)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, pagination.Params) string); ok {
+		r1 = rf(ctx, userID, page)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, pagination.Params) error); ok {
+		r2 = rf(ctx, userID, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// RemoveCollaborator provides a mock function with given fields: ctx, formID, email
+func (_m *Store) RemoveCollaborator(ctx context.Context, formID uuid.UUID, email string) error {
+	ret := _m.Called(ctx, formID, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveCollaborator")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, formID, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *Store) Restore(ctx context.Context, id uuid.UUID) (form.Form, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 form.Form
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (form.Form, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) form.Form); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(form.Form)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Rollback provides a mock function with given fields: ctx, formID, number
+func (_m *Store) Rollback(ctx context.Context, formID uuid.UUID, number int32) (form.Form, error) {
+	ret := _m.Called(ctx, formID, number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rollback")
+	}
+
+	var r0 form.Form
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int32) (form.Form, error)); ok {
+		return rf(ctx, formID, number)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int32) form.Form); ok {
+		r0 = rf(ctx, formID, number)
+	} else {
+		r0 = ret.Get(0).(form.Form)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int32) error); ok {
+		r1 = rf(ctx, formID, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, userID, query, page
+func (_m *Store) Search(ctx context.Context, userID uuid.UUID, query string, page pagination.Params) ([]form.SearchRow, string, error) {
+	ret := _m.Called(ctx, userID, query, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []form.SearchRow
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, pagination.Params) ([]form.SearchRow, string, error)); ok {
+		return rf(ctx, userID, query, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, pagination.Params) []form.SearchRow); ok {
+		r0 = rf(ctx, userID, query, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]form.SearchRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, pagination.Params) string); ok {
+		r1 = rf(ctx, userID, query, page)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, string, pagination.Params) error); ok {
+		r2 = rf(ctx, userID, query, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SetQuiz provides a mock function with given fields: ctx, id, settings, version
+func (_m *Store) SetQuiz(ctx context.Context, id uuid.UUID, settings form.QuizSettings, version *int32) (form.Form, error) {
+	ret := _m.Called(ctx, id, settings, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetQuiz")
+	}
+
+	var r0 form.Form
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, form.QuizSettings, *int32) (form.Form, error)); ok {
+		return rf(ctx, id, settings, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, form.QuizSettings, *int32) form.Form); ok {
+		r0 = rf(ctx, id, settings, version)
+	} else {
+		r0 = ret.Get(0).(form.Form)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, form.QuizSettings, *int32) error); ok {
+		r1 = rf(ctx, id, settings, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetTemplate provides a mock function with given fields: ctx, id, visibility, version
+func (_m *Store) SetTemplate(ctx context.Context, id uuid.UUID, visibility form.TemplateVisibility, version *int32) (form.Form, error) {
+	ret := _m.Called(ctx, id, visibility, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTemplate")
+	}
+
+	var r0 form.Form
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, form.TemplateVisibility, *int32) (form.Form, error)); ok {
+		return rf(ctx, id, visibility, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, form.TemplateVisibility, *int32) form.Form); ok {
+		r0 = rf(ctx, id, visibility, version)
+	} else {
+		r0 = ret.Get(0).(form.Form)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, form.TemplateVisibility, *int32) error); ok {
+		r1 = rf(ctx, id, visibility, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Transition provides a mock function with given fields: ctx, formID, action
+func (_m *Store) Transition(ctx context.Context, formID uuid.UUID, action form.Action) (form.Form, error) {
+	ret := _m.Called(ctx, formID, action)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transition")
+	}
+
+	var r0 form.Form
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, form.Action) (form.Form, error)); ok {
+		return rf(ctx, formID, action)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, form.Action) form.Form); ok {
+		r0 = rf(ctx, formID, action)
+	} else {
+		r0 = ret.Get(0).(form.Form)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, form.Action) error); ok {
+		r1 = rf(ctx, formID, action)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -120,9 +826,9 @@ func (_m *Store) List(ctx context.Context) ([]form.Form, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: ctx, id, name, description
-func (_m *Store) Update(ctx context.Context, id uuid.UUID, name string, description string) (form.Form, error) {
-	ret := _m.Called(ctx, id, name, description)
+// Update provides a mock function with given fields: ctx, id, name, description, version
+func (_m *Store) Update(ctx context.Context, id uuid.UUID, name string, description string, version *int32) (form.Form, error) {
+	ret := _m.Called(ctx, id, name, description, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
@@ -130,17 +836,166 @@ func (_m *Store) Update(ctx context.Context, id uuid.UUID, name string, descript
 
 	var r0 form.Form
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) (form.Form, error)); ok {
-		return rf(ctx, id, name, description)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string, *int32) (form.Form, error)); ok {
+		return rf(ctx, id, name, description, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string, *int32) form.Form); ok {
+		r0 = rf(ctx, id, name, description, version)
+	} else {
+		r0 = ret.Get(0).(form.Form)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, string, *int32) error); ok {
+		r1 = rf(ctx, id, name, description, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateCollaborator provides a mock function with given fields: ctx, formID, email, role
+func (_m *Store) UpdateCollaborator(ctx context.Context, formID uuid.UUID, email string, role form.Role) (form.FormCollaborator, error) {
+	ret := _m.Called(ctx, formID, email, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCollaborator")
+	}
+
+	var r0 form.FormCollaborator
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, form.Role) (form.FormCollaborator, error)); ok {
+		return rf(ctx, formID, email, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, form.Role) form.FormCollaborator); ok {
+		r0 = rf(ctx, formID, email, role)
+	} else {
+		r0 = ret.Get(0).(form.FormCollaborator)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, form.Role) error); ok {
+		r1 = rf(ctx, formID, email, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateQuestion provides a mock function with given fields: ctx, formID, questionID, input
+func (_m *Store) UpdateQuestion(ctx context.Context, formID uuid.UUID, questionID uuid.UUID, input form.QuestionInput) (form.Question, error) {
+	ret := _m.Called(ctx, formID, questionID, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateQuestion")
+	}
+
+	var r0 form.Question
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, form.QuestionInput) (form.Question, error)); ok {
+		return rf(ctx, formID, questionID, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, form.QuestionInput) form.Question); ok {
+		r0 = rf(ctx, formID, questionID, input)
+	} else {
+		r0 = ret.Get(0).(form.Question)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, form.QuestionInput) error); ok {
+		r1 = rf(ctx, formID, questionID, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSection provides a mock function with given fields: ctx, formID, sectionID, input
+func (_m *Store) UpdateSection(ctx context.Context, formID uuid.UUID, sectionID uuid.UUID, input form.SectionInput) (form.FormSection, error) {
+	ret := _m.Called(ctx, formID, sectionID, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSection")
+	}
+
+	var r0 form.FormSection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, form.SectionInput) (form.FormSection, error)); ok {
+		return rf(ctx, formID, sectionID, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, form.SectionInput) form.FormSection); ok {
+		r0 = rf(ctx, formID, sectionID, input)
+	} else {
+		r0 = ret.Get(0).(form.FormSection)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, form.SectionInput) error); ok {
+		r1 = rf(ctx, formID, sectionID, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ValidateAnswers provides a mock function with given fields: ctx, formID, answers
+func (_m *Store) ValidateAnswers(ctx context.Context, formID uuid.UUID, answers map[uuid.UUID]json.RawMessage) (logic.Result, map[string]string, error) {
+	ret := _m.Called(ctx, formID, answers)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateAnswers")
+	}
+
+	var r0 logic.Result
+	var r1 map[string]string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, map[uuid.UUID]json.RawMessage) (logic.Result, map[string]string, error)); ok {
+		return rf(ctx, formID, answers)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, map[uuid.UUID]json.RawMessage) logic.Result); ok {
+		r0 = rf(ctx, formID, answers)
+	} else {
+		r0 = ret.Get(0).(logic.Result)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, map[uuid.UUID]json.RawMessage) map[string]string); ok {
+		r1 = rf(ctx, formID, answers)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(map[string]string)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, map[uuid.UUID]json.RawMessage) error); ok {
+		r2 = rf(ctx, formID, answers)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Visible provides a mock function with given fields: ctx, formID
+func (_m *Store) Visible(ctx context.Context, formID uuid.UUID) (form.Form, error) {
+	ret := _m.Called(ctx, formID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Visible")
+	}
+
+	var r0 form.Form
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (form.Form, error)); ok {
+		return rf(ctx, formID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) form.Form); ok {
-		r0 = rf(ctx, id, name, description)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) form.Form); ok {
+		r0 = rf(ctx, formID)
 	} else {
 		r0 = ret.Get(0).(form.Form)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, string) error); ok {
-		r1 = rf(ctx, id, name, description)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, formID)
 	} else {
 		r1 = ret.Error(1)
 	}
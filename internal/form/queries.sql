@@ -3,20 +3,347 @@ INSERT INTO forms (title, description, author_id)
 VALUES ($1, $2, $3)
 RETURNING *;
 
--- name: List :many
-SELECT * FROM forms
-ORDER BY id;
+-- name: ListWithBookmarks :many
+SELECT f.id, f.title, f.description, f.author_id, f.created_at, f.status,
+       COALESCE(bool_or(b.user_id = sqlc.arg(user_id)::uuid), false)::bool AS is_bookmarked,
+       COUNT(b.user_id) AS bookmark_count
+FROM forms f
+LEFT JOIN bookmarks b ON b.form_id = f.id
+WHERE (sqlc.narg(author_id)::text IS NULL OR f.author_id::text = sqlc.narg(author_id)::text)
+  AND (sqlc.narg(bookmarked_by)::uuid IS NULL OR EXISTS (
+        SELECT 1 FROM bookmarks mb
+        WHERE mb.form_id = f.id AND mb.user_id = sqlc.narg(bookmarked_by)::uuid))
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR f.created_at >= sqlc.narg(created_after)::timestamptz)
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR f.created_at < sqlc.narg(created_before)::timestamptz)
+  AND f.deleted_at IS NULL
+  AND f.status <> 'archived'
+  AND (f.status <> 'draft' OR f.author_id::text = sqlc.arg(user_id)::uuid::text OR EXISTS (
+        SELECT 1 FROM form_collaborators c
+        WHERE c.form_id = f.id AND c.user_id = sqlc.arg(user_id)::uuid AND c.role = 'owner'))
+  AND (sqlc.narg(cursor_id)::uuid IS NULL OR CASE
+        WHEN sqlc.arg(sort_by)::text = 'title' AND sqlc.arg(descending)::bool
+            THEN (f.title, f.id) < (sqlc.arg(cursor_title)::text, sqlc.narg(cursor_id)::uuid)
+        WHEN sqlc.arg(sort_by)::text = 'title'
+            THEN (f.title, f.id) > (sqlc.arg(cursor_title)::text, sqlc.narg(cursor_id)::uuid)
+        WHEN sqlc.arg(descending)::bool
+            THEN (f.created_at, f.id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+        ELSE (f.created_at, f.id) > (sqlc.arg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+      END)
+GROUP BY f.id
+ORDER BY
+    CASE WHEN sqlc.arg(sort_by)::text = 'title' AND NOT sqlc.arg(descending)::bool THEN f.title END,
+    CASE WHEN sqlc.arg(sort_by)::text = 'title' AND sqlc.arg(descending)::bool THEN f.title END DESC,
+    CASE WHEN sqlc.arg(sort_by)::text <> 'title' AND NOT sqlc.arg(descending)::bool THEN f.created_at END,
+    CASE WHEN sqlc.arg(sort_by)::text <> 'title' AND sqlc.arg(descending)::bool THEN f.created_at END DESC,
+    CASE WHEN NOT sqlc.arg(descending)::bool THEN f.id END,
+    CASE WHEN sqlc.arg(descending)::bool THEN f.id END DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: Update :one
-UPDATE forms SET title = $2, description = $3
-where id = $1
+UPDATE forms SET title = sqlc.arg(title), description = sqlc.arg(description), version = version + 1
+where id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (sqlc.narg(expected_version)::int IS NULL OR version = sqlc.narg(expected_version)::int)
+RETURNING *;
+
+-- name: SetQuiz :one
+UPDATE forms SET is_quiz = sqlc.arg(is_quiz), reveal_answers = sqlc.arg(reveal_answers), version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (sqlc.narg(expected_version)::int IS NULL OR version = sqlc.narg(expected_version)::int)
 RETURNING *;
 
--- name: Delete :exec
-DELETE FROM forms WHERE id = $1;
+-- name: Delete :execrows
+UPDATE forms SET deleted_at = now(), version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (sqlc.narg(expected_version)::int IS NULL OR version = sqlc.narg(expected_version)::int);
 
 -- name: IsBookmarked :one
 SELECT EXISTS(
     SELECT 1 FROM bookmarks
     where form_id = $1 and user_id = $2
-);
\ No newline at end of file
+);
+
+-- name: Get :one
+SELECT * FROM forms
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetDeleted :one
+SELECT * FROM forms
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: CreateQuestion :one
+WITH touched AS (
+    UPDATE forms SET version = version + 1
+    WHERE id = sqlc.arg(form_id)
+)
+INSERT INTO questions (form_id, type, title, description, required, position, settings, section_id, rules, points, answer_key)
+VALUES (sqlc.arg(form_id), sqlc.arg(type), sqlc.arg(title), sqlc.arg(description), sqlc.arg(required),
+        COALESCE(sqlc.narg(position), (SELECT COALESCE(MAX(q.position), -1) + 1 FROM questions q WHERE q.form_id = sqlc.arg(form_id))),
+        sqlc.arg(settings), sqlc.narg(section_id), sqlc.arg(rules), sqlc.arg(points), sqlc.narg(answer_key))
+RETURNING *;
+
+-- name: ListQuestions :many
+SELECT * FROM questions
+WHERE form_id = $1
+ORDER BY position, created_at;
+
+-- name: UpdateQuestion :one
+WITH touched AS (
+    UPDATE forms SET version = version + 1
+    WHERE id IN (SELECT q.form_id FROM questions q WHERE q.id = sqlc.arg(id) AND q.form_id = sqlc.arg(form_id))
+)
+UPDATE questions
+SET type        = sqlc.arg(type),
+    title       = sqlc.arg(title),
+    description = sqlc.arg(description),
+    required    = sqlc.arg(required),
+    position    = COALESCE(sqlc.narg(position), position),
+    settings    = sqlc.arg(settings),
+    section_id  = sqlc.narg(section_id),
+    rules       = sqlc.arg(rules),
+    points      = sqlc.arg(points),
+    answer_key  = sqlc.narg(answer_key),
+    updated_at  = now()
+WHERE id = sqlc.arg(id) AND form_id = sqlc.arg(form_id)
+RETURNING *;
+
+-- name: DeleteQuestion :execrows
+WITH touched AS (
+    UPDATE forms SET version = version + 1
+    WHERE id IN (SELECT q.form_id FROM questions q WHERE q.id = $1 AND q.form_id = $2)
+)
+DELETE FROM questions
+WHERE id = $1 AND form_id = $2;
+
+
+-- name: GetCollaboratorRole :one
+SELECT role FROM form_collaborators
+WHERE form_id = sqlc.arg(form_id) AND user_id = sqlc.arg(user_id)::uuid;
+
+-- name: CreateCollaborator :one
+INSERT INTO form_collaborators (form_id, email, user_id, role, invited_by)
+VALUES (sqlc.arg(form_id), sqlc.arg(email), (SELECT u.id FROM users u WHERE lower(u.email) = sqlc.arg(email)), sqlc.arg(role), sqlc.arg(invited_by)::uuid)
+RETURNING *;
+
+-- name: ListCollaborators :many
+SELECT * FROM form_collaborators
+WHERE form_id = $1
+ORDER BY created_at, email;
+
+-- name: UpdateCollaboratorRole :one
+UPDATE form_collaborators SET role = $3
+WHERE form_id = $1 AND email = $2
+RETURNING *;
+
+-- name: DeleteCollaborator :execrows
+DELETE FROM form_collaborators
+WHERE form_id = $1 AND email = $2;
+
+-- name: AttachInvitations :execrows
+UPDATE form_collaborators SET user_id = sqlc.arg(user_id)::uuid
+WHERE email = sqlc.arg(email) AND user_id IS NULL;
+
+-- name: Search :many
+SELECT f.id, f.title, f.description, f.author_id, f.created_at,
+       ts_rank(d.document, q.query) AS rank,
+       ts_headline('simple', f.title, q.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS title_highlight,
+       ts_headline('simple', d.body, q.query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5') AS snippet
+FROM form_search_documents d
+JOIN forms f ON f.id = d.form_id
+CROSS JOIN websearch_to_tsquery('simple', sqlc.arg(query)) AS q(query)
+WHERE d.document @@ q.query
+  AND f.deleted_at IS NULL
+  AND f.status <> 'archived'
+  AND (f.status <> 'draft' OR f.author_id::text = sqlc.arg(user_id)::uuid::text OR EXISTS (
+        SELECT 1 FROM form_collaborators c
+        WHERE c.form_id = f.id AND c.user_id = sqlc.arg(user_id)::uuid AND c.role = 'owner'))
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+       OR (ts_rank(d.document, q.query), f.id) < (sqlc.arg(cursor_rank)::real, sqlc.narg(cursor_id)::uuid))
+ORDER BY rank DESC, f.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: Transition :one
+WITH updated AS (
+    UPDATE forms SET status = sqlc.arg(to_status)::text, version = version + 1
+    WHERE id = sqlc.arg(form_id) AND status = sqlc.arg(from_status)::text
+    RETURNING id
+)
+INSERT INTO form_status_transitions (form_id, from_status, to_status, changed_by)
+SELECT id, sqlc.arg(from_status)::text, sqlc.arg(to_status)::text, sqlc.arg(changed_by)::uuid FROM updated
+RETURNING *;
+
+-- name: ListTransitions :many
+SELECT * FROM form_status_transitions
+WHERE form_id = $1
+ORDER BY changed_at, id;
+
+-- name: Restore :one
+UPDATE forms SET deleted_at = NULL, version = version + 1
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: ListTrash :many
+SELECT f.* FROM forms f
+WHERE f.deleted_at IS NOT NULL
+  AND (f.author_id::text = sqlc.arg(user_id)::uuid::text OR EXISTS (
+        SELECT 1 FROM form_collaborators c
+        WHERE c.form_id = f.id AND c.user_id = sqlc.arg(user_id)::uuid AND c.role = 'owner'))
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+       OR (f.deleted_at, f.id) < (sqlc.arg(cursor_deleted_at)::timestamptz, sqlc.narg(cursor_id)::uuid))
+ORDER BY f.deleted_at DESC, f.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: PurgeDeleted :execrows
+DELETE FROM forms
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg(deleted_before)::timestamptz;
+
+-- name: CreateRevision :one
+INSERT INTO form_revisions (form_id, number, snapshot, created_by)
+VALUES (sqlc.arg(form_id),
+        (SELECT COALESCE(MAX(r.number), 0) + 1 FROM form_revisions r WHERE r.form_id = sqlc.arg(form_id)),
+        sqlc.arg(snapshot),
+        sqlc.arg(created_by)::uuid)
+RETURNING *;
+
+-- name: ListRevisions :many
+SELECT * FROM form_revisions
+WHERE form_id = $1
+ORDER BY number DESC;
+
+-- name: GetRevision :one
+SELECT * FROM form_revisions
+WHERE form_id = $1 AND number = $2;
+
+-- name: CreateSection :one
+WITH touched AS (
+    UPDATE forms SET version = version + 1
+    WHERE id = sqlc.arg(form_id)
+)
+INSERT INTO form_sections (form_id, title, description, position, rules)
+VALUES (sqlc.arg(form_id), sqlc.arg(title), sqlc.arg(description),
+        COALESCE(sqlc.narg(position), (SELECT COALESCE(MAX(s.position), -1) + 1 FROM form_sections s WHERE s.form_id = sqlc.arg(form_id))),
+        sqlc.arg(rules))
+RETURNING *;
+
+-- name: ListSections :many
+SELECT * FROM form_sections
+WHERE form_id = $1
+ORDER BY position, created_at;
+
+-- name: UpdateSection :one
+WITH touched AS (
+    UPDATE forms SET version = version + 1
+    WHERE id IN (SELECT s.form_id FROM form_sections s WHERE s.id = sqlc.arg(id) AND s.form_id = sqlc.arg(form_id))
+)
+UPDATE form_sections
+SET title       = sqlc.arg(title),
+    description = sqlc.arg(description),
+    position    = COALESCE(sqlc.narg(position), position),
+    rules       = sqlc.arg(rules),
+    updated_at  = now()
+WHERE id = sqlc.arg(id) AND form_id = sqlc.arg(form_id)
+RETURNING *;
+
+-- name: DeleteSection :execrows
+WITH touched AS (
+    UPDATE forms SET version = version + 1
+    WHERE id IN (SELECT s.form_id FROM form_sections s WHERE s.id = $1 AND s.form_id = $2)
+)
+DELETE FROM form_sections
+WHERE id = $1 AND form_id = $2;
+
+-- name: SetTemplate :one
+UPDATE forms SET template_visibility = sqlc.narg(template_visibility), version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (sqlc.narg(expected_version)::int IS NULL OR version = sqlc.narg(expected_version)::int)
+RETURNING *;
+
+-- name: ListTemplates :many
+SELECT f.* FROM forms f
+WHERE f.template_visibility IS NOT NULL
+  AND f.deleted_at IS NULL
+  AND f.status <> 'archived'
+  AND (f.status <> 'draft' OR f.author_id::text = sqlc.arg(user_id)::uuid::text OR EXISTS (
+        SELECT 1 FROM form_collaborators c
+        WHERE c.form_id = f.id AND c.user_id = sqlc.arg(user_id)::uuid AND c.role = 'owner'))
+  AND (f.template_visibility = 'public'
+       OR f.author_id::text = sqlc.arg(user_id)::uuid::text
+       OR EXISTS (
+        SELECT 1 FROM form_collaborators c
+        WHERE c.form_id = f.id AND c.user_id = sqlc.arg(user_id)::uuid
+          AND (f.template_visibility = 'team' OR c.role = 'owner')))
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+       OR (f.created_at, f.id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid))
+ORDER BY f.created_at DESC, f.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CreateCopy :one
+INSERT INTO forms (title, description, author_id, is_quiz, reveal_answers)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: CopySections :execrows
+INSERT INTO form_sections (id, form_id, title, description, position, rules)
+SELECT m.new_id, sqlc.arg(form_id), s.title, s.description, (m.ord - 1)::int, m.rules::jsonb
+FROM form_sections s
+JOIN unnest(sqlc.arg(old_ids)::uuid[], sqlc.arg(new_ids)::uuid[], sqlc.arg(rules)::text[])
+    WITH ORDINALITY AS m (old_id, new_id, rules, ord) ON m.old_id = s.id
+WHERE s.form_id = sqlc.arg(source_id);
+
+-- name: CopyQuestions :execrows
+INSERT INTO questions (id, form_id, type, title, description, required, position, settings, section_id, rules, points, answer_key)
+SELECT m.new_id, sqlc.arg(form_id), q.type, q.title, q.description, q.required, (m.ord - 1)::int, q.settings,
+       s.new_id, m.rules::jsonb, q.points, CASE WHEN sqlc.arg(with_answer_keys)::boolean THEN q.answer_key END
+FROM questions q
+JOIN unnest(sqlc.arg(old_ids)::uuid[], sqlc.arg(new_ids)::uuid[], sqlc.arg(rules)::text[])
+    WITH ORDINALITY AS m (old_id, new_id, rules, ord) ON m.old_id = q.id
+LEFT JOIN unnest(sqlc.arg(old_section_ids)::uuid[], sqlc.arg(new_section_ids)::uuid[]) AS s (old_id, new_id)
+    ON s.old_id = q.section_id
+WHERE q.form_id = sqlc.arg(source_id);
+
+-- name: UpdateDefinition :one
+UPDATE forms
+SET title          = sqlc.arg(title),
+    description    = sqlc.arg(description),
+    is_quiz        = sqlc.arg(is_quiz),
+    reveal_answers = sqlc.arg(reveal_answers),
+    version        = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (sqlc.narg(expected_version)::int IS NULL OR version = sqlc.narg(expected_version)::int)
+RETURNING *;
+
+-- name: ImportSection :execrows
+INSERT INTO form_sections (id, form_id, title, description, position, rules)
+VALUES (sqlc.arg(id), sqlc.arg(form_id), sqlc.arg(title), sqlc.arg(description), sqlc.arg(position), sqlc.arg(rules))
+ON CONFLICT (id) DO UPDATE
+SET title       = excluded.title,
+    description = excluded.description,
+    position    = excluded.position,
+    rules       = excluded.rules,
+    updated_at  = now()
+WHERE form_sections.form_id = excluded.form_id;
+
+-- name: ImportQuestion :execrows
+INSERT INTO questions (id, form_id, type, title, description, required, position, settings, section_id, rules, points, answer_key)
+VALUES (sqlc.arg(id), sqlc.arg(form_id), sqlc.arg(type), sqlc.arg(title), sqlc.arg(description), sqlc.arg(required),
+        sqlc.arg(position), sqlc.arg(settings), sqlc.narg(section_id), sqlc.arg(rules), sqlc.arg(points), sqlc.narg(answer_key))
+ON CONFLICT (id) DO UPDATE
+SET type        = excluded.type,
+    title       = excluded.title,
+    description = excluded.description,
+    required    = excluded.required,
+    position    = excluded.position,
+    settings    = excluded.settings,
+    section_id  = excluded.section_id,
+    rules       = excluded.rules,
+    points      = excluded.points,
+    answer_key  = excluded.answer_key,
+    updated_at  = now()
+WHERE questions.form_id = excluded.form_id;
+
+-- name: DeleteQuestionsExcept :execrows
+DELETE FROM questions
+WHERE form_id = sqlc.arg(form_id) AND NOT (id = ANY (sqlc.arg(keep_ids)::uuid[]));
+
+-- name: DeleteSectionsExcept :execrows
+DELETE FROM form_sections
+WHERE form_id = sqlc.arg(form_id) AND NOT (id = ANY (sqlc.arg(keep_ids)::uuid[]));
\ No newline at end of file
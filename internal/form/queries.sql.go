@@ -12,10 +12,99 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const attachInvitations = `-- name: AttachInvitations :execrows
+UPDATE form_collaborators SET user_id = $1::uuid
+WHERE email = $2 AND user_id IS NULL
+`
+
+type AttachInvitationsParams struct {
+	UserID uuid.UUID
+	Email  string
+}
+
+func (q *Queries) AttachInvitations(ctx context.Context, arg AttachInvitationsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, attachInvitations, arg.UserID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const copyQuestions = `-- name: CopyQuestions :execrows
+INSERT INTO questions (id, form_id, type, title, description, required, position, settings, section_id, rules, points, answer_key)
+SELECT m.new_id, $1, q.type, q.title, q.description, q.required, (m.ord - 1)::int, q.settings,
+       s.new_id, m.rules::jsonb, q.points, CASE WHEN $2::boolean THEN q.answer_key END
+FROM questions q
+JOIN unnest($3::uuid[], $4::uuid[], $5::text[])
+    WITH ORDINALITY AS m (old_id, new_id, rules, ord) ON m.old_id = q.id
+LEFT JOIN unnest($6::uuid[], $7::uuid[]) AS s (old_id, new_id)
+    ON s.old_id = q.section_id
+WHERE q.form_id = $8
+`
+
+type CopyQuestionsParams struct {
+	FormID         uuid.UUID
+	WithAnswerKeys bool
+	OldIds         []uuid.UUID
+	NewIds         []uuid.UUID
+	Rules          []string
+	OldSectionIds  []uuid.UUID
+	NewSectionIds  []uuid.UUID
+	SourceID       uuid.UUID
+}
+
+func (q *Queries) CopyQuestions(ctx context.Context, arg CopyQuestionsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, copyQuestions,
+		arg.FormID,
+		arg.WithAnswerKeys,
+		arg.OldIds,
+		arg.NewIds,
+		arg.Rules,
+		arg.OldSectionIds,
+		arg.NewSectionIds,
+		arg.SourceID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const copySections = `-- name: CopySections :execrows
+INSERT INTO form_sections (id, form_id, title, description, position, rules)
+SELECT m.new_id, $1, s.title, s.description, (m.ord - 1)::int, m.rules::jsonb
+FROM form_sections s
+JOIN unnest($2::uuid[], $3::uuid[], $4::text[])
+    WITH ORDINALITY AS m (old_id, new_id, rules, ord) ON m.old_id = s.id
+WHERE s.form_id = $5
+`
+
+type CopySectionsParams struct {
+	FormID   uuid.UUID
+	OldIds   []uuid.UUID
+	NewIds   []uuid.UUID
+	Rules    []string
+	SourceID uuid.UUID
+}
+
+func (q *Queries) CopySections(ctx context.Context, arg CopySectionsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, copySections,
+		arg.FormID,
+		arg.OldIds,
+		arg.NewIds,
+		arg.Rules,
+		arg.SourceID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const create = `-- name: Create :one
 INSERT INTO forms (title, description, author_id)
 VALUES ($1, $2, $3)
-RETURNING id, title, description, author_id, created_at
+RETURNING id, title, description, author_id, created_at, status, deleted_at, version, is_quiz, reveal_answers, template_visibility
 `
 
 type CreateParams struct {
@@ -33,17 +122,513 @@ func (q *Queries) Create(ctx context.Context, arg CreateParams) (Form, error) {
 		&i.Description,
 		&i.AuthorID,
 		&i.CreatedAt,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.IsQuiz,
+		&i.RevealAnswers,
+		&i.TemplateVisibility,
+	)
+	return i, err
+}
+
+const createCollaborator = `-- name: CreateCollaborator :one
+INSERT INTO form_collaborators (form_id, email, user_id, role, invited_by)
+VALUES ($1, $2, (SELECT u.id FROM users u WHERE lower(u.email) = $2), $3, $4::uuid)
+RETURNING form_id, email, user_id, role, invited_by, created_at
+`
+
+type CreateCollaboratorParams struct {
+	FormID    uuid.UUID
+	Email     string
+	Role      string
+	InvitedBy uuid.UUID
+}
+
+func (q *Queries) CreateCollaborator(ctx context.Context, arg CreateCollaboratorParams) (FormCollaborator, error) {
+	row := q.db.QueryRow(ctx, createCollaborator,
+		arg.FormID,
+		arg.Email,
+		arg.Role,
+		arg.InvitedBy,
+	)
+	var i FormCollaborator
+	err := row.Scan(
+		&i.FormID,
+		&i.Email,
+		&i.UserID,
+		&i.Role,
+		&i.InvitedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createCopy = `-- name: CreateCopy :one
+INSERT INTO forms (title, description, author_id, is_quiz, reveal_answers)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, title, description, author_id, created_at, status, deleted_at, version, is_quiz, reveal_answers, template_visibility
+`
+
+type CreateCopyParams struct {
+	Title         string
+	Description   pgtype.Text
+	AuthorID      pgtype.Text
+	IsQuiz        bool
+	RevealAnswers string
+}
+
+func (q *Queries) CreateCopy(ctx context.Context, arg CreateCopyParams) (Form, error) {
+	row := q.db.QueryRow(ctx, createCopy,
+		arg.Title,
+		arg.Description,
+		arg.AuthorID,
+		arg.IsQuiz,
+		arg.RevealAnswers,
+	)
+	var i Form
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.IsQuiz,
+		&i.RevealAnswers,
+		&i.TemplateVisibility,
+	)
+	return i, err
+}
+
+const createQuestion = `-- name: CreateQuestion :one
+WITH touched AS (
+    UPDATE forms SET version = version + 1
+    WHERE id = $1
+)
+INSERT INTO questions (form_id, type, title, description, required, position, settings, section_id, rules, points, answer_key)
+VALUES ($1, $2, $3, $4, $5,
+        COALESCE($6, (SELECT COALESCE(MAX(q.position), -1) + 1 FROM questions q WHERE q.form_id = $1)),
+        $7, $8, $9, $10, $11)
+RETURNING id, form_id, type, title, description, required, position, settings, created_at, updated_at, section_id, rules, points, answer_key
+`
+
+type CreateQuestionParams struct {
+	FormID      uuid.UUID
+	Type        string
+	Title       string
+	Description pgtype.Text
+	Required    bool
+	Position    pgtype.Int4
+	Settings    []byte
+	SectionID   pgtype.UUID
+	Rules       []byte
+	Points      float64
+	AnswerKey   []byte
+}
+
+func (q *Queries) CreateQuestion(ctx context.Context, arg CreateQuestionParams) (Question, error) {
+	row := q.db.QueryRow(ctx, createQuestion,
+		arg.FormID,
+		arg.Type,
+		arg.Title,
+		arg.Description,
+		arg.Required,
+		arg.Position,
+		arg.Settings,
+		arg.SectionID,
+		arg.Rules,
+		arg.Points,
+		arg.AnswerKey,
+	)
+	var i Question
+	err := row.Scan(
+		&i.ID,
+		&i.FormID,
+		&i.Type,
+		&i.Title,
+		&i.Description,
+		&i.Required,
+		&i.Position,
+		&i.Settings,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SectionID,
+		&i.Rules,
+		&i.Points,
+		&i.AnswerKey,
+	)
+	return i, err
+}
+
+const createRevision = `-- name: CreateRevision :one
+INSERT INTO form_revisions (form_id, number, snapshot, created_by)
+VALUES ($1,
+        (SELECT COALESCE(MAX(r.number), 0) + 1 FROM form_revisions r WHERE r.form_id = $1),
+        $2,
+        $3::uuid)
+RETURNING id, form_id, number, snapshot, created_by, created_at
+`
+
+type CreateRevisionParams struct {
+	FormID    uuid.UUID
+	Snapshot  []byte
+	CreatedBy uuid.UUID
+}
+
+func (q *Queries) CreateRevision(ctx context.Context, arg CreateRevisionParams) (FormRevision, error) {
+	row := q.db.QueryRow(ctx, createRevision, arg.FormID, arg.Snapshot, arg.CreatedBy)
+	var i FormRevision
+	err := row.Scan(
+		&i.ID,
+		&i.FormID,
+		&i.Number,
+		&i.Snapshot,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createSection = `-- name: CreateSection :one
+WITH touched AS (
+    UPDATE forms SET version = version + 1
+    WHERE id = $1
+)
+INSERT INTO form_sections (form_id, title, description, position, rules)
+VALUES ($1, $2, $3,
+        COALESCE($4, (SELECT COALESCE(MAX(s.position), -1) + 1 FROM form_sections s WHERE s.form_id = $1)),
+        $5)
+RETURNING id, form_id, title, description, position, rules, created_at, updated_at
+`
+
+type CreateSectionParams struct {
+	FormID      uuid.UUID
+	Title       string
+	Description pgtype.Text
+	Position    pgtype.Int4
+	Rules       []byte
+}
+
+func (q *Queries) CreateSection(ctx context.Context, arg CreateSectionParams) (FormSection, error) {
+	row := q.db.QueryRow(ctx, createSection,
+		arg.FormID,
+		arg.Title,
+		arg.Description,
+		arg.Position,
+		arg.Rules,
+	)
+	var i FormSection
+	err := row.Scan(
+		&i.ID,
+		&i.FormID,
+		&i.Title,
+		&i.Description,
+		&i.Position,
+		&i.Rules,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const delete = `-- name: Delete :execrows
+UPDATE forms SET deleted_at = now(), version = version + 1
+WHERE id = $1 AND deleted_at IS NULL
+  AND ($2::int IS NULL OR version = $2::int)
+`
+
+type DeleteParams struct {
+	ID              uuid.UUID
+	ExpectedVersion pgtype.Int4
+}
+
+func (q *Queries) Delete(ctx context.Context, arg DeleteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, delete, arg.ID, arg.ExpectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteCollaborator = `-- name: DeleteCollaborator :execrows
+DELETE FROM form_collaborators
+WHERE form_id = $1 AND email = $2
+`
+
+type DeleteCollaboratorParams struct {
+	FormID uuid.UUID
+	Email  string
+}
+
+func (q *Queries) DeleteCollaborator(ctx context.Context, arg DeleteCollaboratorParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCollaborator, arg.FormID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteQuestion = `-- name: DeleteQuestion :execrows
+WITH touched AS (
+    UPDATE forms SET version = version + 1
+    WHERE id IN (SELECT q.form_id FROM questions q WHERE q.id = $1 AND q.form_id = $2)
+)
+DELETE FROM questions
+WHERE id = $1 AND form_id = $2
+`
+
+type DeleteQuestionParams struct {
+	ID     uuid.UUID
+	FormID uuid.UUID
+}
+
+func (q *Queries) DeleteQuestion(ctx context.Context, arg DeleteQuestionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteQuestion, arg.ID, arg.FormID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteQuestionsExcept = `-- name: DeleteQuestionsExcept :execrows
+DELETE FROM questions
+WHERE form_id = $1 AND NOT (id = ANY ($2::uuid[]))
+`
+
+type DeleteQuestionsExceptParams struct {
+	FormID  uuid.UUID
+	KeepIds []uuid.UUID
+}
+
+func (q *Queries) DeleteQuestionsExcept(ctx context.Context, arg DeleteQuestionsExceptParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteQuestionsExcept, arg.FormID, arg.KeepIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteSection = `-- name: DeleteSection :execrows
+WITH touched AS (
+    UPDATE forms SET version = version + 1
+    WHERE id IN (SELECT s.form_id FROM form_sections s WHERE s.id = $1 AND s.form_id = $2)
+)
+DELETE FROM form_sections
+WHERE id = $1 AND form_id = $2
+`
+
+type DeleteSectionParams struct {
+	ID     uuid.UUID
+	FormID uuid.UUID
+}
+
+func (q *Queries) DeleteSection(ctx context.Context, arg DeleteSectionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSection, arg.ID, arg.FormID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteSectionsExcept = `-- name: DeleteSectionsExcept :execrows
+DELETE FROM form_sections
+WHERE form_id = $1 AND NOT (id = ANY ($2::uuid[]))
+`
+
+type DeleteSectionsExceptParams struct {
+	FormID  uuid.UUID
+	KeepIds []uuid.UUID
+}
+
+func (q *Queries) DeleteSectionsExcept(ctx context.Context, arg DeleteSectionsExceptParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSectionsExcept, arg.FormID, arg.KeepIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const get = `-- name: Get :one
+SELECT id, title, description, author_id, created_at, status, deleted_at, version, is_quiz, reveal_answers, template_visibility FROM forms
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) Get(ctx context.Context, id uuid.UUID) (Form, error) {
+	row := q.db.QueryRow(ctx, get, id)
+	var i Form
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.IsQuiz,
+		&i.RevealAnswers,
+		&i.TemplateVisibility,
+	)
+	return i, err
+}
+
+const getCollaboratorRole = `-- name: GetCollaboratorRole :one
+SELECT role FROM form_collaborators
+WHERE form_id = $1 AND user_id = $2::uuid
+`
+
+type GetCollaboratorRoleParams struct {
+	FormID uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetCollaboratorRole(ctx context.Context, arg GetCollaboratorRoleParams) (string, error) {
+	row := q.db.QueryRow(ctx, getCollaboratorRole, arg.FormID, arg.UserID)
+	var role string
+	err := row.Scan(&role)
+	return role, err
+}
+
+const getDeleted = `-- name: GetDeleted :one
+SELECT id, title, description, author_id, created_at, status, deleted_at, version, is_quiz, reveal_answers, template_visibility FROM forms
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) GetDeleted(ctx context.Context, id uuid.UUID) (Form, error) {
+	row := q.db.QueryRow(ctx, getDeleted, id)
+	var i Form
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.IsQuiz,
+		&i.RevealAnswers,
+		&i.TemplateVisibility,
+	)
+	return i, err
+}
+
+const getRevision = `-- name: GetRevision :one
+SELECT id, form_id, number, snapshot, created_by, created_at FROM form_revisions
+WHERE form_id = $1 AND number = $2
+`
+
+type GetRevisionParams struct {
+	FormID uuid.UUID
+	Number int32
+}
+
+func (q *Queries) GetRevision(ctx context.Context, arg GetRevisionParams) (FormRevision, error) {
+	row := q.db.QueryRow(ctx, getRevision, arg.FormID, arg.Number)
+	var i FormRevision
+	err := row.Scan(
+		&i.ID,
+		&i.FormID,
+		&i.Number,
+		&i.Snapshot,
+		&i.CreatedBy,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
-const delete = `-- name: Delete :exec
-DELETE FROM forms WHERE id = $1
+const importQuestion = `-- name: ImportQuestion :execrows
+INSERT INTO questions (id, form_id, type, title, description, required, position, settings, section_id, rules, points, answer_key)
+VALUES ($1, $2, $3, $4, $5, $6,
+        $7, $8, $9, $10, $11, $12)
+ON CONFLICT (id) DO UPDATE
+SET type        = excluded.type,
+    title       = excluded.title,
+    description = excluded.description,
+    required    = excluded.required,
+    position    = excluded.position,
+    settings    = excluded.settings,
+    section_id  = excluded.section_id,
+    rules       = excluded.rules,
+    points      = excluded.points,
+    answer_key  = excluded.answer_key,
+    updated_at  = now()
+WHERE questions.form_id = excluded.form_id
+`
+
+type ImportQuestionParams struct {
+	ID          uuid.UUID
+	FormID      uuid.UUID
+	Type        string
+	Title       string
+	Description pgtype.Text
+	Required    bool
+	Position    int32
+	Settings    []byte
+	SectionID   pgtype.UUID
+	Rules       []byte
+	Points      float64
+	AnswerKey   []byte
+}
+
+func (q *Queries) ImportQuestion(ctx context.Context, arg ImportQuestionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, importQuestion,
+		arg.ID,
+		arg.FormID,
+		arg.Type,
+		arg.Title,
+		arg.Description,
+		arg.Required,
+		arg.Position,
+		arg.Settings,
+		arg.SectionID,
+		arg.Rules,
+		arg.Points,
+		arg.AnswerKey,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const importSection = `-- name: ImportSection :execrows
+INSERT INTO form_sections (id, form_id, title, description, position, rules)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id) DO UPDATE
+SET title       = excluded.title,
+    description = excluded.description,
+    position    = excluded.position,
+    rules       = excluded.rules,
+    updated_at  = now()
+WHERE form_sections.form_id = excluded.form_id
 `
 
-func (q *Queries) Delete(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, delete, id)
-	return err
+type ImportSectionParams struct {
+	ID          uuid.UUID
+	FormID      uuid.UUID
+	Title       string
+	Description pgtype.Text
+	Position    int32
+	Rules       []byte
+}
+
+func (q *Queries) ImportSection(ctx context.Context, arg ImportSectionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, importSection,
+		arg.ID,
+		arg.FormID,
+		arg.Title,
+		arg.Description,
+		arg.Position,
+		arg.Rules,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const isBookmarked = `-- name: IsBookmarked :one
@@ -65,26 +650,69 @@ func (q *Queries) IsBookmarked(ctx context.Context, arg IsBookmarkedParams) (boo
 	return exists, err
 }
 
-const list = `-- name: List :many
-SELECT id, title, description, author_id, created_at FROM forms
-ORDER BY id
+const listCollaborators = `-- name: ListCollaborators :many
+SELECT form_id, email, user_id, role, invited_by, created_at FROM form_collaborators
+WHERE form_id = $1
+ORDER BY created_at, email
 `
 
-func (q *Queries) List(ctx context.Context) ([]Form, error) {
-	rows, err := q.db.Query(ctx, list)
+func (q *Queries) ListCollaborators(ctx context.Context, formID uuid.UUID) ([]FormCollaborator, error) {
+	rows, err := q.db.Query(ctx, listCollaborators, formID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Form
+	var items []FormCollaborator
 	for rows.Next() {
-		var i Form
+		var i FormCollaborator
+		if err := rows.Scan(
+			&i.FormID,
+			&i.Email,
+			&i.UserID,
+			&i.Role,
+			&i.InvitedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQuestions = `-- name: ListQuestions :many
+SELECT id, form_id, type, title, description, required, position, settings, created_at, updated_at, section_id, rules, points, answer_key FROM questions
+WHERE form_id = $1
+ORDER BY position, created_at
+`
+
+func (q *Queries) ListQuestions(ctx context.Context, formID uuid.UUID) ([]Question, error) {
+	rows, err := q.db.Query(ctx, listQuestions, formID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Question
+	for rows.Next() {
+		var i Question
 		if err := rows.Scan(
 			&i.ID,
+			&i.FormID,
+			&i.Type,
 			&i.Title,
 			&i.Description,
-			&i.AuthorID,
+			&i.Required,
+			&i.Position,
+			&i.Settings,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SectionID,
+			&i.Rules,
+			&i.Points,
+			&i.AnswerKey,
 		); err != nil {
 			return nil, err
 		}
@@ -96,27 +724,774 @@ func (q *Queries) List(ctx context.Context) ([]Form, error) {
 	return items, nil
 }
 
-const update = `-- name: Update :one
-UPDATE forms SET title = $2, description = $3
-where id = $1
-RETURNING id, title, description, author_id, created_at
+const listRevisions = `-- name: ListRevisions :many
+SELECT id, form_id, number, snapshot, created_by, created_at FROM form_revisions
+WHERE form_id = $1
+ORDER BY number DESC
 `
 
-type UpdateParams struct {
-	ID          uuid.UUID
-	Title       string
-	Description pgtype.Text
-}
-
-func (q *Queries) Update(ctx context.Context, arg UpdateParams) (Form, error) {
-	row := q.db.QueryRow(ctx, update, arg.ID, arg.Title, arg.Description)
-	var i Form
-	err := row.Scan(
-		&i.ID,
-		&i.Title,
-		&i.Description,
-		&i.AuthorID,
+func (q *Queries) ListRevisions(ctx context.Context, formID uuid.UUID) ([]FormRevision, error) {
+	rows, err := q.db.Query(ctx, listRevisions, formID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FormRevision
+	for rows.Next() {
+		var i FormRevision
+		if err := rows.Scan(
+			&i.ID,
+			&i.FormID,
+			&i.Number,
+			&i.Snapshot,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSections = `-- name: ListSections :many
+SELECT id, form_id, title, description, position, rules, created_at, updated_at FROM form_sections
+WHERE form_id = $1
+ORDER BY position, created_at
+`
+
+func (q *Queries) ListSections(ctx context.Context, formID uuid.UUID) ([]FormSection, error) {
+	rows, err := q.db.Query(ctx, listSections, formID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FormSection
+	for rows.Next() {
+		var i FormSection
+		if err := rows.Scan(
+			&i.ID,
+			&i.FormID,
+			&i.Title,
+			&i.Description,
+			&i.Position,
+			&i.Rules,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTemplates = `-- name: ListTemplates :many
+SELECT f.id, f.title, f.description, f.author_id, f.created_at, f.status, f.deleted_at, f.version, f.is_quiz, f.reveal_answers, f.template_visibility FROM forms f
+WHERE f.template_visibility IS NOT NULL
+  AND f.deleted_at IS NULL
+  AND f.status <> 'archived'
+  AND (f.status <> 'draft' OR f.author_id::text = $1::uuid::text OR EXISTS (
+        SELECT 1 FROM form_collaborators c
+        WHERE c.form_id = f.id AND c.user_id = $1::uuid AND c.role = 'owner'))
+  AND (f.template_visibility = 'public'
+       OR f.author_id::text = $1::uuid::text
+       OR EXISTS (
+        SELECT 1 FROM form_collaborators c
+        WHERE c.form_id = f.id AND c.user_id = $1::uuid
+          AND (f.template_visibility = 'team' OR c.role = 'owner')))
+  AND ($2::uuid IS NULL
+       OR (f.created_at, f.id) < ($3::timestamptz, $2::uuid))
+ORDER BY f.created_at DESC, f.id DESC
+LIMIT $4
+`
+
+type ListTemplatesParams struct {
+	UserID          uuid.UUID
+	CursorID        pgtype.UUID
+	CursorCreatedAt pgtype.Timestamptz
+	PageLimit       int32
+}
+
+func (q *Queries) ListTemplates(ctx context.Context, arg ListTemplatesParams) ([]Form, error) {
+	rows, err := q.db.Query(ctx, listTemplates,
+		arg.UserID,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Form
+	for rows.Next() {
+		var i Form
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.AuthorID,
+			&i.CreatedAt,
+			&i.Status,
+			&i.DeletedAt,
+			&i.Version,
+			&i.IsQuiz,
+			&i.RevealAnswers,
+			&i.TemplateVisibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransitions = `-- name: ListTransitions :many
+SELECT id, form_id, from_status, to_status, changed_by, changed_at FROM form_status_transitions
+WHERE form_id = $1
+ORDER BY changed_at, id
+`
+
+func (q *Queries) ListTransitions(ctx context.Context, formID uuid.UUID) ([]FormStatusTransition, error) {
+	rows, err := q.db.Query(ctx, listTransitions, formID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FormStatusTransition
+	for rows.Next() {
+		var i FormStatusTransition
+		if err := rows.Scan(
+			&i.ID,
+			&i.FormID,
+			&i.FromStatus,
+			&i.ToStatus,
+			&i.ChangedBy,
+			&i.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrash = `-- name: ListTrash :many
+SELECT f.id, f.title, f.description, f.author_id, f.created_at, f.status, f.deleted_at, f.version, f.is_quiz, f.reveal_answers, f.template_visibility FROM forms f
+WHERE f.deleted_at IS NOT NULL
+  AND (f.author_id::text = $1::uuid::text OR EXISTS (
+        SELECT 1 FROM form_collaborators c
+        WHERE c.form_id = f.id AND c.user_id = $1::uuid AND c.role = 'owner'))
+  AND ($2::uuid IS NULL
+       OR (f.deleted_at, f.id) < ($3::timestamptz, $2::uuid))
+ORDER BY f.deleted_at DESC, f.id DESC
+LIMIT $4
+`
+
+type ListTrashParams struct {
+	UserID          uuid.UUID
+	CursorID        pgtype.UUID
+	CursorDeletedAt pgtype.Timestamptz
+	PageLimit       int32
+}
+
+func (q *Queries) ListTrash(ctx context.Context, arg ListTrashParams) ([]Form, error) {
+	rows, err := q.db.Query(ctx, listTrash,
+		arg.UserID,
+		arg.CursorID,
+		arg.CursorDeletedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Form
+	for rows.Next() {
+		var i Form
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.AuthorID,
+			&i.CreatedAt,
+			&i.Status,
+			&i.DeletedAt,
+			&i.Version,
+			&i.IsQuiz,
+			&i.RevealAnswers,
+			&i.TemplateVisibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWithBookmarks = `-- name: ListWithBookmarks :many
+SELECT f.id, f.title, f.description, f.author_id, f.created_at, f.status,
+       COALESCE(bool_or(b.user_id = $1::uuid), false)::bool AS is_bookmarked,
+       COUNT(b.user_id) AS bookmark_count
+FROM forms f
+LEFT JOIN bookmarks b ON b.form_id = f.id
+WHERE ($2::text IS NULL OR f.author_id::text = $2::text)
+  AND ($3::uuid IS NULL OR EXISTS (
+        SELECT 1 FROM bookmarks mb
+        WHERE mb.form_id = f.id AND mb.user_id = $3::uuid))
+  AND ($4::timestamptz IS NULL OR f.created_at >= $4::timestamptz)
+  AND ($5::timestamptz IS NULL OR f.created_at < $5::timestamptz)
+  AND f.deleted_at IS NULL
+  AND f.status <> 'archived'
+  AND (f.status <> 'draft' OR f.author_id::text = $1::uuid::text OR EXISTS (
+        SELECT 1 FROM form_collaborators c
+        WHERE c.form_id = f.id AND c.user_id = $1::uuid AND c.role = 'owner'))
+  AND ($6::uuid IS NULL OR CASE
+        WHEN $7::text = 'title' AND $8::bool
+            THEN (f.title, f.id) < ($9::text, $6::uuid)
+        WHEN $7::text = 'title'
+            THEN (f.title, f.id) > ($9::text, $6::uuid)
+        WHEN $8::bool
+            THEN (f.created_at, f.id) < ($10::timestamptz, $6::uuid)
+        ELSE (f.created_at, f.id) > ($10::timestamptz, $6::uuid)
+      END)
+GROUP BY f.id
+ORDER BY
+    CASE WHEN $7::text = 'title' AND NOT $8::bool THEN f.title END,
+    CASE WHEN $7::text = 'title' AND $8::bool THEN f.title END DESC,
+    CASE WHEN $7::text <> 'title' AND NOT $8::bool THEN f.created_at END,
+    CASE WHEN $7::text <> 'title' AND $8::bool THEN f.created_at END DESC,
+    CASE WHEN NOT $8::bool THEN f.id END,
+    CASE WHEN $8::bool THEN f.id END DESC
+LIMIT $11
+`
+
+type ListWithBookmarksParams struct {
+	UserID          uuid.UUID
+	AuthorID        pgtype.Text
+	BookmarkedBy    pgtype.UUID
+	CreatedAfter    pgtype.Timestamptz
+	CreatedBefore   pgtype.Timestamptz
+	CursorID        pgtype.UUID
+	SortBy          string
+	Descending      bool
+	CursorTitle     string
+	CursorCreatedAt pgtype.Timestamptz
+	PageLimit       int32
+}
+
+type ListWithBookmarksRow struct {
+	ID            uuid.UUID
+	Title         string
+	Description   pgtype.Text
+	AuthorID      pgtype.Text
+	CreatedAt     pgtype.Timestamptz
+	Status        string
+	IsBookmarked  bool
+	BookmarkCount int64
+}
+
+func (q *Queries) ListWithBookmarks(ctx context.Context, arg ListWithBookmarksParams) ([]ListWithBookmarksRow, error) {
+	rows, err := q.db.Query(ctx, listWithBookmarks,
+		arg.UserID,
+		arg.AuthorID,
+		arg.BookmarkedBy,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorID,
+		arg.SortBy,
+		arg.Descending,
+		arg.CursorTitle,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWithBookmarksRow
+	for rows.Next() {
+		var i ListWithBookmarksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.AuthorID,
+			&i.CreatedAt,
+			&i.Status,
+			&i.IsBookmarked,
+			&i.BookmarkCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeleted = `-- name: PurgeDeleted :execrows
+DELETE FROM forms
+WHERE deleted_at IS NOT NULL AND deleted_at < $1::timestamptz
+`
+
+func (q *Queries) PurgeDeleted(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeleted, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restore = `-- name: Restore :one
+UPDATE forms SET deleted_at = NULL, version = version + 1
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, title, description, author_id, created_at, status, deleted_at, version, is_quiz, reveal_answers, template_visibility
+`
+
+func (q *Queries) Restore(ctx context.Context, id uuid.UUID) (Form, error) {
+	row := q.db.QueryRow(ctx, restore, id)
+	var i Form
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.IsQuiz,
+		&i.RevealAnswers,
+		&i.TemplateVisibility,
+	)
+	return i, err
+}
+
+const search = `-- name: Search :many
+SELECT f.id, f.title, f.description, f.author_id, f.created_at,
+       ts_rank(d.document, q.query) AS rank,
+       ts_headline('simple', f.title, q.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS title_highlight,
+       ts_headline('simple', d.body, q.query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5') AS snippet
+FROM form_search_documents d
+JOIN forms f ON f.id = d.form_id
+CROSS JOIN websearch_to_tsquery('simple', $1) AS q(query)
+WHERE d.document @@ q.query
+  AND f.deleted_at IS NULL
+  AND f.status <> 'archived'
+  AND (f.status <> 'draft' OR f.author_id::text = $2::uuid::text OR EXISTS (
+        SELECT 1 FROM form_collaborators c
+        WHERE c.form_id = f.id AND c.user_id = $2::uuid AND c.role = 'owner'))
+  AND ($3::uuid IS NULL
+       OR (ts_rank(d.document, q.query), f.id) < ($4::real, $3::uuid))
+ORDER BY rank DESC, f.id DESC
+LIMIT $5
+`
+
+type SearchParams struct {
+	Query      string
+	UserID     uuid.UUID
+	CursorID   pgtype.UUID
+	CursorRank float32
+	PageLimit  int32
+}
+
+type SearchRow struct {
+	ID             uuid.UUID
+	Title          string
+	Description    pgtype.Text
+	AuthorID       pgtype.Text
+	CreatedAt      pgtype.Timestamptz
+	Rank           float32
+	TitleHighlight string
+	Snippet        string
+}
+
+func (q *Queries) Search(ctx context.Context, arg SearchParams) ([]SearchRow, error) {
+	rows, err := q.db.Query(ctx, search,
+		arg.Query,
+		arg.UserID,
+		arg.CursorID,
+		arg.CursorRank,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchRow
+	for rows.Next() {
+		var i SearchRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.AuthorID,
+			&i.CreatedAt,
+			&i.Rank,
+			&i.TitleHighlight,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setQuiz = `-- name: SetQuiz :one
+UPDATE forms SET is_quiz = $1, reveal_answers = $2, version = version + 1
+WHERE id = $3 AND deleted_at IS NULL
+  AND ($4::int IS NULL OR version = $4::int)
+RETURNING id, title, description, author_id, created_at, status, deleted_at, version, is_quiz, reveal_answers, template_visibility
+`
+
+type SetQuizParams struct {
+	IsQuiz          bool
+	RevealAnswers   string
+	ID              uuid.UUID
+	ExpectedVersion pgtype.Int4
+}
+
+func (q *Queries) SetQuiz(ctx context.Context, arg SetQuizParams) (Form, error) {
+	row := q.db.QueryRow(ctx, setQuiz,
+		arg.IsQuiz,
+		arg.RevealAnswers,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	var i Form
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.IsQuiz,
+		&i.RevealAnswers,
+		&i.TemplateVisibility,
+	)
+	return i, err
+}
+
+const setTemplate = `-- name: SetTemplate :one
+UPDATE forms SET template_visibility = $1, version = version + 1
+WHERE id = $2 AND deleted_at IS NULL
+  AND ($3::int IS NULL OR version = $3::int)
+RETURNING id, title, description, author_id, created_at, status, deleted_at, version, is_quiz, reveal_answers, template_visibility
+`
+
+type SetTemplateParams struct {
+	TemplateVisibility pgtype.Text
+	ID                 uuid.UUID
+	ExpectedVersion    pgtype.Int4
+}
+
+func (q *Queries) SetTemplate(ctx context.Context, arg SetTemplateParams) (Form, error) {
+	row := q.db.QueryRow(ctx, setTemplate, arg.TemplateVisibility, arg.ID, arg.ExpectedVersion)
+	var i Form
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.IsQuiz,
+		&i.RevealAnswers,
+		&i.TemplateVisibility,
+	)
+	return i, err
+}
+
+const transition = `-- name: Transition :one
+WITH updated AS (
+    UPDATE forms SET status = $1::text, version = version + 1
+    WHERE id = $2 AND status = $3::text
+    RETURNING id
+)
+INSERT INTO form_status_transitions (form_id, from_status, to_status, changed_by)
+SELECT id, $3::text, $1::text, $4::uuid FROM updated
+RETURNING id, form_id, from_status, to_status, changed_by, changed_at
+`
+
+type TransitionParams struct {
+	ToStatus   string
+	FormID     uuid.UUID
+	FromStatus string
+	ChangedBy  uuid.UUID
+}
+
+func (q *Queries) Transition(ctx context.Context, arg TransitionParams) (FormStatusTransition, error) {
+	row := q.db.QueryRow(ctx, transition,
+		arg.ToStatus,
+		arg.FormID,
+		arg.FromStatus,
+		arg.ChangedBy,
+	)
+	var i FormStatusTransition
+	err := row.Scan(
+		&i.ID,
+		&i.FormID,
+		&i.FromStatus,
+		&i.ToStatus,
+		&i.ChangedBy,
+		&i.ChangedAt,
+	)
+	return i, err
+}
+
+const update = `-- name: Update :one
+UPDATE forms SET title = $1, description = $2, version = version + 1
+where id = $3 AND deleted_at IS NULL
+  AND ($4::int IS NULL OR version = $4::int)
+RETURNING id, title, description, author_id, created_at, status, deleted_at, version, is_quiz, reveal_answers, template_visibility
+`
+
+type UpdateParams struct {
+	Title           string
+	Description     pgtype.Text
+	ID              uuid.UUID
+	ExpectedVersion pgtype.Int4
+}
+
+func (q *Queries) Update(ctx context.Context, arg UpdateParams) (Form, error) {
+	row := q.db.QueryRow(ctx, update,
+		arg.Title,
+		arg.Description,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	var i Form
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.IsQuiz,
+		&i.RevealAnswers,
+		&i.TemplateVisibility,
+	)
+	return i, err
+}
+
+const updateCollaboratorRole = `-- name: UpdateCollaboratorRole :one
+UPDATE form_collaborators SET role = $3
+WHERE form_id = $1 AND email = $2
+RETURNING form_id, email, user_id, role, invited_by, created_at
+`
+
+type UpdateCollaboratorRoleParams struct {
+	FormID uuid.UUID
+	Email  string
+	Role   string
+}
+
+func (q *Queries) UpdateCollaboratorRole(ctx context.Context, arg UpdateCollaboratorRoleParams) (FormCollaborator, error) {
+	row := q.db.QueryRow(ctx, updateCollaboratorRole, arg.FormID, arg.Email, arg.Role)
+	var i FormCollaborator
+	err := row.Scan(
+		&i.FormID,
+		&i.Email,
+		&i.UserID,
+		&i.Role,
+		&i.InvitedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateDefinition = `-- name: UpdateDefinition :one
+UPDATE forms
+SET title          = $1,
+    description    = $2,
+    is_quiz        = $3,
+    reveal_answers = $4,
+    version        = version + 1
+WHERE id = $5 AND deleted_at IS NULL
+  AND ($6::int IS NULL OR version = $6::int)
+RETURNING id, title, description, author_id, created_at, status, deleted_at, version, is_quiz, reveal_answers, template_visibility
+`
+
+type UpdateDefinitionParams struct {
+	Title           string
+	Description     pgtype.Text
+	IsQuiz          bool
+	RevealAnswers   string
+	ID              uuid.UUID
+	ExpectedVersion pgtype.Int4
+}
+
+func (q *Queries) UpdateDefinition(ctx context.Context, arg UpdateDefinitionParams) (Form, error) {
+	row := q.db.QueryRow(ctx, updateDefinition,
+		arg.Title,
+		arg.Description,
+		arg.IsQuiz,
+		arg.RevealAnswers,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	var i Form
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.IsQuiz,
+		&i.RevealAnswers,
+		&i.TemplateVisibility,
+	)
+	return i, err
+}
+
+const updateQuestion = `-- name: UpdateQuestion :one
+WITH touched AS (
+    UPDATE forms SET version = version + 1
+    WHERE id IN (SELECT q.form_id FROM questions q WHERE q.id = $1 AND q.form_id = $2)
+)
+UPDATE questions
+SET type        = $3,
+    title       = $4,
+    description = $5,
+    required    = $6,
+    position    = COALESCE($7, position),
+    settings    = $8,
+    section_id  = $9,
+    rules       = $10,
+    points      = $11,
+    answer_key  = $12,
+    updated_at  = now()
+WHERE id = $1 AND form_id = $2
+RETURNING id, form_id, type, title, description, required, position, settings, created_at, updated_at, section_id, rules, points, answer_key
+`
+
+type UpdateQuestionParams struct {
+	ID          uuid.UUID
+	FormID      uuid.UUID
+	Type        string
+	Title       string
+	Description pgtype.Text
+	Required    bool
+	Position    pgtype.Int4
+	Settings    []byte
+	SectionID   pgtype.UUID
+	Rules       []byte
+	Points      float64
+	AnswerKey   []byte
+}
+
+func (q *Queries) UpdateQuestion(ctx context.Context, arg UpdateQuestionParams) (Question, error) {
+	row := q.db.QueryRow(ctx, updateQuestion,
+		arg.ID,
+		arg.FormID,
+		arg.Type,
+		arg.Title,
+		arg.Description,
+		arg.Required,
+		arg.Position,
+		arg.Settings,
+		arg.SectionID,
+		arg.Rules,
+		arg.Points,
+		arg.AnswerKey,
+	)
+	var i Question
+	err := row.Scan(
+		&i.ID,
+		&i.FormID,
+		&i.Type,
+		&i.Title,
+		&i.Description,
+		&i.Required,
+		&i.Position,
+		&i.Settings,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SectionID,
+		&i.Rules,
+		&i.Points,
+		&i.AnswerKey,
+	)
+	return i, err
+}
+
+const updateSection = `-- name: UpdateSection :one
+WITH touched AS (
+    UPDATE forms SET version = version + 1
+    WHERE id IN (SELECT s.form_id FROM form_sections s WHERE s.id = $1 AND s.form_id = $2)
+)
+UPDATE form_sections
+SET title       = $3,
+    description = $4,
+    position    = COALESCE($5, position),
+    rules       = $6,
+    updated_at  = now()
+WHERE id = $1 AND form_id = $2
+RETURNING id, form_id, title, description, position, rules, created_at, updated_at
+`
+
+type UpdateSectionParams struct {
+	ID          uuid.UUID
+	FormID      uuid.UUID
+	Title       string
+	Description pgtype.Text
+	Position    pgtype.Int4
+	Rules       []byte
+}
+
+func (q *Queries) UpdateSection(ctx context.Context, arg UpdateSectionParams) (FormSection, error) {
+	row := q.db.QueryRow(ctx, updateSection,
+		arg.ID,
+		arg.FormID,
+		arg.Title,
+		arg.Description,
+		arg.Position,
+		arg.Rules,
+	)
+	var i FormSection
+	err := row.Scan(
+		&i.ID,
+		&i.FormID,
+		&i.Title,
+		&i.Description,
+		&i.Position,
+		&i.Rules,
 		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
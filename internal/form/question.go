@@ -0,0 +1,261 @@
+package form
+
+import (
+	"awesomeProject/internal/form/logic"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+type QuestionType string
+
+const (
+	QuestionTypeShortText      QuestionType = "short_text"
+	QuestionTypeParagraph      QuestionType = "paragraph"
+	QuestionTypeSingleChoice   QuestionType = "single_choice"
+	QuestionTypeMultipleChoice QuestionType = "multiple_choice"
+	QuestionTypeNumber         QuestionType = "number"
+	QuestionTypeDate           QuestionType = "date"
+)
+
+// DateLayout is the format used for date answers and date bounds.
+const DateLayout = time.DateOnly
+
+// QuestionSettings holds the per-type configuration of a question.
+//
+// Min and Max bound the answer length for text questions, the value for
+// number questions and the number of selections for multiple choice
+// questions. Date questions use MinDate and MaxDate instead.
+type QuestionSettings struct {
+	Choices []string `json:"choices,omitempty"`
+	Min     *float64 `json:"min,omitempty"`
+	Max     *float64 `json:"max,omitempty"`
+	MinDate string   `json:"min_date,omitempty"`
+	MaxDate string   `json:"max_date,omitempty"`
+	Pattern string   `json:"pattern,omitempty"`
+}
+
+// QuestionInput is the user supplied part of a question, shared by create and update.
+type QuestionInput struct {
+	Type        QuestionType
+	Title       string
+	Description string
+	Required    bool
+	Position    *int32
+	Settings    QuestionSettings
+	// SectionID is nil for questions in front of the first section.
+	SectionID *uuid.UUID
+	Rules     logic.QuestionRules
+	// Points and AnswerKey are only used when the form is a quiz. A question
+	// with points but no answer key is graded by hand.
+	Points    float64
+	AnswerKey json.RawMessage
+}
+
+func (t QuestionType) IsValid() bool {
+	switch t {
+	case QuestionTypeShortText, QuestionTypeParagraph, QuestionTypeSingleChoice,
+		QuestionTypeMultipleChoice, QuestionTypeNumber, QuestionTypeDate:
+		return true
+	}
+	return false
+}
+
+func (t QuestionType) IsChoice() bool {
+	return t == QuestionTypeSingleChoice || t == QuestionTypeMultipleChoice
+}
+
+func (t QuestionType) IsText() bool {
+	return t == QuestionTypeShortText || t == QuestionTypeParagraph
+}
+
+// Validate checks that the settings make sense for the given question type.
+func (s QuestionSettings) Validate(t QuestionType) error {
+	if !t.IsValid() {
+		return fmt.Errorf("%w: unknown question type %q", ErrInvalidQuestion, t)
+	}
+
+	if t.IsChoice() {
+		if len(s.Choices) == 0 {
+			return fmt.Errorf("%w: %s question requires choices", ErrInvalidQuestion, t)
+		}
+		seen := make(map[string]bool, len(s.Choices))
+		for _, choice := range s.Choices {
+			if choice == "" {
+				return fmt.Errorf("%w: choices must not be empty", ErrInvalidQuestion)
+			}
+			if seen[choice] {
+				return fmt.Errorf("%w: duplicate choice %q", ErrInvalidQuestion, choice)
+			}
+			seen[choice] = true
+		}
+	} else if len(s.Choices) > 0 {
+		return fmt.Errorf("%w: choices are only allowed on choice questions", ErrInvalidQuestion)
+	}
+
+	if s.Pattern != "" {
+		if !t.IsText() {
+			return fmt.Errorf("%w: pattern is only allowed on text questions", ErrInvalidQuestion)
+		}
+		if _, err := regexp.Compile(s.Pattern); err != nil {
+			return fmt.Errorf("%w: invalid pattern: %v", ErrInvalidQuestion, err)
+		}
+	}
+
+	if s.Min != nil || s.Max != nil {
+		if t == QuestionTypeSingleChoice || t == QuestionTypeDate {
+			return fmt.Errorf("%w: min and max are not allowed on %s questions", ErrInvalidQuestion, t)
+		}
+		if s.Min != nil && s.Max != nil && *s.Min > *s.Max {
+			return fmt.Errorf("%w: min must not be greater than max", ErrInvalidQuestion)
+		}
+		if t != QuestionTypeNumber && ((s.Min != nil && *s.Min < 0) || (s.Max != nil && *s.Max < 0)) {
+			return fmt.Errorf("%w: min and max must not be negative", ErrInvalidQuestion)
+		}
+	}
+
+	if s.MinDate != "" || s.MaxDate != "" {
+		if t != QuestionTypeDate {
+			return fmt.Errorf("%w: min_date and max_date are only allowed on date questions", ErrInvalidQuestion)
+		}
+		var minDate, maxDate time.Time
+		var err error
+		if s.MinDate != "" {
+			if minDate, err = time.Parse(DateLayout, s.MinDate); err != nil {
+				return fmt.Errorf("%w: invalid min_date %q", ErrInvalidQuestion, s.MinDate)
+			}
+		}
+		if s.MaxDate != "" {
+			if maxDate, err = time.Parse(DateLayout, s.MaxDate); err != nil {
+				return fmt.Errorf("%w: invalid max_date %q", ErrInvalidQuestion, s.MaxDate)
+			}
+		}
+		if s.MinDate != "" && s.MaxDate != "" && minDate.After(maxDate) {
+			return fmt.Errorf("%w: min_date must not be after max_date", ErrInvalidQuestion)
+		}
+	}
+
+	return nil
+}
+
+// ParseSettings decodes the settings column of a question.
+func (q Question) ParseSettings() (QuestionSettings, error) {
+	var settings QuestionSettings
+	if len(q.Settings) == 0 {
+		return settings, nil
+	}
+	err := json.Unmarshal(q.Settings, &settings)
+	return settings, err
+}
+
+// IsEmptyAnswer reports whether a raw answer should be treated as not answered.
+func IsEmptyAnswer(value json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(value)
+	return len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) || bytes.Equal(trimmed, []byte(`""`)) || bytes.Equal(trimmed, []byte("[]"))
+}
+
+// ValidateAnswer checks a non-empty raw JSON answer against the question definition.
+//
+// Text, choice and date answers are JSON strings, multiple choice answers are
+// arrays of strings and number answers are JSON numbers. A pattern must match
+// the whole text answer.
+func (q Question) ValidateAnswer(value json.RawMessage) error {
+	settings, err := q.ParseSettings()
+	if err != nil {
+		return err
+	}
+
+	switch QuestionType(q.Type) {
+	case QuestionTypeShortText, QuestionTypeParagraph:
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			return fmt.Errorf("%w: expected a string", ErrInvalidAnswer)
+		}
+		length := float64(utf8.RuneCountInString(text))
+		if settings.Min != nil && length < *settings.Min {
+			return fmt.Errorf("%w: must be at least %g characters", ErrInvalidAnswer, *settings.Min)
+		}
+		if settings.Max != nil && length > *settings.Max {
+			return fmt.Errorf("%w: must be at most %g characters", ErrInvalidAnswer, *settings.Max)
+		}
+		if settings.Pattern != "" {
+			pattern, err := regexp.Compile("^(?:" + settings.Pattern + ")$")
+			if err != nil {
+				return err
+			}
+			if !pattern.MatchString(text) {
+				return fmt.Errorf("%w: does not match the required format", ErrInvalidAnswer)
+			}
+		}
+
+	case QuestionTypeSingleChoice:
+		var choice string
+		if err := json.Unmarshal(value, &choice); err != nil {
+			return fmt.Errorf("%w: expected a string", ErrInvalidAnswer)
+		}
+		if !slices.Contains(settings.Choices, choice) {
+			return fmt.Errorf("%w: %q is not one of the choices", ErrInvalidAnswer, choice)
+		}
+
+	case QuestionTypeMultipleChoice:
+		var choices []string
+		if err := json.Unmarshal(value, &choices); err != nil {
+			return fmt.Errorf("%w: expected an array of strings", ErrInvalidAnswer)
+		}
+		seen := make(map[string]bool, len(choices))
+		for _, choice := range choices {
+			if !slices.Contains(settings.Choices, choice) {
+				return fmt.Errorf("%w: %q is not one of the choices", ErrInvalidAnswer, choice)
+			}
+			if seen[choice] {
+				return fmt.Errorf("%w: %q is selected more than once", ErrInvalidAnswer, choice)
+			}
+			seen[choice] = true
+		}
+		count := float64(len(choices))
+		if settings.Min != nil && count < *settings.Min {
+			return fmt.Errorf("%w: select at least %g choices", ErrInvalidAnswer, *settings.Min)
+		}
+		if settings.Max != nil && count > *settings.Max {
+			return fmt.Errorf("%w: select at most %g choices", ErrInvalidAnswer, *settings.Max)
+		}
+
+	case QuestionTypeNumber:
+		var number float64
+		if err := json.Unmarshal(value, &number); err != nil {
+			return fmt.Errorf("%w: expected a number", ErrInvalidAnswer)
+		}
+		if settings.Min != nil && number < *settings.Min {
+			return fmt.Errorf("%w: must be at least %g", ErrInvalidAnswer, *settings.Min)
+		}
+		if settings.Max != nil && number > *settings.Max {
+			return fmt.Errorf("%w: must be at most %g", ErrInvalidAnswer, *settings.Max)
+		}
+
+	case QuestionTypeDate:
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			return fmt.Errorf("%w: expected a date string", ErrInvalidAnswer)
+		}
+		if _, err := time.Parse(DateLayout, text); err != nil {
+			return fmt.Errorf("%w: expected a date formatted as YYYY-MM-DD", ErrInvalidAnswer)
+		}
+		if settings.MinDate != "" && text < settings.MinDate {
+			return fmt.Errorf("%w: must not be before %s", ErrInvalidAnswer, settings.MinDate)
+		}
+		if settings.MaxDate != "" && text > settings.MaxDate {
+			return fmt.Errorf("%w: must not be after %s", ErrInvalidAnswer, settings.MaxDate)
+		}
+
+	default:
+		return fmt.Errorf("%w: unknown question type %q", ErrInvalidAnswer, q.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package form_test
+
+import (
+	"awesomeProject/internal/form"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func bound(value float64) *float64 {
+	return &value
+}
+
+func TestQuestionSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		questionType form.QuestionType
+		settings     form.QuestionSettings
+		expectError  bool
+	}{
+		{name: "Short text without settings", questionType: form.QuestionTypeShortText},
+		{name: "Paragraph with length bounds and pattern", questionType: form.QuestionTypeParagraph, settings: form.QuestionSettings{Min: bound(1), Max: bound(200), Pattern: `[a-z ]+`}},
+		{name: "Unknown type", questionType: "slider", expectError: true},
+		{name: "Invalid pattern", questionType: form.QuestionTypeShortText, settings: form.QuestionSettings{Pattern: `[a-z`}, expectError: true},
+		{name: "Negative text length", questionType: form.QuestionTypeShortText, settings: form.QuestionSettings{Min: bound(-1)}, expectError: true},
+		{name: "Choices on a text question", questionType: form.QuestionTypeShortText, settings: form.QuestionSettings{Choices: []string{"A"}}, expectError: true},
+		{name: "Single choice", questionType: form.QuestionTypeSingleChoice, settings: form.QuestionSettings{Choices: []string{"A", "B"}}},
+		{name: "Single choice without choices", questionType: form.QuestionTypeSingleChoice, expectError: true},
+		{name: "Empty choice", questionType: form.QuestionTypeSingleChoice, settings: form.QuestionSettings{Choices: []string{"A", ""}}, expectError: true},
+		{name: "Duplicate choice", questionType: form.QuestionTypeMultipleChoice, settings: form.QuestionSettings{Choices: []string{"A", "A"}}, expectError: true},
+		{name: "Min and max on a single choice", questionType: form.QuestionTypeSingleChoice, settings: form.QuestionSettings{Choices: []string{"A"}, Max: bound(1)}, expectError: true},
+		{name: "Multiple choice with selection bounds", questionType: form.QuestionTypeMultipleChoice, settings: form.QuestionSettings{Choices: []string{"A", "B", "C"}, Min: bound(1), Max: bound(2)}},
+		{name: "Pattern on a choice question", questionType: form.QuestionTypeMultipleChoice, settings: form.QuestionSettings{Choices: []string{"A"}, Pattern: `A`}, expectError: true},
+		{name: "Number with negative bounds", questionType: form.QuestionTypeNumber, settings: form.QuestionSettings{Min: bound(-10), Max: bound(10)}},
+		{name: "Number with min above max", questionType: form.QuestionTypeNumber, settings: form.QuestionSettings{Min: bound(10), Max: bound(1)}, expectError: true},
+		{name: "Date with bounds", questionType: form.QuestionTypeDate, settings: form.QuestionSettings{MinDate: "2024-01-01", MaxDate: "2024-12-31"}},
+		{name: "Date with min after max", questionType: form.QuestionTypeDate, settings: form.QuestionSettings{MinDate: "2024-12-31", MaxDate: "2024-01-01"}, expectError: true},
+		{name: "Malformed date bound", questionType: form.QuestionTypeDate, settings: form.QuestionSettings{MinDate: "01/01/2024"}, expectError: true},
+		{name: "Min and max on a date", questionType: form.QuestionTypeDate, settings: form.QuestionSettings{Min: bound(1)}, expectError: true},
+		{name: "Date bounds on a number", questionType: form.QuestionTypeNumber, settings: form.QuestionSettings{MinDate: "2024-01-01"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate(tt.questionType)
+			if tt.expectError {
+				assert.ErrorIs(t, err, form.ErrInvalidQuestion)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestQuestion_ValidateAnswer(t *testing.T) {
+	question := func(questionType form.QuestionType, settings form.QuestionSettings) form.Question {
+		raw, err := json.Marshal(settings)
+		assert.NoError(t, err)
+		return form.Question{Type: string(questionType), Settings: raw}
+	}
+
+	name := question(form.QuestionTypeShortText, form.QuestionSettings{Min: bound(2), Max: bound(5)})
+	code := question(form.QuestionTypeShortText, form.QuestionSettings{Pattern: `[A-Z]{3}`})
+	essay := question(form.QuestionTypeParagraph, form.QuestionSettings{Max: bound(3)})
+	color := question(form.QuestionTypeSingleChoice, form.QuestionSettings{Choices: []string{"Red", "Green"}})
+	toppings := question(form.QuestionTypeMultipleChoice, form.QuestionSettings{Choices: []string{"Ham", "Olives", "Onions"}, Min: bound(1), Max: bound(2)})
+	age := question(form.QuestionTypeNumber, form.QuestionSettings{Min: bound(0), Max: bound(120)})
+	birthday := question(form.QuestionTypeDate, form.QuestionSettings{MinDate: "1900-01-01", MaxDate: "2024-12-31"})
+
+	tests := []struct {
+		name        string
+		question    form.Question
+		answer      string
+		expectError bool
+	}{
+		{name: "Text within bounds", question: name, answer: `"Ann"`},
+		{name: "Text counts characters, not bytes", question: name, answer: `"Zoë"`},
+		{name: "Text too short", question: name, answer: `"A"`, expectError: true},
+		{name: "Text too long", question: name, answer: `"Alexander"`, expectError: true},
+		{name: "Text that is not a string", question: name, answer: `42`, expectError: true},
+		{name: "Pattern matches", question: code, answer: `"ABC"`},
+		{name: "Pattern must match the whole answer", question: code, answer: `"ABCD"`, expectError: true},
+		{name: "Paragraph too long", question: essay, answer: `"four"`, expectError: true},
+		{name: "Single choice", question: color, answer: `"Red"`},
+		{name: "Single choice not offered", question: color, answer: `"Blue"`, expectError: true},
+		{name: "Single choice as array", question: color, answer: `["Red"]`, expectError: true},
+		{name: "Multiple choice", question: toppings, answer: `["Ham","Olives"]`},
+		{name: "Multiple choice not offered", question: toppings, answer: `["Pineapple"]`, expectError: true},
+		{name: "Multiple choice selected twice", question: toppings, answer: `["Ham","Ham"]`, expectError: true},
+		{name: "Multiple choice too many", question: toppings, answer: `["Ham","Olives","Onions"]`, expectError: true},
+		{name: "Multiple choice too few", question: toppings, answer: `[]`, expectError: true},
+		{name: "Multiple choice as string", question: toppings, answer: `"Ham"`, expectError: true},
+		{name: "Number within bounds", question: age, answer: `42.5`},
+		{name: "Number below min", question: age, answer: `-1`, expectError: true},
+		{name: "Number above max", question: age, answer: `121`, expectError: true},
+		{name: "Number as string", question: age, answer: `"42"`, expectError: true},
+		{name: "Date within bounds", question: birthday, answer: `"1990-06-15"`},
+		{name: "Date before min", question: birthday, answer: `"1899-12-31"`, expectError: true},
+		{name: "Date after max", question: birthday, answer: `"2025-01-01"`, expectError: true},
+		{name: "Date in another format", question: birthday, answer: `"15/06/1990"`, expectError: true},
+		{name: "Date that does not exist", question: birthday, answer: `"1990-02-30"`, expectError: true},
+		{name: "Unknown question type", question: form.Question{Type: "slider"}, answer: `1`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.question.ValidateAnswer(json.RawMessage(tt.answer))
+			if tt.expectError {
+				assert.ErrorIs(t, err, form.ErrInvalidAnswer)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
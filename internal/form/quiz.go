@@ -0,0 +1,120 @@
+package form
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RevealPolicy decides when respondents of a quiz see the correct answers.
+// Collaborators of the form always see them.
+type RevealPolicy string
+
+const (
+	RevealNever           RevealPolicy = "never"
+	RevealAfterSubmission RevealPolicy = "after_submission"
+	RevealAfterClose      RevealPolicy = "after_close"
+)
+
+func (p RevealPolicy) IsValid() bool {
+	return p == RevealNever || p == RevealAfterSubmission || p == RevealAfterClose
+}
+
+// QuizSettings turns a form into a quiz whose answers are graded.
+type QuizSettings struct {
+	Enabled       bool
+	RevealAnswers RevealPolicy
+}
+
+// RevealsAnswers reports whether respondents of f may currently see the
+// correct answers.
+func RevealsAnswers(f Form) bool {
+	switch RevealPolicy(f.RevealAnswers) {
+	case RevealAfterSubmission:
+		return true
+	case RevealAfterClose:
+		return Status(f.Status) == StatusClosed || Status(f.Status) == StatusArchived
+	}
+	return false
+}
+
+// Grade scores an answer to the question against its answer key. Questions
+// without points always score 0. graded is false for questions with points
+// but without an answer key, those are left to be graded by hand.
+//
+// Text answers match the key ignoring case and surrounding spaces, multiple
+// choice answers must select exactly the choices of the key, in any order.
+// There is no partial credit.
+func (q Question) Grade(value json.RawMessage) (points float64, graded bool) {
+	if q.Points == 0 {
+		return 0, true
+	}
+	if len(q.AnswerKey) == 0 {
+		return 0, false
+	}
+	if matchesKey(QuestionType(q.Type), value, q.AnswerKey) {
+		return q.Points, true
+	}
+	return 0, true
+}
+
+func matchesKey(t QuestionType, value, key json.RawMessage) bool {
+	switch t {
+	case QuestionTypeShortText, QuestionTypeParagraph:
+		var answer, expected string
+		if json.Unmarshal(value, &answer) != nil || json.Unmarshal(key, &expected) != nil {
+			return false
+		}
+		return strings.EqualFold(strings.TrimSpace(answer), strings.TrimSpace(expected))
+
+	case QuestionTypeMultipleChoice:
+		var answer, expected []string
+		if json.Unmarshal(value, &answer) != nil || json.Unmarshal(key, &expected) != nil {
+			return false
+		}
+		slices.Sort(answer)
+		slices.Sort(expected)
+		return slices.Equal(answer, expected)
+
+	case QuestionTypeNumber:
+		var answer, expected float64
+		if json.Unmarshal(value, &answer) != nil || json.Unmarshal(key, &expected) != nil {
+			return false
+		}
+		return answer == expected
+
+	default:
+		var answer, expected string
+		if json.Unmarshal(value, &answer) != nil || json.Unmarshal(key, &expected) != nil {
+			return false
+		}
+		return answer == expected
+	}
+}
+
+// validateScoring checks the points of the question and that its answer key,
+// if any, is a valid answer to it.
+func (input QuestionInput) validateScoring(settings []byte) error {
+	if input.Points < 0 {
+		return fmt.Errorf("%w: points must not be negative", ErrInvalidQuestion)
+	}
+	if IsEmptyAnswer(input.AnswerKey) {
+		return nil
+	}
+
+	err := Question{Type: string(input.Type), Settings: settings}.ValidateAnswer(input.AnswerKey)
+	if errors.Is(err, ErrInvalidAnswer) {
+		return fmt.Errorf("%w: answer key: %s", ErrInvalidQuestion, strings.TrimPrefix(err.Error(), ErrInvalidAnswer.Error()+": "))
+	}
+	return err
+}
+
+// answerKeyParam stores empty answer keys as NULL.
+func answerKeyParam(key json.RawMessage) []byte {
+	if IsEmptyAnswer(key) {
+		return nil
+	}
+	return key
+}
@@ -0,0 +1,68 @@
+package form
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Snapshot is the state of a form stored with each revision.
+type Snapshot struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+func snapshotOf(f Form) Snapshot {
+	return Snapshot{
+		Title:       f.Title,
+		Description: f.Description.String,
+	}
+}
+
+// FieldChange is one field that differs between two revisions.
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from,omitempty"`
+	To    json.RawMessage `json:"to,omitempty"`
+}
+
+// Diff compares two revision snapshots field by field. Fields are compared
+// on their JSON encoding, so fields added to Snapshot later are picked up
+// without changes here, and a field missing from one side shows up with an
+// empty From or To.
+func Diff(from, to FormRevision) ([]FieldChange, error) {
+	var before, after map[string]json.RawMessage
+	err := json.Unmarshal(from.Snapshot, &before)
+	if err != nil {
+		return nil, fmt.Errorf("decode revision %d: %w", from.Number, err)
+	}
+	err = json.Unmarshal(to.Snapshot, &after)
+	if err != nil {
+		return nil, fmt.Errorf("decode revision %d: %w", to.Number, err)
+	}
+
+	fields := make([]string, 0, len(before)+len(after))
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := make([]FieldChange, 0)
+	for _, field := range fields {
+		if bytes.Equal(before[field], after[field]) {
+			continue
+		}
+		changes = append(changes, FieldChange{
+			Field: field,
+			From:  before[field],
+			To:    after[field],
+		})
+	}
+	return changes, nil
+}
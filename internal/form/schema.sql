@@ -3,5 +3,81 @@ CREATE TABLE IF NOT EXISTS forms (
     title TEXT NOT NULL,
     description TEXT,
     author_id TEXT references users(id),
-    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
-    );
\ No newline at end of file
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    status TEXT NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'published', 'closed', 'archived')),
+    deleted_at TIMESTAMPTZ,
+    version INT NOT NULL DEFAULT 1,
+    is_quiz BOOLEAN NOT NULL DEFAULT false,
+    reveal_answers TEXT NOT NULL DEFAULT 'never' CHECK (reveal_answers IN ('never', 'after_submission', 'after_close')),
+    template_visibility TEXT CHECK (template_visibility IN ('private', 'team', 'public'))
+    );
+
+CREATE TABLE IF NOT EXISTS form_sections
+(
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    form_id     UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    title       TEXT        NOT NULL,
+    description TEXT,
+    position    INT         NOT NULL,
+    rules       JSONB       NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS questions
+(
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    form_id     UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    type        TEXT        NOT NULL CHECK (type IN ('short_text', 'paragraph', 'single_choice', 'multiple_choice', 'number', 'date')),
+    title       TEXT        NOT NULL,
+    description TEXT,
+    required    BOOLEAN     NOT NULL DEFAULT false,
+    position    INT         NOT NULL,
+    settings    JSONB       NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    section_id  UUID REFERENCES form_sections (id) ON DELETE SET NULL,
+    rules       JSONB       NOT NULL DEFAULT '{}',
+    points      DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (points >= 0),
+    answer_key  JSONB
+);
+
+CREATE TABLE IF NOT EXISTS form_collaborators
+(
+    form_id    UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    email      TEXT        NOT NULL,
+    user_id    UUID REFERENCES users (id) ON DELETE CASCADE,
+    role       TEXT        NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    invited_by UUID REFERENCES users (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (form_id, email)
+);
+
+CREATE TABLE IF NOT EXISTS form_search_documents
+(
+    form_id    UUID PRIMARY KEY REFERENCES forms (id) ON DELETE CASCADE,
+    document   TSVECTOR    NOT NULL,
+    body       TEXT        NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS form_status_transitions
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    form_id     UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    from_status TEXT        NOT NULL,
+    to_status   TEXT        NOT NULL,
+    changed_by  UUID REFERENCES users (id) ON DELETE SET NULL,
+    changed_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS form_revisions
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    form_id    UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    number     INT         NOT NULL,
+    snapshot   JSONB       NOT NULL,
+    created_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (form_id, number)
+);
\ No newline at end of file
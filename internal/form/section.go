@@ -0,0 +1,179 @@
+package form
+
+import (
+	"awesomeProject/internal/form/logic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// SectionInput is the user supplied part of a section, shared by create and update.
+type SectionInput struct {
+	Title       string
+	Description string
+	Position    *int32
+	Rules       logic.SectionRules
+}
+
+// ParseRules decodes the rules column of a section.
+func (s FormSection) ParseRules() (logic.SectionRules, error) {
+	var rules logic.SectionRules
+	if len(s.Rules) == 0 {
+		return rules, nil
+	}
+	err := json.Unmarshal(s.Rules, &rules)
+	return rules, err
+}
+
+// ParseRules decodes the rules column of a question.
+func (q Question) ParseRules() (logic.QuestionRules, error) {
+	var rules logic.QuestionRules
+	if len(q.Rules) == 0 {
+		return rules, nil
+	}
+	err := json.Unmarshal(q.Rules, &rules)
+	return rules, err
+}
+
+// BuildLogic arranges sections and questions in the order respondents see
+// them. Questions without a section come first, in a section with the zero
+// ID and no rules.
+func BuildLogic(sections []FormSection, questions []Question) (logic.Form, error) {
+	sections = slices.Clone(sections)
+	slices.SortStableFunc(sections, func(a, b FormSection) int { return int(a.Position) - int(b.Position) })
+	questions = slices.Clone(questions)
+	slices.SortStableFunc(questions, func(a, b Question) int { return int(a.Position) - int(b.Position) })
+
+	result := logic.Form{Sections: make([]logic.Section, 0, len(sections)+1)}
+	result.Sections = append(result.Sections, logic.Section{ID: uuid.Nil})
+	index := make(map[uuid.UUID]int, len(sections))
+	for _, section := range sections {
+		rules, err := section.ParseRules()
+		if err != nil {
+			return logic.Form{}, fmt.Errorf("decode rules of section %s: %w", section.ID, err)
+		}
+		index[section.ID] = len(result.Sections)
+		result.Sections = append(result.Sections, logic.Section{ID: section.ID, Rules: rules})
+	}
+
+	for _, question := range questions {
+		rules, err := question.ParseRules()
+		if err != nil {
+			return logic.Form{}, fmt.Errorf("decode rules of question %s: %w", question.ID, err)
+		}
+		i := 0
+		if question.SectionID.Valid {
+			i = index[uuid.UUID(question.SectionID.Bytes)]
+		}
+		result.Sections[i].Questions = append(result.Sections[i].Questions, logic.Question{
+			ID:       question.ID,
+			Required: question.Required,
+			Rules:    rules,
+		})
+	}
+
+	return result, nil
+}
+
+// CheckAnswers evaluates the rules of the form for answers and validates
+// them. It returns the path through the form and the rejected answers keyed
+// by question ID: required questions without an answer, answers to hidden or
+// skipped questions, answers that do not fit their question and answers to
+// questions of other forms. Empty answers count as not answered.
+func CheckAnswers(sections []FormSection, questions []Question, answers map[uuid.UUID]json.RawMessage) (logic.Result, map[string]string, error) {
+	structure, err := BuildLogic(sections, questions)
+	if err != nil {
+		return logic.Result{}, nil, err
+	}
+
+	given := make(map[uuid.UUID]json.RawMessage, len(answers))
+	for questionID, value := range answers {
+		if !IsEmptyAnswer(value) {
+			given[questionID] = value
+		}
+	}
+
+	result := logic.Evaluate(structure, given)
+	fields := make(map[string]string)
+	for questionID, message := range result.Problems(structure, given) {
+		fields[questionID.String()] = message
+	}
+
+	known := make(map[uuid.UUID]bool, len(questions))
+	for _, question := range questions {
+		known[question.ID] = true
+
+		value, ok := given[question.ID]
+		if !ok || !result.Shown[question.ID] {
+			continue
+		}
+		err := question.ValidateAnswer(value)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidAnswer) {
+				return logic.Result{}, nil, err
+			}
+			fields[question.ID.String()] = err.Error()
+		}
+	}
+
+	for questionID := range answers {
+		if !known[questionID] {
+			fields[questionID.String()] = "question does not belong to this form"
+		}
+	}
+
+	return result, fields, nil
+}
+
+// upsertQuestion returns questions with question added, or replacing the
+// question with the same ID. A nil position appends a new question and
+// keeps the position of an existing one, like the queries do.
+func upsertQuestion(questions []Question, question Question, position *int32) []Question {
+	result := slices.Clone(questions)
+	for i, existing := range result {
+		if existing.ID == question.ID {
+			question.Position = existing.Position
+			if position != nil {
+				question.Position = *position
+			}
+			result[i] = question
+			return result
+		}
+	}
+
+	question.Position = 0
+	for _, existing := range result {
+		question.Position = max(question.Position, existing.Position+1)
+	}
+	if position != nil {
+		question.Position = *position
+	}
+	return append(result, question)
+}
+
+// upsertSection is upsertQuestion for sections.
+func upsertSection(sections []FormSection, section FormSection, position *int32) []FormSection {
+	result := slices.Clone(sections)
+	for i, existing := range result {
+		if existing.ID == section.ID {
+			section.Position = existing.Position
+			if position != nil {
+				section.Position = *position
+			}
+			result[i] = section
+			return result
+		}
+	}
+
+	section.Position = 0
+	for _, existing := range result {
+		section.Position = max(section.Position, existing.Position+1)
+	}
+	if position != nil {
+		section.Position = *position
+	}
+	return append(result, section)
+}
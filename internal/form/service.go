@@ -1,39 +1,159 @@
 package form
 
 import (
+	"awesomeProject/internal/form/logic"
+	"awesomeProject/internal/jwt"
+	"awesomeProject/internal/pagination"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrForbidden       = errors.New("forbidden")
+	ErrUnauthenticated = errors.New("no user in context")
+	ErrInvalidQuestion = errors.New("invalid question")
+	ErrInvalidAnswer   = errors.New("invalid answer")
+	ErrConflict        = errors.New("conflict")
+	ErrInvalidRole     = errors.New("invalid role")
+	ErrEmptyQuery      = errors.New("search query is empty")
+	ErrReadOnly        = errors.New("form is read-only")
+	ErrInvalidAction   = errors.New("invalid status transition")
+	ErrVersionMismatch = errors.New("form was modified since it was read")
+	ErrInvalidRule     = logic.ErrInvalidRule
+	ErrInvalidQuiz     = errors.New("invalid quiz settings")
+	ErrInvalidTemplate = errors.New("invalid template settings")
+	ErrCopyConflict    = errors.New("form changed while it was copied")
+)
+
+// Role is the access level a user has on a form. The author of a form is
+// always an owner, other users get their role from form_collaborators.
+type Role string
+
+const (
+	RoleNone   Role = ""
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleOwner  Role = "owner"
+)
+
+func (r Role) IsValid() bool {
+	return r == RoleViewer || r == RoleEditor || r == RoleOwner
+}
+
+// Includes reports whether r grants at least the permissions of required.
+func (r Role) Includes(required Role) bool {
+	return r.rank() >= required.rank()
+}
+
+func (r Role) rank() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleEditor:
+		return 2
+	case RoleOwner:
+		return 3
+	}
+	return 0
+}
+
+//go:generate mockery --name=Querier
 type Querier interface {
 	Create(ctx context.Context, arg CreateParams) (Form, error)
-	List(ctx context.Context) ([]Form, error)
+	Get(ctx context.Context, id uuid.UUID) (Form, error)
+	ListWithBookmarks(ctx context.Context, arg ListWithBookmarksParams) ([]ListWithBookmarksRow, error)
+	Search(ctx context.Context, arg SearchParams) ([]SearchRow, error)
+	Transition(ctx context.Context, arg TransitionParams) (FormStatusTransition, error)
+	ListTransitions(ctx context.Context, formID uuid.UUID) ([]FormStatusTransition, error)
 	Update(ctx context.Context, arg UpdateParams) (Form, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, arg DeleteParams) (int64, error)
+	GetDeleted(ctx context.Context, id uuid.UUID) (Form, error)
+	Restore(ctx context.Context, id uuid.UUID) (Form, error)
+	ListTrash(ctx context.Context, arg ListTrashParams) ([]Form, error)
+	PurgeDeleted(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error)
 	IsBookmarked(ctx context.Context, arg IsBookmarkedParams) (bool, error)
+	CreateQuestion(ctx context.Context, arg CreateQuestionParams) (Question, error)
+	ListQuestions(ctx context.Context, formID uuid.UUID) ([]Question, error)
+	UpdateQuestion(ctx context.Context, arg UpdateQuestionParams) (Question, error)
+	DeleteQuestion(ctx context.Context, arg DeleteQuestionParams) (int64, error)
+	GetCollaboratorRole(ctx context.Context, arg GetCollaboratorRoleParams) (string, error)
+	CreateCollaborator(ctx context.Context, arg CreateCollaboratorParams) (FormCollaborator, error)
+	ListCollaborators(ctx context.Context, formID uuid.UUID) ([]FormCollaborator, error)
+	UpdateCollaboratorRole(ctx context.Context, arg UpdateCollaboratorRoleParams) (FormCollaborator, error)
+	DeleteCollaborator(ctx context.Context, arg DeleteCollaboratorParams) (int64, error)
+	AttachInvitations(ctx context.Context, arg AttachInvitationsParams) (int64, error)
+	CreateRevision(ctx context.Context, arg CreateRevisionParams) (FormRevision, error)
+	ListRevisions(ctx context.Context, formID uuid.UUID) ([]FormRevision, error)
+	GetRevision(ctx context.Context, arg GetRevisionParams) (FormRevision, error)
+	CreateSection(ctx context.Context, arg CreateSectionParams) (FormSection, error)
+	ListSections(ctx context.Context, formID uuid.UUID) ([]FormSection, error)
+	UpdateSection(ctx context.Context, arg UpdateSectionParams) (FormSection, error)
+	DeleteSection(ctx context.Context, arg DeleteSectionParams) (int64, error)
+	SetQuiz(ctx context.Context, arg SetQuizParams) (Form, error)
+	SetTemplate(ctx context.Context, arg SetTemplateParams) (Form, error)
+	ListTemplates(ctx context.Context, arg ListTemplatesParams) ([]Form, error)
+	CreateCopy(ctx context.Context, arg CreateCopyParams) (Form, error)
+	CopySections(ctx context.Context, arg CopySectionsParams) (int64, error)
+	CopyQuestions(ctx context.Context, arg CopyQuestionsParams) (int64, error)
+	UpdateDefinition(ctx context.Context, arg UpdateDefinitionParams) (Form, error)
+	ImportSection(ctx context.Context, arg ImportSectionParams) (int64, error)
+	ImportQuestion(ctx context.Context, arg ImportQuestionParams) (int64, error)
+	DeleteQuestionsExcept(ctx context.Context, arg DeleteQuestionsExceptParams) (int64, error)
+	DeleteSectionsExcept(ctx context.Context, arg DeleteSectionsExceptParams) (int64, error)
 }
 
 type Service struct {
 	logger  *zap.Logger
 	queries Querier
+	tx      Transactor
 }
 
-func NewService(logger *zap.Logger, querier Querier) *Service {
+func NewService(logger *zap.Logger, querier Querier, tx Transactor) *Service {
 	return &Service{
 		logger:  logger,
 		queries: querier,
+		tx:      tx,
+	}
+}
+
+// withQueries returns a copy of the service that runs its queries on q,
+// usually the queries of a transaction.
+func (s *Service) withQueries(q Querier) *Service {
+	return &Service{
+		logger:  s.logger,
+		queries: q,
+		tx:      s.tx,
 	}
 }
 
 func (s *Service) Create(ctx context.Context, name, description string, authorID uuid.UUID) (Form, error) {
+	var result Form
+	err := s.tx.InTx(ctx, func(q Querier) error {
+		var err error
+		result, err = q.Create(ctx, CreateParams{
+			Title:       name,
+			Description: pgtype.Text{String: description, Valid: true},
+			AuthorID:    pgtype.Text{String: authorID.String(), Valid: true}, // Renamed from AuthorEmail
+		})
+		if err != nil {
+			return err
+		}
 
-	var result, err = s.queries.Create(ctx, CreateParams{
-		Title:       name,
-		Description: pgtype.Text{String: description, Valid: true},
-		AuthorID:    pgtype.Text{String: authorID.String(), Valid: true}, // Renamed from AuthorEmail
+		_, err = s.withQueries(q).recordRevision(ctx, result, authorID)
+		return err
 	})
 	if err != nil {
 		s.logger.Error("Failed to create form", zap.Error(err))
@@ -45,24 +165,352 @@ func (s *Service) Create(ctx context.Context, name, description string, authorID
 	return result, nil
 }
 
-func (s *Service) List(ctx context.Context) ([]Form, error) {
-	result, err := s.queries.List(ctx)
+// CreateFromTemplate creates a draft owned by authorID as a deep copy of a
+// template: its sections, questions, rules and quiz settings. Answer keys are
+// only copied for editors of the template, since they are hidden from everyone
+// else. Responses, collaborators and the history of the template are not
+// copied.
+func (s *Service) CreateFromTemplate(ctx context.Context, templateID, authorID uuid.UUID, opts CopyOptions) (Form, error) {
+	template, role, err := s.template(ctx, templateID, authorID)
 	if err != nil {
-		s.logger.Error("Failed to list form", zap.Error(err))
-		return []Form{}, err
+		return Form{}, err
+	}
+
+	result, err := s.copyForm(ctx, template, authorID, opts, role.Includes(RoleEditor))
+	if err != nil {
+		return Form{}, err
+	}
+
+	s.logger.Info("Created form from template", zap.String("form_id", result.ID.String()), zap.String("template_id", templateID.String()), zap.String("author_id", authorID.String()))
+
+	return result, nil
+}
+
+// Duplicate copies the form into a new draft owned by userID, see copyForm.
+// Editors of the form may duplicate it, since the copy includes the answer
+// keys. Without a new title the copy is called "Copy of" the original.
+func (s *Service) Duplicate(ctx context.Context, formID, userID uuid.UUID, opts CopyOptions) (Form, error) {
+	source, err := s.authorizeContent(ctx, formID, RoleEditor)
+	if err != nil {
+		return Form{}, err
+	}
+	if opts.Title == "" {
+		opts.Title = "Copy of " + source.Title
+	}
+
+	result, err := s.copyForm(ctx, source, userID, opts, true)
+	if err != nil {
+		return Form{}, err
+	}
+
+	s.logger.Info("Duplicated form", zap.String("form_id", result.ID.String()), zap.String("source_id", formID.String()), zap.String("author_id", userID.String()))
+
+	return result, nil
+}
+
+// copyForm copies source and everything that makes up its structure into a
+// new draft owned by authorID, with the answer keys if answerKeys is set.
+// The copy is made in one transaction, so it either succeeds as a whole or
+// leaves nothing behind.
+func (s *Service) copyForm(ctx context.Context, source Form, authorID uuid.UUID, opts CopyOptions, answerKeys bool) (Form, error) {
+	if opts.Title == "" {
+		opts.Title = source.Title
+	}
+	if opts.Description == "" {
+		opts.Description = source.Description.String
+	}
+
+	var result Form
+	err := s.tx.InTx(ctx, func(q Querier) error {
+		sections, err := q.ListSections(ctx, source.ID)
+		if err != nil {
+			return err
+		}
+		questions, err := q.ListQuestions(ctx, source.ID)
+		if err != nil {
+			return err
+		}
+
+		result, err = q.CreateCopy(ctx, CreateCopyParams{
+			Title:         opts.Title,
+			Description:   pgtype.Text{String: opts.Description, Valid: true},
+			AuthorID:      pgtype.Text{String: authorID.String(), Valid: true},
+			IsQuiz:        source.IsQuiz,
+			RevealAnswers: source.RevealAnswers,
+		})
+		if err != nil {
+			return err
+		}
+
+		ids := newIDMap(sections, questions)
+		if len(sections) > 0 {
+			copied, err := q.CopySections(ctx, ids.copySectionsParams(result.ID, source.ID, sections))
+			if err != nil {
+				return err
+			}
+			if copied != int64(len(sections)) {
+				return fmt.Errorf("%w: sections were added or removed", ErrCopyConflict)
+			}
+		}
+		if len(questions) > 0 {
+			copied, err := q.CopyQuestions(ctx, ids.copyQuestionsParams(result.ID, source.ID, sections, questions, answerKeys))
+			if err != nil {
+				return err
+			}
+			if copied != int64(len(questions)) {
+				return fmt.Errorf("%w: questions were added or removed", ErrCopyConflict)
+			}
+		}
+
+		_, err = s.withQueries(q).recordRevision(ctx, result, authorID)
+		return err
+	})
+	if err != nil {
+		if !errors.Is(err, ErrCopyConflict) {
+			s.logger.Error("Failed to copy form", zap.String("source_id", source.ID.String()), zap.Error(err))
+		}
+		return Form{}, err
+	}
+
+	return result, nil
+}
+
+// SetTemplate makes the form a template with the given visibility, or no
+// longer a template with TemplateNone. Only owners may change it. version
+// works like in Update.
+func (s *Service) SetTemplate(ctx context.Context, id uuid.UUID, visibility TemplateVisibility, version *int32) (Form, error) {
+	if !visibility.IsValid() {
+		return Form{}, fmt.Errorf("%w: unknown template visibility %q", ErrInvalidTemplate, visibility)
+	}
+
+	current, err := s.Authorize(ctx, id, RoleOwner)
+	if err != nil {
+		return Form{}, err
+	}
+	if version != nil && current.Version != *version {
+		return Form{}, ErrVersionMismatch
+	}
+
+	result, err := s.queries.SetTemplate(ctx, SetTemplateParams{
+		TemplateVisibility: visibility.param(),
+		ID:                 id,
+		ExpectedVersion:    versionParam(version),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if version != nil {
+				return Form{}, ErrVersionMismatch
+			}
+			return Form{}, ErrNotFound
+		}
+		s.logger.Error("Failed to change template visibility", zap.Error(err))
+		return Form{}, err
 	}
 
+	s.logger.Info("Changed template visibility", zap.String("form_id", id.String()), zap.String("visibility", string(visibility)))
+
 	return result, nil
 }
 
-func (s *Service) Update(ctx context.Context, id uuid.UUID, name, description string) (Form, error) {
+// ListTemplates returns one page of the templates available to the user,
+// newest first, and the cursor of the next page. Archived templates and
+// drafts of other owners are left out.
+func (s *Service) ListTemplates(ctx context.Context, userID uuid.UUID, page pagination.Params) ([]Form, string, error) {
+	params := ListTemplatesParams{
+		UserID:    userID,
+		PageLimit: page.FetchLimit(),
+	}
+
+	const sort = "created_at:desc"
+	cursor, err := page.CursorFor(sort)
+	if err != nil {
+		return nil, "", err
+	}
+	if cursor != nil {
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Key)
+		if err != nil {
+			return nil, "", pagination.ErrInvalidCursor
+		}
+		params.CursorID = pgtype.UUID{Bytes: cursor.ID, Valid: true}
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: createdAt, Valid: true}
+	}
+
+	result, err := s.queries.ListTemplates(ctx, params)
+	if err != nil {
+		s.logger.Error("Failed to list templates", zap.Error(err))
+		return nil, "", err
+	}
+
+	forms, next := pagination.Trim(result, page, func(f Form) pagination.Cursor {
+		return pagination.Cursor{
+			Sort: sort,
+			Key:  f.CreatedAt.Time.Format(time.RFC3339Nano),
+			ID:   f.ID,
+		}
+	})
+	return forms, next, nil
+}
+
+// template loads a template userID may create forms from, with the role of
+// the user on it. Forms that are not templates or not available to the user
+// look like missing forms, and so do archived templates and drafts of other
+// owners, like in ListTemplates.
+func (s *Service) template(ctx context.Context, id, userID uuid.UUID) (Form, Role, error) {
+	result, err := s.Get(ctx, id)
+	if err != nil {
+		return Form{}, RoleNone, err
+	}
+
+	visibility := TemplateVisibility(result.TemplateVisibility.String)
+	status := Status(result.Status)
+	if visibility == TemplateNone || status == StatusArchived {
+		return Form{}, RoleNone, ErrNotFound
+	}
+
+	role, err := s.roleOf(ctx, result, userID)
+	if err != nil {
+		return Form{}, RoleNone, err
+	}
+	if !role.Includes(visibility.requiredRole()) {
+		return Form{}, RoleNone, ErrNotFound
+	}
+	if status == StatusDraft && !role.Includes(RoleOwner) {
+		return Form{}, RoleNone, ErrNotFound
+	}
+
+	return result, role, nil
+}
+
+// List returns one page of forms with the bookmark state of userID and the
+// cursor of the next page, which is empty on the last page. The bookmark
+// state is joined in the same query, so a page costs one round trip.
+func (s *Service) List(ctx context.Context, userID uuid.UUID, opts ListOptions) ([]ListWithBookmarksRow, string, error) {
+	if opts.SortBy == "" {
+		opts.SortBy = SortByCreatedAt
+	}
+
+	params := ListWithBookmarksParams{
+		UserID:     userID,
+		SortBy:     string(opts.SortBy),
+		Descending: opts.Descending,
+		PageLimit:  opts.Page.FetchLimit(),
+	}
+	if opts.AuthorOnly {
+		params.AuthorID = pgtype.Text{String: userID.String(), Valid: true}
+	}
+	if opts.Bookmarked {
+		params.BookmarkedBy = pgtype.UUID{Bytes: userID, Valid: true}
+	}
+	if !opts.CreatedAfter.IsZero() {
+		params.CreatedAfter = pgtype.Timestamptz{Time: opts.CreatedAfter, Valid: true}
+	}
+	if !opts.CreatedBefore.IsZero() {
+		params.CreatedBefore = pgtype.Timestamptz{Time: opts.CreatedBefore, Valid: true}
+	}
+
+	err := opts.applyCursor(&params)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result, err := s.queries.ListWithBookmarks(ctx, params)
+	if err != nil {
+		s.logger.Error("Failed to list form", zap.Error(err))
+		return []ListWithBookmarksRow{}, "", err
+	}
+
+	forms, next := pagination.Trim(result, opts.Page, opts.cursorOf)
+	return forms, next, nil
+}
+
+// Search returns one page of forms matching the web search style query, most
+// relevant first, and the cursor of the next page. Title matches rank above
+// description matches, which rank above question text.
+func (s *Service) Search(ctx context.Context, userID uuid.UUID, query string, page pagination.Params) ([]SearchRow, string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, "", ErrEmptyQuery
+	}
+
+	params := SearchParams{
+		Query:     query,
+		UserID:    userID,
+		PageLimit: page.FetchLimit(),
+	}
+
+	// a cursor only makes sense for the query it was created for
+	sort := "rank:" + query
+	cursor, err := page.CursorFor(sort)
+	if err != nil {
+		return nil, "", err
+	}
+	if cursor != nil {
+		rank, err := strconv.ParseFloat(cursor.Key, 32)
+		if err != nil {
+			return nil, "", pagination.ErrInvalidCursor
+		}
+		params.CursorID = pgtype.UUID{Bytes: cursor.ID, Valid: true}
+		params.CursorRank = float32(rank)
+	}
+
+	result, err := s.queries.Search(ctx, params)
+	if err != nil {
+		s.logger.Error("Failed to search forms", zap.Error(err))
+		return nil, "", err
+	}
+
+	forms, next := pagination.Trim(result, page, func(row SearchRow) pagination.Cursor {
+		return pagination.Cursor{
+			Sort: sort,
+			Key:  strconv.FormatFloat(float64(row.Rank), 'g', -1, 32),
+			ID:   row.ID,
+		}
+	})
+	return forms, next, nil
+}
+
+// Update changes the title and description of the form and records the
+// result as a new revision. If version is not nil, the form is only changed
+// while it is still at that version, otherwise ErrVersionMismatch is returned.
+func (s *Service) Update(ctx context.Context, id uuid.UUID, name, description string, version *int32) (Form, error) {
+	current, err := s.authorizeEdit(ctx, id)
+	if err != nil {
+		return Form{}, err
+	}
+	if version != nil && current.Version != *version {
+		return Form{}, ErrVersionMismatch
+	}
+
+	return s.update(ctx, id, name, description, version)
+}
+
+// update changes the form and records the new revision in one transaction,
+// so the history never misses a change or lists one that did not happen.
+func (s *Service) update(ctx context.Context, id uuid.UUID, name, description string, version *int32) (Form, error) {
+	var result Form
+	err := s.tx.InTx(ctx, func(q Querier) error {
+		var err error
+		result, err = q.Update(ctx, UpdateParams{
+			ID:              id,
+			Title:           name,
+			Description:     pgtype.Text{String: description, Valid: true},
+			ExpectedVersion: versionParam(version),
+		})
+		if err != nil {
+			return err
+		}
 
-	result, err := s.queries.Update(ctx, UpdateParams{
-		ID:          id,
-		Title:       name,
-		Description: pgtype.Text{String: description, Valid: true},
+		_, err = s.withQueries(q).recordRevision(ctx, result, ctx.Value(jwt.UserContextKey).(uuid.UUID))
+		return err
 	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// the form changed or went to the trash since it was authorized
+			if version != nil {
+				return Form{}, ErrVersionMismatch
+			}
+			return Form{}, ErrNotFound
+		}
 		s.logger.Error("Failed to update form", zap.Error(err))
 		return Form{}, err
 	}
@@ -70,16 +518,284 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, name, description st
 	return result, nil
 }
 
-func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+// SetQuiz turns quiz mode of the form on or off and sets when respondents
+// see the correct answers. version works like in Update.
+func (s *Service) SetQuiz(ctx context.Context, id uuid.UUID, settings QuizSettings, version *int32) (Form, error) {
+	if !settings.RevealAnswers.IsValid() {
+		return Form{}, fmt.Errorf("%w: unknown reveal policy %q", ErrInvalidQuiz, settings.RevealAnswers)
+	}
+
+	current, err := s.authorizeEdit(ctx, id)
+	if err != nil {
+		return Form{}, err
+	}
+	if version != nil && current.Version != *version {
+		return Form{}, ErrVersionMismatch
+	}
+
+	result, err := s.queries.SetQuiz(ctx, SetQuizParams{
+		IsQuiz:          settings.Enabled,
+		RevealAnswers:   string(settings.RevealAnswers),
+		ID:              id,
+		ExpectedVersion: versionParam(version),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if version != nil {
+				return Form{}, ErrVersionMismatch
+			}
+			return Form{}, ErrNotFound
+		}
+		s.logger.Error("Failed to change quiz settings", zap.Error(err))
+		return Form{}, err
+	}
+
+	s.logger.Info("Changed quiz settings", zap.String("form_id", id.String()), zap.Bool("is_quiz", result.IsQuiz), zap.String("reveal_answers", result.RevealAnswers))
+
+	return result, nil
+}
+
+// recordRevision stores a snapshot of f as its next revision. It must run in
+// the transaction that created or changed f: the row lock on the form
+// serializes concurrent revisions, so they cannot race for the next number.
+func (s *Service) recordRevision(ctx context.Context, f Form, userID uuid.UUID) (FormRevision, error) {
+	snapshot, err := json.Marshal(snapshotOf(f))
+	if err != nil {
+		return FormRevision{}, err
+	}
+
+	revision, err := s.queries.CreateRevision(ctx, CreateRevisionParams{
+		FormID:    f.ID,
+		Snapshot:  snapshot,
+		CreatedBy: userID,
+	})
+	if err != nil {
+		s.logger.Error("Failed to record form revision", zap.String("form_id", f.ID.String()), zap.Error(err))
+		return FormRevision{}, err
+	}
+	return revision, nil
+}
+
+// ListRevisions returns the revisions of the form, newest first. Like the
+// other parts of the edit history, they are only shown to collaborators.
+func (s *Service) ListRevisions(ctx context.Context, formID uuid.UUID) ([]FormRevision, error) {
+	_, err := s.authorizeContent(ctx, formID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.queries.ListRevisions(ctx, formID)
+	if err != nil {
+		s.logger.Error("Failed to list form revisions", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DiffRevisions returns the fields that changed from one revision of the
+// form to another. from may be newer than to, the diff is then reversed.
+func (s *Service) DiffRevisions(ctx context.Context, formID uuid.UUID, from, to int32) ([]FieldChange, error) {
+	_, err := s.authorizeContent(ctx, formID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := s.getRevision(ctx, formID, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.getRevision(ctx, formID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return Diff(before, after)
+}
+
+// Rollback restores the form to the state of the given revision. The
+// rollback is itself recorded as a new revision, so it can be undone.
+func (s *Service) Rollback(ctx context.Context, formID uuid.UUID, number int32) (Form, error) {
+	_, err := s.authorizeEdit(ctx, formID)
+	if err != nil {
+		return Form{}, err
+	}
+
+	revision, err := s.getRevision(ctx, formID, number)
+	if err != nil {
+		return Form{}, err
+	}
+
+	var snapshot Snapshot
+	err = json.Unmarshal(revision.Snapshot, &snapshot)
+	if err != nil {
+		s.logger.Error("Failed to decode form revision", zap.String("revision_id", revision.ID.String()), zap.Error(err))
+		return Form{}, err
+	}
+
+	result, err := s.update(ctx, formID, snapshot.Title, snapshot.Description, nil)
+	if err != nil {
+		return Form{}, err
+	}
+
+	s.logger.Info("Rolled back form", zap.String("form_id", formID.String()), zap.Int32("revision", number))
+
+	return result, nil
+}
+
+func (s *Service) getRevision(ctx context.Context, formID uuid.UUID, number int32) (FormRevision, error) {
+	revision, err := s.queries.GetRevision(ctx, GetRevisionParams{
+		FormID: formID,
+		Number: number,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return FormRevision{}, ErrNotFound
+		}
+		s.logger.Error("Failed to get form revision", zap.Error(err))
+		return FormRevision{}, err
+	}
+
+	return revision, nil
+}
+
+// Delete moves the form to the trash. It can be restored until PurgeDeleted
+// removes it for good. If version is not nil, the form is only deleted while
+// it is still at that version.
+func (s *Service) Delete(ctx context.Context, id uuid.UUID, version *int32) error {
+	current, err := s.Authorize(ctx, id, RoleOwner)
+	if err != nil {
+		return err
+	}
+	if version != nil && current.Version != *version {
+		return ErrVersionMismatch
+	}
 
-	err := s.queries.Delete(ctx, id)
+	affected, err := s.queries.Delete(ctx, DeleteParams{
+		ID:              id,
+		ExpectedVersion: versionParam(version),
+	})
 	if err != nil {
 		s.logger.Error("Failed to delete form", zap.Error(err))
 		return err
 	}
+	if affected == 0 {
+		if version != nil {
+			return ErrVersionMismatch
+		}
+		return ErrNotFound
+	}
+
+	s.logger.Info("Moved form to trash", zap.String("form_id", id.String()))
+
 	return nil
 }
 
+// Restore takes a form out of the trash. Only owners may restore a form.
+func (s *Service) Restore(ctx context.Context, id uuid.UUID) (Form, error) {
+	deleted, err := s.queries.GetDeleted(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Form{}, ErrNotFound
+		}
+		s.logger.Error("Failed to get deleted form", zap.String("form_id", id.String()), zap.Error(err))
+		return Form{}, err
+	}
+
+	err = s.authorize(ctx, deleted, RoleOwner)
+	if err != nil {
+		return Form{}, err
+	}
+
+	result, err := s.queries.Restore(ctx, id)
+	if err != nil {
+		// restored or purged since it was read
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Form{}, ErrNotFound
+		}
+		s.logger.Error("Failed to restore form", zap.Error(err))
+		return Form{}, err
+	}
+
+	s.logger.Info("Restored form", zap.String("form_id", id.String()))
+
+	return result, nil
+}
+
+// ListTrash returns one page of the deleted forms the user owns, most
+// recently deleted first, and the cursor of the next page.
+func (s *Service) ListTrash(ctx context.Context, userID uuid.UUID, page pagination.Params) ([]Form, string, error) {
+	params := ListTrashParams{
+		UserID:    userID,
+		PageLimit: page.FetchLimit(),
+	}
+
+	const sort = "deleted_at:desc"
+	cursor, err := page.CursorFor(sort)
+	if err != nil {
+		return nil, "", err
+	}
+	if cursor != nil {
+		deletedAt, err := time.Parse(time.RFC3339Nano, cursor.Key)
+		if err != nil {
+			return nil, "", pagination.ErrInvalidCursor
+		}
+		params.CursorID = pgtype.UUID{Bytes: cursor.ID, Valid: true}
+		params.CursorDeletedAt = pgtype.Timestamptz{Time: deletedAt, Valid: true}
+	}
+
+	result, err := s.queries.ListTrash(ctx, params)
+	if err != nil {
+		s.logger.Error("Failed to list trash", zap.Error(err))
+		return nil, "", err
+	}
+
+	forms, next := pagination.Trim(result, page, func(f Form) pagination.Cursor {
+		return pagination.Cursor{
+			Sort: sort,
+			Key:  f.DeletedAt.Time.Format(time.RFC3339Nano),
+			ID:   f.ID,
+		}
+	})
+	return forms, next, nil
+}
+
+// PurgeDeleted permanently removes forms that have been in the trash for
+// longer than retention. Everything attached to them is removed by the
+// ON DELETE CASCADE foreign keys.
+func (s *Service) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	purged, err := s.queries.PurgeDeleted(ctx, pgtype.Timestamptz{
+		Time:  time.Now().Add(-retention),
+		Valid: true,
+	})
+	if err != nil {
+		s.logger.Error("Failed to purge deleted forms", zap.Error(err))
+		return 0, err
+	}
+
+	if purged > 0 {
+		s.logger.Info("Purged deleted forms", zap.Int64("count", purged))
+	}
+
+	return purged, nil
+}
+
+// RunPurge calls PurgeDeleted every interval until ctx is done.
+func (s *Service) RunPurge(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = s.PurgeDeleted(ctx, retention)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func (s *Service) IsBookmarked(ctx context.Context, form_id, user_id uuid.UUID) (bool, error) {
 	exists, err := s.queries.IsBookmarked(ctx, IsBookmarkedParams{
 		FormID: form_id,
@@ -92,3 +808,747 @@ func (s *Service) IsBookmarked(ctx context.Context, form_id, user_id uuid.UUID)
 
 	return exists, err
 }
+
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (Form, error) {
+	result, err := s.queries.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Form{}, ErrNotFound
+		}
+		s.logger.Error("Failed to get form", zap.String("form_id", id.String()), zap.Error(err))
+		return Form{}, err
+	}
+
+	return result, nil
+}
+
+// Authorize loads the form and checks that the user stored in the context by
+// jwt.Middleware has at least the required role on it.
+func (s *Service) Authorize(ctx context.Context, formID uuid.UUID, required Role) (Form, error) {
+	userID, ok := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	if !ok {
+		s.logger.Error("Failed to get user ID from context")
+		return Form{}, ErrUnauthenticated
+	}
+
+	result, err := s.Get(ctx, formID)
+	if err != nil {
+		return Form{}, err
+	}
+
+	err = s.checkRole(ctx, result, userID, required)
+	if err != nil {
+		return Form{}, err
+	}
+
+	return result, nil
+}
+
+// authorize is Authorize for a form that is already loaded.
+func (s *Service) authorize(ctx context.Context, f Form, required Role) error {
+	userID, ok := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	if !ok {
+		s.logger.Error("Failed to get user ID from context")
+		return ErrUnauthenticated
+	}
+
+	return s.checkRole(ctx, f, userID, required)
+}
+
+func (s *Service) checkRole(ctx context.Context, f Form, userID uuid.UUID, required Role) error {
+	role, err := s.roleOf(ctx, f, userID)
+	if err != nil {
+		return err
+	}
+
+	if !role.Includes(required) {
+		s.logger.Warn("User does not have the required role on form", zap.String("form_id", f.ID.String()), zap.String("user_id", userID.String()), zap.String("role", string(role)), zap.String("required", string(required)))
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// authorizeContent is Authorize for the questions and sections of the form.
+// Drafts are only visible to owners, so they look like missing forms to
+// everyone else, whatever their role, see Visible.
+func (s *Service) authorizeContent(ctx context.Context, formID uuid.UUID, required Role) (Form, error) {
+	result, err := s.Authorize(ctx, formID, required)
+	if err != nil {
+		return Form{}, err
+	}
+
+	err = s.hideDraft(ctx, result)
+	if err != nil {
+		return Form{}, err
+	}
+
+	return result, nil
+}
+
+// authorizeEdit is Authorize for changes to the form and its questions, which
+// also need the form to still be editable. Drafts may only be changed by
+// their owners.
+func (s *Service) authorizeEdit(ctx context.Context, formID uuid.UUID) (Form, error) {
+	result, err := s.authorizeContent(ctx, formID, RoleEditor)
+	if err != nil {
+		return Form{}, err
+	}
+
+	if !Status(result.Status).IsEditable() {
+		return Form{}, ErrReadOnly
+	}
+
+	return result, nil
+}
+
+// Visible loads a form the caller may see. Drafts are only visible to owners
+// and look like missing forms to everyone else.
+func (s *Service) Visible(ctx context.Context, formID uuid.UUID) (Form, error) {
+	result, err := s.Get(ctx, formID)
+	if err != nil {
+		return Form{}, err
+	}
+
+	err = s.hideDraft(ctx, result)
+	if err != nil {
+		return Form{}, err
+	}
+
+	return result, nil
+}
+
+// hideDraft returns ErrNotFound if the form is a draft and the caller is not
+// one of its owners.
+func (s *Service) hideDraft(ctx context.Context, f Form) error {
+	if Status(f.Status) != StatusDraft {
+		return nil
+	}
+
+	userID, ok := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	if !ok {
+		return ErrNotFound
+	}
+	role, err := s.roleOf(ctx, f, userID)
+	if err != nil {
+		return err
+	}
+	if !role.Includes(RoleOwner) {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// OpenForResponses loads a form that accepts submissions, which only
+// published forms do.
+func (s *Service) OpenForResponses(ctx context.Context, formID uuid.UUID) (Form, error) {
+	result, err := s.Visible(ctx, formID)
+	if err != nil {
+		return Form{}, err
+	}
+
+	if Status(result.Status) != StatusPublished {
+		return Form{}, ErrReadOnly
+	}
+
+	return result, nil
+}
+
+// Transition applies a lifecycle action to the form and records who did it.
+func (s *Service) Transition(ctx context.Context, formID uuid.UUID, action Action) (Form, error) {
+	rule, ok := transitions[action]
+	if !ok {
+		return Form{}, fmt.Errorf("%w: unknown action %q", ErrInvalidAction, action)
+	}
+
+	result, err := s.Authorize(ctx, formID, rule.required)
+	if err != nil {
+		return Form{}, err
+	}
+
+	from := Status(result.Status)
+	if !rule.allows(from) {
+		return Form{}, fmt.Errorf("%w: cannot %s a %s form", ErrInvalidAction, action, from)
+	}
+
+	_, err = s.queries.Transition(ctx, TransitionParams{
+		ToStatus:   string(rule.to),
+		FormID:     formID,
+		FromStatus: string(from),
+		ChangedBy:  ctx.Value(jwt.UserContextKey).(uuid.UUID),
+	})
+	if err != nil {
+		// the status changed since it was read
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Form{}, fmt.Errorf("%w: form is no longer %s", ErrInvalidAction, from)
+		}
+		s.logger.Error("Failed to change form status", zap.Error(err))
+		return Form{}, err
+	}
+
+	s.logger.Info("Changed form status", zap.String("form_id", formID.String()), zap.String("from", string(from)), zap.String("to", string(rule.to)))
+
+	result.Status = string(rule.to)
+	result.Version++
+	return result, nil
+}
+
+func (s *Service) ListTransitions(ctx context.Context, formID uuid.UUID) ([]FormStatusTransition, error) {
+	_, err := s.authorizeContent(ctx, formID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.queries.ListTransitions(ctx, formID)
+	if err != nil {
+		s.logger.Error("Failed to list form status transitions", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *Service) roleOf(ctx context.Context, f Form, userID uuid.UUID) (Role, error) {
+	if f.AuthorID.String == userID.String() {
+		return RoleOwner, nil
+	}
+
+	role, err := s.queries.GetCollaboratorRole(ctx, GetCollaboratorRoleParams{
+		FormID: f.ID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return RoleNone, nil
+		}
+		s.logger.Error("Failed to get collaborator role", zap.Error(err))
+		return RoleNone, err
+	}
+
+	return Role(role), nil
+}
+
+func (s *Service) CreateQuestion(ctx context.Context, formID uuid.UUID, input QuestionInput) (Question, error) {
+	err := input.Settings.Validate(input.Type)
+	if err != nil {
+		return Question{}, err
+	}
+
+	_, err = s.authorizeEdit(ctx, formID)
+	if err != nil {
+		return Question{}, err
+	}
+
+	settings, rules, err := s.encodeQuestion(input)
+	if err != nil {
+		return Question{}, err
+	}
+	err = input.validateScoring(settings)
+	if err != nil {
+		return Question{}, err
+	}
+
+	proposed := Question{
+		ID:        uuid.New(),
+		Required:  input.Required,
+		SectionID: sectionParam(input.SectionID),
+		Rules:     rules,
+	}
+	err = s.checkRules(ctx, formID, func(sections []FormSection, questions []Question) ([]FormSection, []Question) {
+		return sections, upsertQuestion(questions, proposed, input.Position)
+	})
+	if err != nil {
+		return Question{}, err
+	}
+
+	result, err := s.queries.CreateQuestion(ctx, CreateQuestionParams{
+		FormID:      formID,
+		Type:        string(input.Type),
+		Title:       input.Title,
+		Description: pgtype.Text{String: input.Description, Valid: input.Description != ""},
+		Required:    input.Required,
+		Position:    positionParam(input.Position),
+		Settings:    settings,
+		SectionID:   sectionParam(input.SectionID),
+		Rules:       rules,
+		Points:      input.Points,
+		AnswerKey:   answerKeyParam(input.AnswerKey),
+	})
+	if err != nil {
+		s.logger.Error("Failed to create question", zap.Error(err))
+		return Question{}, err
+	}
+
+	s.logger.Info("Created question", zap.String("form_id", formID.String()), zap.String("question_id", result.ID.String()))
+
+	return result, nil
+}
+
+// ListQuestions returns the questions of the form. Answer keys are only
+// included for editors, everyone else must not learn the correct answers
+// from the questions.
+func (s *Service) ListQuestions(ctx context.Context, formID uuid.UUID) ([]Question, error) {
+	f, err := s.Visible(ctx, formID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.queries.ListQuestions(ctx, formID)
+	if err != nil {
+		s.logger.Error("Failed to list questions", zap.Error(err))
+		return nil, err
+	}
+
+	if slices.ContainsFunc(result, func(q Question) bool { return len(q.AnswerKey) > 0 }) {
+		err = s.authorize(ctx, f, RoleEditor)
+		if errors.Is(err, ErrForbidden) || errors.Is(err, ErrUnauthenticated) {
+			for i := range result {
+				result[i].AnswerKey = nil
+			}
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// GradingQuestions returns the questions of the form including their answer
+// keys, for grading. It does not check access, callers must do that.
+func (s *Service) GradingQuestions(ctx context.Context, formID uuid.UUID) ([]Question, error) {
+	result, err := s.queries.ListQuestions(ctx, formID)
+	if err != nil {
+		s.logger.Error("Failed to list questions", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *Service) UpdateQuestion(ctx context.Context, formID, questionID uuid.UUID, input QuestionInput) (Question, error) {
+	err := input.Settings.Validate(input.Type)
+	if err != nil {
+		return Question{}, err
+	}
+
+	_, err = s.authorizeEdit(ctx, formID)
+	if err != nil {
+		return Question{}, err
+	}
+
+	settings, rules, err := s.encodeQuestion(input)
+	if err != nil {
+		return Question{}, err
+	}
+	err = input.validateScoring(settings)
+	if err != nil {
+		return Question{}, err
+	}
+
+	proposed := Question{
+		ID:        questionID,
+		Required:  input.Required,
+		SectionID: sectionParam(input.SectionID),
+		Rules:     rules,
+	}
+	err = s.checkRules(ctx, formID, func(sections []FormSection, questions []Question) ([]FormSection, []Question) {
+		return sections, upsertQuestion(questions, proposed, input.Position)
+	})
+	if err != nil {
+		return Question{}, err
+	}
+
+	result, err := s.queries.UpdateQuestion(ctx, UpdateQuestionParams{
+		ID:          questionID,
+		FormID:      formID,
+		Type:        string(input.Type),
+		Title:       input.Title,
+		Description: pgtype.Text{String: input.Description, Valid: input.Description != ""},
+		Required:    input.Required,
+		Position:    positionParam(input.Position),
+		Settings:    settings,
+		SectionID:   sectionParam(input.SectionID),
+		Rules:       rules,
+		Points:      input.Points,
+		AnswerKey:   answerKeyParam(input.AnswerKey),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Question{}, ErrNotFound
+		}
+		s.logger.Error("Failed to update question", zap.Error(err))
+		return Question{}, err
+	}
+
+	return result, nil
+}
+
+// DeleteQuestion removes a question. Questions that other rules refer to
+// cannot be removed until those rules are changed.
+func (s *Service) DeleteQuestion(ctx context.Context, formID, questionID uuid.UUID) error {
+	_, err := s.authorizeEdit(ctx, formID)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkRules(ctx, formID, func(sections []FormSection, questions []Question) ([]FormSection, []Question) {
+		return sections, slices.DeleteFunc(slices.Clone(questions), func(q Question) bool { return q.ID == questionID })
+	})
+	if err != nil {
+		return err
+	}
+
+	affected, err := s.queries.DeleteQuestion(ctx, DeleteQuestionParams{
+		ID:     questionID,
+		FormID: formID,
+	})
+	if err != nil {
+		s.logger.Error("Failed to delete question", zap.Error(err))
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateSection adds a section to the form. Without a position it goes
+// after the last section.
+func (s *Service) CreateSection(ctx context.Context, formID uuid.UUID, input SectionInput) (FormSection, error) {
+	_, err := s.authorizeEdit(ctx, formID)
+	if err != nil {
+		return FormSection{}, err
+	}
+
+	rules, err := json.Marshal(input.Rules)
+	if err != nil {
+		s.logger.Error("Failed to encode section rules", zap.Error(err))
+		return FormSection{}, err
+	}
+
+	proposed := FormSection{ID: uuid.New(), Rules: rules}
+	err = s.checkRules(ctx, formID, func(sections []FormSection, questions []Question) ([]FormSection, []Question) {
+		return upsertSection(sections, proposed, input.Position), questions
+	})
+	if err != nil {
+		return FormSection{}, err
+	}
+
+	result, err := s.queries.CreateSection(ctx, CreateSectionParams{
+		FormID:      formID,
+		Title:       input.Title,
+		Description: pgtype.Text{String: input.Description, Valid: input.Description != ""},
+		Position:    positionParam(input.Position),
+		Rules:       rules,
+	})
+	if err != nil {
+		s.logger.Error("Failed to create section", zap.Error(err))
+		return FormSection{}, err
+	}
+
+	s.logger.Info("Created section", zap.String("form_id", formID.String()), zap.String("section_id", result.ID.String()))
+
+	return result, nil
+}
+
+func (s *Service) ListSections(ctx context.Context, formID uuid.UUID) ([]FormSection, error) {
+	_, err := s.Visible(ctx, formID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.queries.ListSections(ctx, formID)
+	if err != nil {
+		s.logger.Error("Failed to list sections", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *Service) UpdateSection(ctx context.Context, formID, sectionID uuid.UUID, input SectionInput) (FormSection, error) {
+	_, err := s.authorizeEdit(ctx, formID)
+	if err != nil {
+		return FormSection{}, err
+	}
+
+	rules, err := json.Marshal(input.Rules)
+	if err != nil {
+		s.logger.Error("Failed to encode section rules", zap.Error(err))
+		return FormSection{}, err
+	}
+
+	proposed := FormSection{ID: sectionID, Rules: rules}
+	err = s.checkRules(ctx, formID, func(sections []FormSection, questions []Question) ([]FormSection, []Question) {
+		return upsertSection(sections, proposed, input.Position), questions
+	})
+	if err != nil {
+		return FormSection{}, err
+	}
+
+	result, err := s.queries.UpdateSection(ctx, UpdateSectionParams{
+		ID:          sectionID,
+		FormID:      formID,
+		Title:       input.Title,
+		Description: pgtype.Text{String: input.Description, Valid: input.Description != ""},
+		Position:    positionParam(input.Position),
+		Rules:       rules,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return FormSection{}, ErrNotFound
+		}
+		s.logger.Error("Failed to update section", zap.Error(err))
+		return FormSection{}, err
+	}
+
+	return result, nil
+}
+
+// DeleteSection removes a section. Its questions are kept and move in front
+// of the first section.
+func (s *Service) DeleteSection(ctx context.Context, formID, sectionID uuid.UUID) error {
+	_, err := s.authorizeEdit(ctx, formID)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkRules(ctx, formID, func(sections []FormSection, questions []Question) ([]FormSection, []Question) {
+		sections = slices.DeleteFunc(slices.Clone(sections), func(section FormSection) bool { return section.ID == sectionID })
+		questions = slices.Clone(questions)
+		for i := range questions {
+			if questions[i].SectionID.Valid && uuid.UUID(questions[i].SectionID.Bytes) == sectionID {
+				questions[i].SectionID = pgtype.UUID{}
+			}
+		}
+		return sections, questions
+	})
+	if err != nil {
+		return err
+	}
+
+	affected, err := s.queries.DeleteSection(ctx, DeleteSectionParams{
+		ID:     sectionID,
+		FormID: formID,
+	})
+	if err != nil {
+		s.logger.Error("Failed to delete section", zap.Error(err))
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ValidateAnswers evaluates the rules of the form for answers without
+// storing anything, see CheckAnswers.
+func (s *Service) ValidateAnswers(ctx context.Context, formID uuid.UUID, answers map[uuid.UUID]json.RawMessage) (logic.Result, map[string]string, error) {
+	_, err := s.Visible(ctx, formID)
+	if err != nil {
+		return logic.Result{}, nil, err
+	}
+
+	sections, questions, err := s.structure(ctx, formID)
+	if err != nil {
+		return logic.Result{}, nil, err
+	}
+
+	return CheckAnswers(sections, questions, answers)
+}
+
+func (s *Service) structure(ctx context.Context, formID uuid.UUID) ([]FormSection, []Question, error) {
+	sections, err := s.queries.ListSections(ctx, formID)
+	if err != nil {
+		s.logger.Error("Failed to list sections", zap.Error(err))
+		return nil, nil, err
+	}
+	questions, err := s.queries.ListQuestions(ctx, formID)
+	if err != nil {
+		s.logger.Error("Failed to list questions", zap.Error(err))
+		return nil, nil, err
+	}
+	return sections, questions, nil
+}
+
+// checkRules validates the rules of the whole form as they will be after
+// change, so a change to one question or section cannot break the rules of
+// another.
+func (s *Service) checkRules(ctx context.Context, formID uuid.UUID, change func([]FormSection, []Question) ([]FormSection, []Question)) error {
+	sections, questions, err := s.structure(ctx, formID)
+	if err != nil {
+		return err
+	}
+	sections, questions = change(sections, questions)
+
+	for _, question := range questions {
+		if question.SectionID.Valid && !slices.ContainsFunc(sections, func(section FormSection) bool {
+			return section.ID == uuid.UUID(question.SectionID.Bytes)
+		}) {
+			return fmt.Errorf("%w: section %s does not belong to this form", ErrInvalidQuestion, uuid.UUID(question.SectionID.Bytes))
+		}
+	}
+
+	structure, err := BuildLogic(sections, questions)
+	if err != nil {
+		s.logger.Error("Failed to decode form rules", zap.String("form_id", formID.String()), zap.Error(err))
+		return err
+	}
+	return structure.Validate()
+}
+
+func (s *Service) encodeQuestion(input QuestionInput) ([]byte, []byte, error) {
+	settings, err := json.Marshal(input.Settings)
+	if err != nil {
+		s.logger.Error("Failed to encode question settings", zap.Error(err))
+		return nil, nil, err
+	}
+	rules, err := json.Marshal(input.Rules)
+	if err != nil {
+		s.logger.Error("Failed to encode question rules", zap.Error(err))
+		return nil, nil, err
+	}
+	return settings, rules, nil
+}
+
+// InviteCollaborator gives the user with the given email a role on the form.
+// The invitation is attached to the user as soon as they sign in.
+func (s *Service) InviteCollaborator(ctx context.Context, formID uuid.UUID, email string, role Role) (FormCollaborator, error) {
+	if !role.IsValid() {
+		return FormCollaborator{}, ErrInvalidRole
+	}
+
+	_, err := s.Authorize(ctx, formID, RoleOwner)
+	if err != nil {
+		return FormCollaborator{}, err
+	}
+	invitedBy := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+
+	result, err := s.queries.CreateCollaborator(ctx, CreateCollaboratorParams{
+		FormID:    formID,
+		Email:     normalizeEmail(email),
+		Role:      string(role),
+		InvitedBy: invitedBy,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return FormCollaborator{}, ErrConflict
+		}
+		s.logger.Error("Failed to invite collaborator", zap.Error(err))
+		return FormCollaborator{}, err
+	}
+
+	s.logger.Info("Invited collaborator", zap.String("form_id", formID.String()), zap.String("email", result.Email), zap.String("role", result.Role))
+
+	return result, nil
+}
+
+func (s *Service) ListCollaborators(ctx context.Context, formID uuid.UUID) ([]FormCollaborator, error) {
+	_, err := s.authorizeContent(ctx, formID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.queries.ListCollaborators(ctx, formID)
+	if err != nil {
+		s.logger.Error("Failed to list collaborators", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *Service) UpdateCollaborator(ctx context.Context, formID uuid.UUID, email string, role Role) (FormCollaborator, error) {
+	if !role.IsValid() {
+		return FormCollaborator{}, ErrInvalidRole
+	}
+
+	_, err := s.Authorize(ctx, formID, RoleOwner)
+	if err != nil {
+		return FormCollaborator{}, err
+	}
+
+	result, err := s.queries.UpdateCollaboratorRole(ctx, UpdateCollaboratorRoleParams{
+		FormID: formID,
+		Email:  normalizeEmail(email),
+		Role:   string(role),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return FormCollaborator{}, ErrNotFound
+		}
+		s.logger.Error("Failed to update collaborator", zap.Error(err))
+		return FormCollaborator{}, err
+	}
+
+	return result, nil
+}
+
+func (s *Service) RemoveCollaborator(ctx context.Context, formID uuid.UUID, email string) error {
+	_, err := s.Authorize(ctx, formID, RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	affected, err := s.queries.DeleteCollaborator(ctx, DeleteCollaboratorParams{
+		FormID: formID,
+		Email:  normalizeEmail(email),
+	})
+	if err != nil {
+		s.logger.Error("Failed to remove collaborator", zap.Error(err))
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// AttachInvitations links pending invitations for the email to the user. It is
+// called on every sign in, invitations that are already attached are left alone.
+func (s *Service) AttachInvitations(ctx context.Context, userID uuid.UUID, email string) error {
+	affected, err := s.queries.AttachInvitations(ctx, AttachInvitationsParams{
+		UserID: userID,
+		Email:  normalizeEmail(email),
+	})
+	if err != nil {
+		s.logger.Error("Failed to attach invitations", zap.Error(err))
+		return err
+	}
+
+	if affected > 0 {
+		s.logger.Info("Attached form invitations", zap.String("user_id", userID.String()), zap.Int64("count", affected))
+	}
+
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func sectionParam(sectionID *uuid.UUID) pgtype.UUID {
+	if sectionID == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: *sectionID, Valid: true}
+}
+
+func versionParam(version *int32) pgtype.Int4 {
+	if version == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *version, Valid: true}
+}
+
+func positionParam(position *int32) pgtype.Int4 {
+	if position == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *position, Valid: true}
+}
@@ -0,0 +1,1339 @@
+package form_test
+
+import (
+	"awesomeProject/internal/form"
+	"awesomeProject/internal/form/definition"
+	"awesomeProject/internal/form/logic"
+	"awesomeProject/internal/form/mocks"
+	"awesomeProject/internal/jwt"
+	"awesomeProject/internal/pagination"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_Update(t *testing.T) {
+	testFormID := uuid.New()
+	errRevision := errors.New("revision failed")
+	authorID := uuid.New()
+	editorID := uuid.New()
+	viewerID := uuid.New()
+	tests := []struct {
+		name        string
+		userID      uuid.UUID // ID of the user making the request
+		version     *int32    // version from If-Match, nil when absent
+		setMock     func(querier *mocks.Querier, tx *mocks.Transactor)
+		expectError error
+	}{
+		{
+			name:   "Author updates the form",
+			userID: authorID,
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(form.Form{
+					ID:       testFormID,
+					AuthorID: pgtype.Text{String: authorID.String(), Valid: true},
+				}, nil)
+				tx.On("InTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(form.Querier) error) error {
+					return fn(querier)
+				})
+				querier.On("Update", mock.Anything, form.UpdateParams{
+					ID:          testFormID,
+					Title:       "New title",
+					Description: pgtype.Text{String: "New description", Valid: true},
+				}).Return(form.Form{
+					ID:          testFormID,
+					Title:       "New title",
+					Description: pgtype.Text{String: "New description", Valid: true},
+				}, nil)
+				querier.On("CreateRevision", mock.Anything, form.CreateRevisionParams{
+					FormID:    testFormID,
+					Snapshot:  []byte(`{"title":"New title","description":"New description"}`),
+					CreatedBy: authorID,
+				}).Return(form.FormRevision{FormID: testFormID, Number: 2}, nil)
+			},
+		},
+		{
+			name:   "Editor updates the form",
+			userID: editorID,
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(form.Form{
+					ID:       testFormID,
+					AuthorID: pgtype.Text{String: authorID.String(), Valid: true},
+				}, nil)
+				querier.On("GetCollaboratorRole", mock.Anything, form.GetCollaboratorRoleParams{
+					FormID: testFormID,
+					UserID: editorID,
+				}).Return("editor", nil)
+				tx.On("InTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(form.Querier) error) error {
+					return fn(querier)
+				})
+				querier.On("Update", mock.Anything, mock.Anything).Return(form.Form{ID: testFormID, Title: "New title"}, nil)
+				querier.On("CreateRevision", mock.Anything, mock.Anything).Return(form.FormRevision{FormID: testFormID, Number: 2}, nil)
+			},
+		},
+		{
+			name:   "Failed revision fails the update",
+			userID: authorID,
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(form.Form{
+					ID:       testFormID,
+					AuthorID: pgtype.Text{String: authorID.String(), Valid: true},
+				}, nil)
+				tx.On("InTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(form.Querier) error) error {
+					return fn(querier)
+				})
+				querier.On("Update", mock.Anything, mock.Anything).Return(form.Form{ID: testFormID, Title: "New title"}, nil)
+				querier.On("CreateRevision", mock.Anything, mock.Anything).Return(form.FormRevision{}, errRevision)
+			},
+			expectError: errRevision,
+		},
+		{
+			name:   "Viewer is forbidden",
+			userID: viewerID,
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(form.Form{
+					ID:       testFormID,
+					AuthorID: pgtype.Text{String: authorID.String(), Valid: true},
+				}, nil)
+				querier.On("GetCollaboratorRole", mock.Anything, form.GetCollaboratorRoleParams{
+					FormID: testFormID,
+					UserID: viewerID,
+				}).Return("viewer", nil)
+			},
+			expectError: form.ErrForbidden,
+		},
+		{
+			name:   "Other user is forbidden",
+			userID: uuid.New(),
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(form.Form{
+					ID:       testFormID,
+					AuthorID: pgtype.Text{String: authorID.String(), Valid: true},
+				}, nil)
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("", pgx.ErrNoRows)
+			},
+			expectError: form.ErrForbidden,
+		},
+		{
+			name:   "Closed form is read-only",
+			userID: authorID,
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(form.Form{
+					ID:       testFormID,
+					AuthorID: pgtype.Text{String: authorID.String(), Valid: true},
+					Status:   "closed",
+				}, nil)
+			},
+			expectError: form.ErrReadOnly,
+		},
+		{
+			name:    "Stale version",
+			userID:  authorID,
+			version: ptr(int32(2)),
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(form.Form{
+					ID:       testFormID,
+					AuthorID: pgtype.Text{String: authorID.String(), Valid: true},
+					Version:  3,
+				}, nil)
+			},
+			expectError: form.ErrVersionMismatch,
+		},
+		{
+			name:    "Version changes after the form was read",
+			userID:  authorID,
+			version: ptr(int32(3)),
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(form.Form{
+					ID:       testFormID,
+					AuthorID: pgtype.Text{String: authorID.String(), Valid: true},
+					Version:  3,
+				}, nil)
+				tx.On("InTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(form.Querier) error) error {
+					return fn(querier)
+				})
+				querier.On("Update", mock.Anything, form.UpdateParams{
+					Title:           "New title",
+					Description:     pgtype.Text{String: "New description", Valid: true},
+					ID:              testFormID,
+					ExpectedVersion: pgtype.Int4{Int32: 3, Valid: true},
+				}).Return(form.Form{}, pgx.ErrNoRows)
+			},
+			expectError: form.ErrVersionMismatch,
+		},
+		{
+			name:   "Form does not exist",
+			userID: authorID,
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(form.Form{}, pgx.ErrNoRows)
+			},
+			expectError: form.ErrNotFound,
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			tx := mocks.NewTransactor(t)
+			tt.setMock(querier, tx)
+			service := form.NewService(logger, querier, tx)
+
+			ctx := context.WithValue(context.Background(), jwt.UserContextKey, tt.userID)
+			_, err := service.Update(ctx, testFormID, "New title", "New description", tt.version)
+			if tt.expectError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestService_List(t *testing.T) {
+	userID := uuid.New()
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	page := []form.ListWithBookmarksRow{
+		{ID: uuid.New(), Title: "a", CreatedAt: pgtype.Timestamptz{Time: createdAt.Add(2 * time.Hour), Valid: true}},
+		{ID: uuid.New(), Title: "b", CreatedAt: pgtype.Timestamptz{Time: createdAt.Add(time.Hour), Valid: true}},
+		{ID: uuid.New(), Title: "c", CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true}},
+	}
+	logger := zaptest.NewLogger(t)
+
+	querier := mocks.NewQuerier(t)
+	querier.On("ListWithBookmarks", mock.Anything, form.ListWithBookmarksParams{
+		UserID:     userID,
+		AuthorID:   pgtype.Text{String: userID.String(), Valid: true},
+		SortBy:     "created_at",
+		Descending: true,
+		PageLimit:  3,
+	}).Return(page, nil)
+	service := form.NewService(logger, querier, nil)
+
+	opts := form.ListOptions{
+		Page:       pagination.Params{Limit: 2},
+		Descending: true,
+		AuthorOnly: true,
+	}
+	forms, next, err := service.List(context.Background(), userID, opts)
+	assert.NoError(t, err)
+	assert.Len(t, forms, 2)
+	assert.NotEmpty(t, next)
+
+	// the next page continues after the last returned form
+	cursor, err := pagination.DecodeCursor(next)
+	assert.NoError(t, err)
+	querier.On("ListWithBookmarks", mock.Anything, form.ListWithBookmarksParams{
+		UserID:          userID,
+		AuthorID:        pgtype.Text{String: userID.String(), Valid: true},
+		CursorID:        pgtype.UUID{Bytes: page[1].ID, Valid: true},
+		SortBy:          "created_at",
+		Descending:      true,
+		CursorCreatedAt: pgtype.Timestamptz{Time: page[1].CreatedAt.Time, Valid: true},
+		PageLimit:       3,
+	}).Return(page[2:], nil)
+	opts.Page.Cursor = &cursor
+	forms, next, err = service.List(context.Background(), userID, opts)
+	assert.NoError(t, err)
+	assert.Len(t, forms, 1)
+	assert.Empty(t, next)
+
+	// a cursor can't be reused with another ordering
+	opts.SortBy = form.SortByTitle
+	_, _, err = service.List(context.Background(), userID, opts)
+	assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+}
+
+func TestService_Search(t *testing.T) {
+	userID := uuid.New()
+	rows := []form.SearchRow{
+		{ID: uuid.New(), Title: "Team lunch", Rank: 0.6079271},
+		{ID: uuid.New(), Title: "Lunch survey", Rank: 0.30396354},
+	}
+	logger := zaptest.NewLogger(t)
+
+	querier := mocks.NewQuerier(t)
+	querier.On("Search", mock.Anything, form.SearchParams{
+		Query:     "lunch",
+		UserID:    userID,
+		PageLimit: 2,
+	}).Return(rows, nil)
+	service := form.NewService(logger, querier, nil)
+
+	results, next, err := service.Search(context.Background(), userID, " lunch ", pagination.Params{Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	// the rank in the cursor survives the round trip exactly
+	cursor, err := pagination.DecodeCursor(next)
+	assert.NoError(t, err)
+	querier.On("Search", mock.Anything, form.SearchParams{
+		Query:      "lunch",
+		UserID:     userID,
+		CursorID:   pgtype.UUID{Bytes: rows[0].ID, Valid: true},
+		CursorRank: rows[0].Rank,
+		PageLimit:  2,
+	}).Return(rows[1:], nil)
+	results, next, err = service.Search(context.Background(), userID, "lunch", pagination.Params{Limit: 1, Cursor: &cursor})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Empty(t, next)
+
+	// the cursor belongs to the query it was created for
+	_, _, err = service.Search(context.Background(), userID, "dinner", pagination.Params{Limit: 1, Cursor: &cursor})
+	assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+
+	_, _, err = service.Search(context.Background(), userID, "  ", pagination.Params{Limit: 1})
+	assert.ErrorIs(t, err, form.ErrEmptyQuery)
+}
+
+func TestService_EditorOnDraft(t *testing.T) {
+	testFormID := uuid.New()
+	authorID := uuid.New()
+	editorID := uuid.New()
+	input := form.QuestionInput{Type: form.QuestionTypeShortText, Title: "Name"}
+	tests := []struct {
+		name        string
+		userID      uuid.UUID
+		status      form.Status
+		call        func(service *form.Service, ctx context.Context) error
+		setMock     func(querier *mocks.Querier)
+		expectError error
+	}{
+		{
+			name:   "Editor cannot add questions to a draft",
+			userID: editorID,
+			status: form.StatusDraft,
+			call: func(service *form.Service, ctx context.Context) error {
+				_, err := service.CreateQuestion(ctx, testFormID, input)
+				return err
+			},
+			setMock:     func(querier *mocks.Querier) {},
+			expectError: form.ErrNotFound,
+		},
+		{
+			name:   "Editor cannot delete sections of a draft",
+			userID: editorID,
+			status: form.StatusDraft,
+			call: func(service *form.Service, ctx context.Context) error {
+				return service.DeleteSection(ctx, testFormID, uuid.New())
+			},
+			setMock:     func(querier *mocks.Querier) {},
+			expectError: form.ErrNotFound,
+		},
+		{
+			name:   "Editor cannot export a draft",
+			userID: editorID,
+			status: form.StatusDraft,
+			call: func(service *form.Service, ctx context.Context) error {
+				_, _, err := service.Definition(ctx, testFormID)
+				return err
+			},
+			setMock:     func(querier *mocks.Querier) {},
+			expectError: form.ErrNotFound,
+		},
+		{
+			name:   "Editor cannot list transitions of a draft",
+			userID: editorID,
+			status: form.StatusDraft,
+			call: func(service *form.Service, ctx context.Context) error {
+				_, err := service.ListTransitions(ctx, testFormID)
+				return err
+			},
+			setMock:     func(querier *mocks.Querier) {},
+			expectError: form.ErrNotFound,
+		},
+		{
+			name:   "Editor cannot list collaborators of a draft",
+			userID: editorID,
+			status: form.StatusDraft,
+			call: func(service *form.Service, ctx context.Context) error {
+				_, err := service.ListCollaborators(ctx, testFormID)
+				return err
+			},
+			setMock:     func(querier *mocks.Querier) {},
+			expectError: form.ErrNotFound,
+		},
+		{
+			name:   "Owner adds questions to a draft",
+			userID: authorID,
+			status: form.StatusDraft,
+			call: func(service *form.Service, ctx context.Context) error {
+				_, err := service.CreateQuestion(ctx, testFormID, input)
+				return err
+			},
+			setMock: func(querier *mocks.Querier) {
+				querier.On("ListSections", mock.Anything, testFormID).Return(nil, nil)
+				querier.On("ListQuestions", mock.Anything, testFormID).Return(nil, nil)
+				querier.On("CreateQuestion", mock.Anything, mock.Anything).Return(form.Question{ID: uuid.New()}, nil)
+			},
+		},
+		{
+			name:   "Editor adds questions to a published form",
+			userID: editorID,
+			status: form.StatusPublished,
+			call: func(service *form.Service, ctx context.Context) error {
+				_, err := service.CreateQuestion(ctx, testFormID, input)
+				return err
+			},
+			setMock: func(querier *mocks.Querier) {
+				querier.On("ListSections", mock.Anything, testFormID).Return(nil, nil)
+				querier.On("ListQuestions", mock.Anything, testFormID).Return(nil, nil)
+				querier.On("CreateQuestion", mock.Anything, mock.Anything).Return(form.Question{ID: uuid.New()}, nil)
+			},
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			querier.On("Get", mock.Anything, testFormID).Return(form.Form{
+				ID:       testFormID,
+				AuthorID: pgtype.Text{String: authorID.String(), Valid: true},
+				Status:   string(tt.status),
+			}, nil)
+			querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("editor", nil).Maybe()
+			tt.setMock(querier)
+			service := form.NewService(logger, querier, nil)
+
+			ctx := context.WithValue(context.Background(), jwt.UserContextKey, tt.userID)
+			err := tt.call(service, ctx)
+			if tt.expectError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestService_Transition(t *testing.T) {
+	testFormID := uuid.New()
+	authorID := uuid.New()
+	editorID := uuid.New()
+	tests := []struct {
+		name         string
+		userID       uuid.UUID // ID of the user making the request
+		status       form.Status
+		action       form.Action
+		setMock      func(querier *mocks.Querier)
+		expectStatus form.Status
+		expectError  error
+	}{
+		{
+			name:   "Author publishes a draft",
+			userID: authorID,
+			status: form.StatusDraft,
+			action: form.ActionPublish,
+			setMock: func(querier *mocks.Querier) {
+				querier.On("Transition", mock.Anything, form.TransitionParams{
+					ToStatus:   "published",
+					FormID:     testFormID,
+					FromStatus: "draft",
+					ChangedBy:  authorID,
+				}).Return(form.FormStatusTransition{}, nil)
+			},
+			expectStatus: form.StatusPublished,
+		},
+		{
+			name:   "Editor reopens a closed form",
+			userID: editorID,
+			status: form.StatusClosed,
+			action: form.ActionReopen,
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("editor", nil)
+				querier.On("Transition", mock.Anything, mock.Anything).Return(form.FormStatusTransition{}, nil)
+			},
+			expectStatus: form.StatusPublished,
+		},
+		{
+			name:   "Editor cannot publish a draft",
+			userID: editorID,
+			status: form.StatusDraft,
+			action: form.ActionPublish,
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("editor", nil)
+			},
+			expectError: form.ErrForbidden,
+		},
+		{
+			name:   "Editor cannot archive",
+			userID: editorID,
+			status: form.StatusPublished,
+			action: form.ActionArchive,
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("editor", nil)
+			},
+			expectError: form.ErrForbidden,
+		},
+		{
+			name:        "Draft cannot be closed",
+			userID:      authorID,
+			status:      form.StatusDraft,
+			action:      form.ActionClose,
+			setMock:     func(querier *mocks.Querier) {},
+			expectError: form.ErrInvalidAction,
+		},
+		{
+			name:        "Archived form cannot be reopened",
+			userID:      authorID,
+			status:      form.StatusArchived,
+			action:      form.ActionReopen,
+			setMock:     func(querier *mocks.Querier) {},
+			expectError: form.ErrInvalidAction,
+		},
+		{
+			name:   "Status changed concurrently",
+			userID: authorID,
+			status: form.StatusPublished,
+			action: form.ActionClose,
+			setMock: func(querier *mocks.Querier) {
+				querier.On("Transition", mock.Anything, mock.Anything).Return(form.FormStatusTransition{}, pgx.ErrNoRows)
+			},
+			expectError: form.ErrInvalidAction,
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			querier.On("Get", mock.Anything, testFormID).Return(form.Form{
+				ID:       testFormID,
+				AuthorID: pgtype.Text{String: authorID.String(), Valid: true},
+				Status:   string(tt.status),
+			}, nil)
+			tt.setMock(querier)
+			service := form.NewService(logger, querier, nil)
+
+			ctx := context.WithValue(context.Background(), jwt.UserContextKey, tt.userID)
+			result, err := service.Transition(ctx, testFormID, tt.action)
+			if tt.expectError == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, string(tt.expectStatus), result.Status)
+			} else {
+				assert.ErrorIs(t, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestService_Restore(t *testing.T) {
+	testFormID := uuid.New()
+	authorID := uuid.New()
+	deleted := form.Form{
+		ID:        testFormID,
+		AuthorID:  pgtype.Text{String: authorID.String(), Valid: true},
+		DeletedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	tests := []struct {
+		name        string
+		userID      uuid.UUID // ID of the user making the request
+		setMock     func(querier *mocks.Querier)
+		expectError error
+	}{
+		{
+			name:   "Author restores the form",
+			userID: authorID,
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetDeleted", mock.Anything, testFormID).Return(deleted, nil)
+				querier.On("Restore", mock.Anything, testFormID).Return(form.Form{ID: testFormID}, nil)
+			},
+		},
+		{
+			name:   "Editor cannot restore",
+			userID: uuid.New(),
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetDeleted", mock.Anything, testFormID).Return(deleted, nil)
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("editor", nil)
+			},
+			expectError: form.ErrForbidden,
+		},
+		{
+			name:   "Form is not in the trash",
+			userID: authorID,
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetDeleted", mock.Anything, testFormID).Return(form.Form{}, pgx.ErrNoRows)
+			},
+			expectError: form.ErrNotFound,
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			tt.setMock(querier)
+			service := form.NewService(logger, querier, nil)
+
+			ctx := context.WithValue(context.Background(), jwt.UserContextKey, tt.userID)
+			_, err := service.Restore(ctx, testFormID)
+			if tt.expectError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestService_Rollback(t *testing.T) {
+	testFormID := uuid.New()
+	authorID := uuid.New()
+	current := form.Form{
+		ID:       testFormID,
+		Title:    "Current title",
+		AuthorID: pgtype.Text{String: authorID.String(), Valid: true},
+	}
+	tests := []struct {
+		name        string
+		userID      uuid.UUID // ID of the user making the request
+		setMock     func(querier *mocks.Querier, tx *mocks.Transactor)
+		expectError error
+	}{
+		{
+			name:   "Author rolls back to an earlier revision",
+			userID: authorID,
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(current, nil)
+				querier.On("GetRevision", mock.Anything, form.GetRevisionParams{
+					FormID: testFormID,
+					Number: 1,
+				}).Return(form.FormRevision{
+					FormID:   testFormID,
+					Number:   1,
+					Snapshot: []byte(`{"title": "Old title", "description": "Old description"}`),
+				}, nil)
+				tx.On("InTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(form.Querier) error) error {
+					return fn(querier)
+				})
+				querier.On("Update", mock.Anything, form.UpdateParams{
+					ID:          testFormID,
+					Title:       "Old title",
+					Description: pgtype.Text{String: "Old description", Valid: true},
+				}).Return(form.Form{
+					ID:          testFormID,
+					Title:       "Old title",
+					Description: pgtype.Text{String: "Old description", Valid: true},
+				}, nil)
+				querier.On("CreateRevision", mock.Anything, mock.Anything).Return(form.FormRevision{FormID: testFormID, Number: 3}, nil)
+			},
+		},
+		{
+			name:   "Revision does not exist",
+			userID: authorID,
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(current, nil)
+				querier.On("GetRevision", mock.Anything, mock.Anything).Return(form.FormRevision{}, pgx.ErrNoRows)
+			},
+			expectError: form.ErrNotFound,
+		},
+		{
+			name:   "Viewer is forbidden",
+			userID: uuid.New(),
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(current, nil)
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("viewer", nil)
+			},
+			expectError: form.ErrForbidden,
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			tx := mocks.NewTransactor(t)
+			tt.setMock(querier, tx)
+			service := form.NewService(logger, querier, tx)
+
+			ctx := context.WithValue(context.Background(), jwt.UserContextKey, tt.userID)
+			_, err := service.Rollback(ctx, testFormID, 1)
+			if tt.expectError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestService_ListRevisions(t *testing.T) {
+	testFormID := uuid.New()
+	authorID := uuid.New()
+	tests := []struct {
+		name        string
+		userID      uuid.UUID // ID of the user making the request
+		status      form.Status
+		setMock     func(querier *mocks.Querier)
+		expectError error
+	}{
+		{
+			name:   "Viewer lists the revisions",
+			userID: uuid.New(),
+			status: form.StatusPublished,
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("viewer", nil)
+				querier.On("ListRevisions", mock.Anything, testFormID).Return([]form.FormRevision{{FormID: testFormID, Number: 1}}, nil)
+			},
+		},
+		{
+			name:   "Other user cannot read the history of a published form",
+			userID: uuid.New(),
+			status: form.StatusPublished,
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("", pgx.ErrNoRows)
+			},
+			expectError: form.ErrForbidden,
+		},
+		{
+			name:   "Editor cannot read the history of a draft",
+			userID: uuid.New(),
+			status: form.StatusDraft,
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("editor", nil)
+			},
+			expectError: form.ErrNotFound,
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			querier.On("Get", mock.Anything, testFormID).Return(form.Form{
+				ID:       testFormID,
+				AuthorID: pgtype.Text{String: authorID.String(), Valid: true},
+				Status:   string(tt.status),
+			}, nil)
+			tt.setMock(querier)
+			service := form.NewService(logger, querier, nil)
+
+			ctx := context.WithValue(context.Background(), jwt.UserContextKey, tt.userID)
+			_, err := service.ListRevisions(ctx, testFormID)
+			if tt.expectError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   string
+		to     string
+		expect []form.FieldChange
+	}{
+		{
+			name:   "Nothing changed",
+			from:   `{"title": "Survey", "description": ""}`,
+			to:     `{"title": "Survey", "description": ""}`,
+			expect: []form.FieldChange{},
+		},
+		{
+			name: "Title changed",
+			from: `{"title": "Survey", "description": "About you"}`,
+			to:   `{"title": "Team survey", "description": "About you"}`,
+			expect: []form.FieldChange{
+				{Field: "title", From: []byte(`"Survey"`), To: []byte(`"Team survey"`)},
+			},
+		},
+		{
+			name: "Field only in one revision",
+			from: `{"title": "Survey"}`,
+			to:   `{"title": "Survey", "description": "About you"}`,
+			expect: []form.FieldChange{
+				{Field: "description", To: []byte(`"About you"`)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := form.Diff(
+				form.FormRevision{Number: 1, Snapshot: []byte(tt.from)},
+				form.FormRevision{Number: 2, Snapshot: []byte(tt.to)},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expect, changes)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestQuestion_Grade(t *testing.T) {
+	tests := []struct {
+		name         string
+		question     form.Question
+		answer       string
+		expectPoints float64
+		expectGraded bool
+	}{
+		{"text ignores case and spaces", form.Question{Type: "short_text", Points: 2, AnswerKey: []byte(`"Paris"`)}, `" paris "`, 2, true},
+		{"wrong text", form.Question{Type: "short_text", Points: 2, AnswerKey: []byte(`"Paris"`)}, `"Lyon"`, 0, true},
+		{"multiple choice in any order", form.Question{Type: "multiple_choice", Points: 1, AnswerKey: []byte(`["a","b"]`)}, `["b","a"]`, 1, true},
+		{"multiple choice missing a choice", form.Question{Type: "multiple_choice", Points: 1, AnswerKey: []byte(`["a","b"]`)}, `["a"]`, 0, true},
+		{"number", form.Question{Type: "number", Points: 1, AnswerKey: []byte(`4`)}, `4.0`, 1, true},
+		{"no key waits for manual grading", form.Question{Type: "paragraph", Points: 5}, `"Because"`, 0, false},
+		{"no points", form.Question{Type: "paragraph"}, `"Because"`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, graded := tt.question.Grade(json.RawMessage(tt.answer))
+			assert.Equal(t, tt.expectPoints, points)
+			assert.Equal(t, tt.expectGraded, graded)
+		})
+	}
+}
+
+func TestService_CreateQuestion(t *testing.T) {
+	testFormID := uuid.New()
+	authorID := uuid.New()
+	existingID := uuid.New()
+	existing := []form.Question{{ID: existingID, FormID: testFormID, Position: 0}}
+	tests := []struct {
+		name        string
+		input       form.QuestionInput
+		setMock     func(querier *mocks.Querier)
+		expectError error
+	}{
+		{
+			name: "Rule on an earlier question",
+			input: form.QuestionInput{
+				Type:  form.QuestionTypeShortText,
+				Title: "Why?",
+				Rules: logic.QuestionRules{ShowIf: &logic.Condition{Question: existingID, Op: logic.OpAnswered}},
+			},
+			setMock: func(querier *mocks.Querier) {
+				querier.On("ListSections", mock.Anything, testFormID).Return(nil, nil)
+				querier.On("ListQuestions", mock.Anything, testFormID).Return(existing, nil)
+				querier.On("CreateQuestion", mock.Anything, mock.Anything).Return(form.Question{ID: uuid.New()}, nil)
+			},
+		},
+		{
+			name: "Rule on a question of another form",
+			input: form.QuestionInput{
+				Type:  form.QuestionTypeShortText,
+				Title: "Why?",
+				Rules: logic.QuestionRules{ShowIf: &logic.Condition{Question: uuid.New(), Op: logic.OpAnswered}},
+			},
+			setMock: func(querier *mocks.Querier) {
+				querier.On("ListSections", mock.Anything, testFormID).Return(nil, nil)
+				querier.On("ListQuestions", mock.Anything, testFormID).Return(existing, nil)
+			},
+			expectError: form.ErrInvalidRule,
+		},
+		{
+			name: "Section of another form",
+			input: form.QuestionInput{
+				Type:      form.QuestionTypeShortText,
+				Title:     "Why?",
+				SectionID: ptr(uuid.New()),
+			},
+			setMock: func(querier *mocks.Querier) {
+				querier.On("ListSections", mock.Anything, testFormID).Return(nil, nil)
+				querier.On("ListQuestions", mock.Anything, testFormID).Return(existing, nil)
+			},
+			expectError: form.ErrInvalidQuestion,
+		},
+		{
+			name: "Answer key outside of the choices",
+			input: form.QuestionInput{
+				Type:      form.QuestionTypeSingleChoice,
+				Title:     "Capital of France?",
+				Settings:  form.QuestionSettings{Choices: []string{"Paris", "Lyon"}},
+				Points:    1,
+				AnswerKey: json.RawMessage(`"Nice"`),
+			},
+			setMock:     func(querier *mocks.Querier) {},
+			expectError: form.ErrInvalidQuestion,
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			querier.On("Get", mock.Anything, testFormID).Return(form.Form{
+				ID:       testFormID,
+				AuthorID: pgtype.Text{String: authorID.String(), Valid: true},
+			}, nil)
+			tt.setMock(querier)
+			service := form.NewService(logger, querier, nil)
+
+			ctx := context.WithValue(context.Background(), jwt.UserContextKey, authorID)
+			_, err := service.CreateQuestion(ctx, testFormID, tt.input)
+			if tt.expectError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestService_CreateFromTemplate(t *testing.T) {
+	templateID := uuid.New()
+	authorID := uuid.New()
+	userID := uuid.New()
+	sectionID := uuid.New()
+	firstID := uuid.New()
+	secondID := uuid.New()
+	template := func(visibility form.TemplateVisibility) form.Form {
+		return form.Form{
+			ID:                 templateID,
+			Title:              "Course feedback",
+			Description:        pgtype.Text{String: "Tell us what you think", Valid: true},
+			AuthorID:           pgtype.Text{String: authorID.String(), Valid: true},
+			IsQuiz:             true,
+			RevealAnswers:      string(form.RevealAfterSubmission),
+			TemplateVisibility: pgtype.Text{String: string(visibility), Valid: visibility != form.TemplateNone},
+		}
+	}
+	sections := []form.FormSection{{ID: sectionID, FormID: templateID, Rules: []byte(`{}`)}}
+	questions := []form.Question{
+		{ID: firstID, FormID: templateID, SectionID: pgtype.UUID{Bytes: sectionID, Valid: true}, Rules: []byte(`{}`)},
+		{ID: secondID, FormID: templateID, Rules: []byte(`{"show_if": {"question": "` + firstID.String() + `", "op": "answered"}}`)},
+	}
+	copied := form.Form{ID: uuid.New(), Title: "My feedback"}
+	expectCopy := func(querier *mocks.Querier, tx *mocks.Transactor, withAnswerKeys bool) {
+		tx.On("InTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(form.Querier) error) error {
+			return fn(querier)
+		})
+		querier.On("ListSections", mock.Anything, templateID).Return(sections, nil)
+		querier.On("ListQuestions", mock.Anything, templateID).Return(questions, nil)
+		querier.On("CreateCopy", mock.Anything, mock.Anything).Return(copied, nil)
+		querier.On("CopySections", mock.Anything, mock.Anything).Return(int64(1), nil)
+		querier.On("CopyQuestions", mock.Anything, mock.MatchedBy(func(arg form.CopyQuestionsParams) bool {
+			return arg.WithAnswerKeys == withAnswerKeys
+		})).Return(int64(2), nil)
+		querier.On("CreateRevision", mock.Anything, mock.Anything).Return(form.FormRevision{FormID: copied.ID, Number: 1}, nil)
+	}
+	tests := []struct {
+		name        string
+		setMock     func(querier *mocks.Querier, tx *mocks.Transactor)
+		expectError error
+	}{
+		{
+			name: "Public template is copied with new IDs",
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, templateID).Return(template(form.TemplatePublic), nil)
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("", pgx.ErrNoRows)
+				tx.On("InTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(form.Querier) error) error {
+					return fn(querier)
+				})
+				querier.On("ListSections", mock.Anything, templateID).Return(sections, nil)
+				querier.On("ListQuestions", mock.Anything, templateID).Return(questions, nil)
+				querier.On("CreateCopy", mock.Anything, form.CreateCopyParams{
+					Title:         "My feedback",
+					Description:   pgtype.Text{String: "Tell us what you think", Valid: true},
+					AuthorID:      pgtype.Text{String: userID.String(), Valid: true},
+					IsQuiz:        true,
+					RevealAnswers: string(form.RevealAfterSubmission),
+				}).Return(copied, nil)
+				querier.On("CopySections", mock.Anything, mock.MatchedBy(func(arg form.CopySectionsParams) bool {
+					return arg.FormID == copied.ID && arg.SourceID == templateID &&
+						arg.OldIds[0] == sectionID && arg.NewIds[0] != sectionID
+				})).Return(int64(1), nil)
+				querier.On("CopyQuestions", mock.Anything, mock.MatchedBy(func(arg form.CopyQuestionsParams) bool {
+					// the rule of the second question points to the copy of the first one
+					var rules logic.QuestionRules
+					err := json.Unmarshal([]byte(arg.Rules[1]), &rules)
+					return err == nil && arg.FormID == copied.ID && !arg.WithAnswerKeys &&
+						arg.NewIds[0] != firstID && rules.ShowIf.Question == arg.NewIds[0] &&
+						arg.OldSectionIds[0] == sectionID && arg.NewSectionIds[0] != sectionID
+				})).Return(int64(2), nil)
+				querier.On("CreateRevision", mock.Anything, mock.Anything).Return(form.FormRevision{FormID: copied.ID, Number: 1}, nil)
+			},
+		},
+		{
+			name: "Viewer copies a team quiz without the answer keys",
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, templateID).Return(template(form.TemplateTeam), nil)
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("viewer", nil)
+				expectCopy(querier, tx, false)
+			},
+		},
+		{
+			name: "Editor copies a team quiz with the answer keys",
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, templateID).Return(template(form.TemplateTeam), nil)
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("editor", nil)
+				expectCopy(querier, tx, true)
+			},
+		},
+		{
+			name: "Form is not a template",
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, templateID).Return(template(form.TemplateNone), nil)
+			},
+			expectError: form.ErrNotFound,
+		},
+		{
+			name: "Team template that is still a draft",
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				draft := template(form.TemplateTeam)
+				draft.Status = string(form.StatusDraft)
+				querier.On("Get", mock.Anything, templateID).Return(draft, nil)
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("editor", nil)
+			},
+			expectError: form.ErrNotFound,
+		},
+		{
+			name: "Archived template",
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				archived := template(form.TemplatePublic)
+				archived.Status = string(form.StatusArchived)
+				querier.On("Get", mock.Anything, templateID).Return(archived, nil)
+			},
+			expectError: form.ErrNotFound,
+		},
+		{
+			name: "Private template of another owner",
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, templateID).Return(template(form.TemplatePrivate), nil)
+				querier.On("GetCollaboratorRole", mock.Anything, form.GetCollaboratorRoleParams{
+					FormID: templateID,
+					UserID: userID,
+				}).Return("editor", nil)
+			},
+			expectError: form.ErrNotFound,
+		},
+		{
+			name: "Questions change while copying",
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, templateID).Return(template(form.TemplateTeam), nil)
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("viewer", nil)
+				tx.On("InTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(form.Querier) error) error {
+					return fn(querier)
+				})
+				querier.On("ListSections", mock.Anything, templateID).Return([]form.FormSection{}, nil)
+				querier.On("ListQuestions", mock.Anything, templateID).Return(questions, nil)
+				querier.On("CreateCopy", mock.Anything, mock.Anything).Return(copied, nil)
+				querier.On("CopyQuestions", mock.Anything, mock.Anything).Return(int64(1), nil)
+			},
+			expectError: form.ErrCopyConflict,
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			tx := mocks.NewTransactor(t)
+			tt.setMock(querier, tx)
+			service := form.NewService(logger, querier, tx)
+
+			ctx := context.WithValue(context.Background(), jwt.UserContextKey, userID)
+			result, err := service.CreateFromTemplate(ctx, templateID, userID, form.CopyOptions{Title: "My feedback"})
+			if tt.expectError == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, copied.ID, result.ID)
+			} else {
+				assert.ErrorIs(t, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestService_Duplicate(t *testing.T) {
+	testFormID := uuid.New()
+	authorID := uuid.New()
+	editorID := uuid.New()
+	source := form.Form{
+		ID:          testFormID,
+		Title:       "Survey",
+		Description: pgtype.Text{String: "About you", Valid: true},
+		AuthorID:    pgtype.Text{String: authorID.String(), Valid: true},
+	}
+	tests := []struct {
+		name        string
+		opts        form.CopyOptions
+		setMock     func(querier *mocks.Querier, tx *mocks.Transactor)
+		expectError error
+	}{
+		{
+			name: "Editor duplicates without a new title",
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(source, nil)
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("editor", nil)
+				tx.On("InTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(form.Querier) error) error {
+					return fn(querier)
+				})
+				querier.On("ListSections", mock.Anything, testFormID).Return([]form.FormSection{}, nil)
+				querier.On("ListQuestions", mock.Anything, testFormID).Return([]form.Question{}, nil)
+				querier.On("CreateCopy", mock.Anything, form.CreateCopyParams{
+					Title:       "Copy of Survey",
+					Description: pgtype.Text{String: "About you", Valid: true},
+					AuthorID:    pgtype.Text{String: editorID.String(), Valid: true},
+				}).Return(form.Form{ID: uuid.New(), Title: "Copy of Survey"}, nil)
+				querier.On("CreateRevision", mock.Anything, mock.Anything).Return(form.FormRevision{Number: 1}, nil)
+			},
+		},
+		{
+			name: "Failed copy is reported",
+			opts: form.CopyOptions{Title: "Renamed"},
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(source, nil)
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("owner", nil)
+				tx.On("InTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(form.Querier) error) error {
+					return fn(querier)
+				})
+				querier.On("ListSections", mock.Anything, testFormID).Return(nil, pgx.ErrTxClosed)
+			},
+			expectError: pgx.ErrTxClosed,
+		},
+		{
+			name: "Viewer cannot duplicate",
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(source, nil)
+				querier.On("GetCollaboratorRole", mock.Anything, mock.Anything).Return("viewer", nil)
+			},
+			expectError: form.ErrForbidden,
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			tx := mocks.NewTransactor(t)
+			tt.setMock(querier, tx)
+			service := form.NewService(logger, querier, tx)
+
+			ctx := context.WithValue(context.Background(), jwt.UserContextKey, editorID)
+			_, err := service.Duplicate(ctx, testFormID, editorID, tt.opts)
+			if tt.expectError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestService_Import(t *testing.T) {
+	testFormID := uuid.New()
+	authorID := uuid.New()
+	questionID := uuid.New()
+	existing := form.Form{
+		ID:       testFormID,
+		Title:    "Survey",
+		AuthorID: pgtype.Text{String: authorID.String(), Valid: true},
+		Version:  4,
+	}
+	question := func(key, questionType string) definition.Question {
+		return definition.Question{Key: key, Type: questionType, Title: "Question " + key}
+	}
+	tests := []struct {
+		name        string
+		doc         definition.Document
+		setMock     func(querier *mocks.Querier, tx *mocks.Transactor)
+		expectError []definition.FieldError // nil when the import succeeds
+		created     bool
+	}{
+		{
+			name: "Document without ID creates a form",
+			doc: definition.Document{
+				Title:    "New survey",
+				Quiz:     &definition.Quiz{RevealAnswers: "after_submission"},
+				Sections: []definition.Section{{Key: "end", Title: "The end"}},
+				Questions: []definition.Question{
+					{Key: "age", Type: "number", Title: "Age", Points: 1, AnswerKey: 42},
+					{Key: "why", Section: "end", Type: "paragraph", Title: "Why?", Rules: &definition.QuestionRules{
+						ShowIf: &definition.Condition{Question: "age", Op: "gt", Value: 40},
+					}},
+				},
+			},
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				tx.On("InTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(form.Querier) error) error {
+					return fn(querier)
+				})
+				querier.On("CreateCopy", mock.Anything, form.CreateCopyParams{
+					Title:         "New survey",
+					Description:   pgtype.Text{Valid: true},
+					AuthorID:      pgtype.Text{String: authorID.String(), Valid: true},
+					IsQuiz:        true,
+					RevealAnswers: "after_submission",
+				}).Return(form.Form{ID: testFormID, Title: "New survey"}, nil)
+				querier.On("DeleteQuestionsExcept", mock.Anything, mock.Anything).Return(int64(0), nil)
+				querier.On("DeleteSectionsExcept", mock.Anything, mock.Anything).Return(int64(0), nil)
+				querier.On("ImportSection", mock.Anything, mock.MatchedBy(func(arg form.ImportSectionParams) bool {
+					return arg.FormID == testFormID && arg.Title == "The end" && arg.Position == 0
+				})).Return(int64(1), nil)
+				querier.On("ImportQuestion", mock.Anything, mock.MatchedBy(func(arg form.ImportQuestionParams) bool {
+					return arg.Title == "Age" && arg.Position == 0 && !arg.SectionID.Valid && string(arg.AnswerKey) == "42"
+				})).Return(int64(1), nil)
+				querier.On("ImportQuestion", mock.Anything, mock.MatchedBy(func(arg form.ImportQuestionParams) bool {
+					return arg.Title == "Why?" && arg.Position == 1 && arg.SectionID.Valid
+				})).Return(int64(1), nil)
+				querier.On("CreateRevision", mock.Anything, mock.Anything).Return(form.FormRevision{Number: 1}, nil)
+			},
+			created: true,
+		},
+		{
+			name: "Keys with IDs of the form keep them",
+			doc: definition.Document{
+				ID:        testFormID.String(),
+				Title:     "Survey",
+				Questions: []definition.Question{question(questionID.String(), "short_text"), question("new", "short_text")},
+			},
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(existing, nil)
+				querier.On("ListSections", mock.Anything, testFormID).Return([]form.FormSection{}, nil)
+				querier.On("ListQuestions", mock.Anything, testFormID).Return([]form.Question{{ID: questionID, FormID: testFormID}, {ID: uuid.New(), FormID: testFormID}}, nil)
+				tx.On("InTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(form.Querier) error) error {
+					return fn(querier)
+				})
+				querier.On("UpdateDefinition", mock.Anything, mock.MatchedBy(func(arg form.UpdateDefinitionParams) bool {
+					return arg.ID == testFormID && arg.RevealAnswers == "never" && arg.ExpectedVersion == pgtype.Int4{Int32: 4, Valid: true}
+				})).Return(existing, nil)
+				querier.On("DeleteQuestionsExcept", mock.Anything, mock.MatchedBy(func(arg form.DeleteQuestionsExceptParams) bool {
+					return len(arg.KeepIds) == 2 && arg.KeepIds[0] == questionID && arg.KeepIds[1] != questionID
+				})).Return(int64(1), nil)
+				querier.On("DeleteSectionsExcept", mock.Anything, mock.Anything).Return(int64(0), nil)
+				querier.On("ImportQuestion", mock.Anything, mock.Anything).Return(int64(1), nil).Twice()
+				querier.On("CreateRevision", mock.Anything, mock.Anything).Return(form.FormRevision{Number: 5}, nil)
+			},
+		},
+		{
+			name: "Structure is read again once the form is locked",
+			doc: definition.Document{
+				ID:        testFormID.String(),
+				Title:     "Survey",
+				Questions: []definition.Question{question(questionID.String(), "short_text")},
+			},
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {
+				querier.On("Get", mock.Anything, testFormID).Return(existing, nil)
+				querier.On("ListSections", mock.Anything, testFormID).Return([]form.FormSection{}, nil)
+				querier.On("ListQuestions", mock.Anything, testFormID).Return([]form.Question{{ID: questionID, FormID: testFormID}}, nil).Once()
+				tx.On("InTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(form.Querier) error) error {
+					return fn(querier)
+				})
+				querier.On("UpdateDefinition", mock.Anything, mock.Anything).Return(existing, nil)
+				// the question was deleted before the form was locked
+				querier.On("ListQuestions", mock.Anything, testFormID).Return([]form.Question{}, nil).Once()
+				querier.On("DeleteQuestionsExcept", mock.Anything, mock.MatchedBy(func(arg form.DeleteQuestionsExceptParams) bool {
+					return len(arg.KeepIds) == 1 && arg.KeepIds[0] != questionID
+				})).Return(int64(0), nil)
+				querier.On("DeleteSectionsExcept", mock.Anything, mock.Anything).Return(int64(0), nil)
+				querier.On("ImportQuestion", mock.Anything, mock.MatchedBy(func(arg form.ImportQuestionParams) bool {
+					return arg.ID != questionID
+				})).Return(int64(1), nil)
+				querier.On("CreateRevision", mock.Anything, mock.Anything).Return(form.FormRevision{Number: 5}, nil)
+			},
+		},
+		{
+			name: "Problems of questions point to their fields",
+			doc: definition.Document{
+				Title: "Survey",
+				Quiz:  &definition.Quiz{RevealAnswers: "sometimes"},
+				Questions: []definition.Question{
+					{Key: "a", Type: "single_choice", Title: "A"},
+					{Key: "b", Type: "number", Title: "B", AnswerKey: "many"},
+					{Key: "c", Type: "essay", Title: "C"},
+				},
+			},
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {},
+			expectError: []definition.FieldError{
+				{Path: "quiz.reveal_answers", Message: `unknown reveal policy "sometimes"`},
+				{Path: "questions[0].settings", Message: "single_choice question requires choices"},
+				{Path: "questions[1].answer_key", Message: "expected a number"},
+				{Path: "questions[2].type", Message: `unknown question type "essay"`},
+			},
+		},
+		{
+			name: "Rules may only refer to earlier questions",
+			doc: definition.Document{
+				Title: "Survey",
+				Questions: []definition.Question{
+					{Key: "first", Type: "short_text", Title: "First", Rules: &definition.QuestionRules{
+						RequireIf: &definition.Condition{Question: "second", Op: "answered"},
+					}},
+					question("second", "short_text"),
+				},
+			},
+			setMock: func(querier *mocks.Querier, tx *mocks.Transactor) {},
+			expectError: []definition.FieldError{
+				{Path: "questions[0].rules.require_if", Message: `question "second" does not come before this rule`},
+			},
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			tx := mocks.NewTransactor(t)
+			tt.setMock(querier, tx)
+			service := form.NewService(logger, querier, tx)
+
+			tt.doc.SchemaVersion = definition.CurrentVersion
+			version := int32(4)
+			ctx := context.WithValue(context.Background(), jwt.UserContextKey, authorID)
+			_, created, err := service.Import(ctx, tt.doc, authorID, &version)
+			if tt.expectError == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.created, created)
+				return
+			}
+
+			var problems *definition.ValidationError
+			if assert.ErrorAs(t, err, &problems) {
+				assert.Equal(t, tt.expectError, problems.Errors)
+			}
+		})
+	}
+}
+
+func TestDocumentOf(t *testing.T) {
+	sectionID := uuid.New()
+	q1, q2 := uuid.New(), uuid.New()
+	rules, _ := json.Marshal(logic.QuestionRules{ShowIf: &logic.Condition{Question: q1, Op: logic.OpEquals, Value: json.RawMessage(`"Yes"`)}})
+	f := form.Form{ID: uuid.New(), Title: "Survey", IsQuiz: true, RevealAnswers: "after_close"}
+	sections := []form.FormSection{{ID: sectionID, Title: "More", Rules: []byte(`{}`)}}
+	questions := []form.Question{
+		{ID: q1, Type: "single_choice", Title: "Continue?", Settings: []byte(`{"choices":["Yes","No"]}`), Rules: []byte(`{}`), Points: 2, AnswerKey: []byte(`"Yes"`)},
+		{ID: q2, Type: "short_text", Title: "Why?", SectionID: pgtype.UUID{Bytes: sectionID, Valid: true}, Settings: []byte(`{}`), Rules: rules},
+	}
+
+	doc, err := form.DocumentOf(f, sections, questions)
+	assert.NoError(t, err)
+	assert.NoError(t, doc.Validate())
+
+	assert.Equal(t, f.ID.String(), doc.ID)
+	assert.Equal(t, &definition.Quiz{RevealAnswers: "after_close"}, doc.Quiz)
+	assert.Equal(t, []definition.Section{{Key: sectionID.String(), Title: "More"}}, doc.Sections)
+	assert.Equal(t, definition.Question{
+		Key:       q1.String(),
+		Type:      "single_choice",
+		Title:     "Continue?",
+		Settings:  &definition.Settings{Choices: []string{"Yes", "No"}},
+		Points:    2,
+		AnswerKey: "Yes",
+	}, doc.Questions[0])
+	assert.Equal(t, definition.Question{
+		Key:     q2.String(),
+		Section: sectionID.String(),
+		Type:    "short_text",
+		Title:   "Why?",
+		Rules:   &definition.QuestionRules{ShowIf: &definition.Condition{Question: q1.String(), Op: "equals", Value: "Yes"}},
+	}, doc.Questions[1])
+}
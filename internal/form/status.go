@@ -0,0 +1,51 @@
+package form
+
+type Status string
+
+const (
+	StatusDraft     Status = "draft"
+	StatusPublished Status = "published"
+	StatusClosed    Status = "closed"
+	StatusArchived  Status = "archived"
+)
+
+// Action moves a form from one status to another.
+type Action string
+
+const (
+	ActionPublish Action = "publish"
+	ActionClose   Action = "close"
+	ActionReopen  Action = "reopen"
+	ActionArchive Action = "archive"
+)
+
+type transitionRule struct {
+	from     []Status
+	to       Status
+	required Role
+}
+
+// transitions lists the statuses each action may start from and the role it
+// requires. Drafts belong to their owners until published, so only owners
+// may publish them. Archiving is final and only owners may do it.
+var transitions = map[Action]transitionRule{
+	ActionPublish: {from: []Status{StatusDraft}, to: StatusPublished, required: RoleOwner},
+	ActionClose:   {from: []Status{StatusPublished}, to: StatusClosed, required: RoleEditor},
+	ActionReopen:  {from: []Status{StatusClosed}, to: StatusPublished, required: RoleEditor},
+	ActionArchive: {from: []Status{StatusDraft, StatusPublished, StatusClosed}, to: StatusArchived, required: RoleOwner},
+}
+
+// IsEditable reports whether the form and its questions may still change.
+// Closed and archived forms are read-only.
+func (s Status) IsEditable() bool {
+	return s != StatusClosed && s != StatusArchived
+}
+
+func (r transitionRule) allows(from Status) bool {
+	for _, status := range r.from {
+		if status == from {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package form
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TemplateVisibility decides who finds a form in the template gallery and
+// may create forms from it. Forms with TemplateNone are not templates.
+type TemplateVisibility string
+
+const (
+	TemplateNone TemplateVisibility = ""
+	// TemplatePrivate templates are only available to owners of the form.
+	TemplatePrivate TemplateVisibility = "private"
+	// TemplateTeam templates are available to every collaborator of the form.
+	TemplateTeam TemplateVisibility = "team"
+	// TemplatePublic templates are available to every user.
+	TemplatePublic TemplateVisibility = "public"
+)
+
+func (v TemplateVisibility) IsValid() bool {
+	return v == TemplateNone || v == TemplatePrivate || v == TemplateTeam || v == TemplatePublic
+}
+
+// requiredRole is the role a user needs on the form to use it as a template.
+func (v TemplateVisibility) requiredRole() Role {
+	switch v {
+	case TemplatePublic:
+		return RoleNone
+	case TemplateTeam:
+		return RoleViewer
+	}
+	return RoleOwner
+}
+
+func (v TemplateVisibility) param() pgtype.Text {
+	return pgtype.Text{String: string(v), Valid: v != TemplateNone}
+}
+
+// CopyOptions override the title and description of a copied form. Empty
+// fields keep those of the original.
+type CopyOptions struct {
+	Title       string
+	Description string
+}
+
+// idMap assigns new IDs to the sections and questions of a copied form.
+type idMap map[uuid.UUID]uuid.UUID
+
+func newIDMap(sections []FormSection, questions []Question) idMap {
+	ids := make(idMap, len(sections)+len(questions))
+	for _, section := range sections {
+		ids[section.ID] = uuid.New()
+	}
+	for _, question := range questions {
+		ids[question.ID] = uuid.New()
+	}
+	return ids
+}
+
+// rewrite replaces the old IDs in the JSON rules of a section or question
+// with the new ones. Rules only refer to sections and questions by their ID
+// strings, and IDs are random, so a plain text replacement cannot hit
+// anything else.
+func (ids idMap) rewrite(rules []byte) string {
+	pairs := make([]string, 0, 2*len(ids))
+	for from, to := range ids {
+		pairs = append(pairs, from.String(), to.String())
+	}
+	return strings.NewReplacer(pairs...).Replace(string(rules))
+}
+
+func (ids idMap) copySectionsParams(formID, sourceID uuid.UUID, sections []FormSection) CopySectionsParams {
+	params := CopySectionsParams{
+		FormID:   formID,
+		SourceID: sourceID,
+		OldIds:   make([]uuid.UUID, 0, len(sections)),
+		NewIds:   make([]uuid.UUID, 0, len(sections)),
+		Rules:    make([]string, 0, len(sections)),
+	}
+	for _, section := range sections {
+		params.OldIds = append(params.OldIds, section.ID)
+		params.NewIds = append(params.NewIds, ids[section.ID])
+		params.Rules = append(params.Rules, ids.rewrite(section.Rules))
+	}
+	return params
+}
+
+func (ids idMap) copyQuestionsParams(formID, sourceID uuid.UUID, sections []FormSection, questions []Question, answerKeys bool) CopyQuestionsParams {
+	params := CopyQuestionsParams{
+		FormID:         formID,
+		WithAnswerKeys: answerKeys,
+		SourceID:       sourceID,
+		OldIds:         make([]uuid.UUID, 0, len(questions)),
+		NewIds:         make([]uuid.UUID, 0, len(questions)),
+		Rules:          make([]string, 0, len(questions)),
+		OldSectionIds:  make([]uuid.UUID, 0, len(sections)),
+		NewSectionIds:  make([]uuid.UUID, 0, len(sections)),
+	}
+	for _, question := range questions {
+		params.OldIds = append(params.OldIds, question.ID)
+		params.NewIds = append(params.NewIds, ids[question.ID])
+		params.Rules = append(params.Rules, ids.rewrite(question.Rules))
+	}
+	for _, section := range sections {
+		params.OldSectionIds = append(params.OldSectionIds, section.ID)
+		params.NewSectionIds = append(params.NewSectionIds, ids[section.ID])
+	}
+	return params
+}
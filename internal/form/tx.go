@@ -0,0 +1,35 @@
+package form
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Transactor runs fn with queries bound to a single database transaction.
+// The transaction is committed when fn returns nil and rolled back
+// otherwise.
+//
+//go:generate mockery --name=Transactor
+type Transactor interface {
+	InTx(ctx context.Context, fn func(Querier) error) error
+}
+
+// TxBeginner starts transactions. *pgxpool.Pool and *pgx.Conn implement it.
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type transactor struct {
+	db TxBeginner
+}
+
+func NewTransactor(db TxBeginner) Transactor {
+	return transactor{db: db}
+}
+
+func (t transactor) InTx(ctx context.Context, fn func(Querier) error) error {
+	return pgx.BeginFunc(ctx, t.db, func(tx pgx.Tx) error {
+		return fn(New(tx))
+	})
+}
@@ -0,0 +1,147 @@
+package formsync
+
+import (
+	"awesomeProject/internal/form"
+	"awesomeProject/internal/form/definition"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrNotFound = errors.New("form not found on the server")
+	// ErrStale is returned by Import when the form changed on the server
+	// since the ETag passed to it was read.
+	ErrStale = errors.New("form changed on the server since it was read")
+)
+
+// Server is the part of the form API formsync needs.
+//
+//go:generate mockery --name=Server
+type Server interface {
+	Definition(ctx context.Context, formID string) (definition.Document, string, error)
+	Import(ctx context.Context, doc definition.Document, etag string) (string, error)
+	Delete(ctx context.Context, formID string) error
+}
+
+// Client calls the form API of a running server with a JWT access token.
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewClient(baseURL, token string, httpClient *http.Client) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		http:    httpClient,
+	}
+}
+
+// Definition returns the definition of the form and its ETag, or
+// ErrNotFound when the form does not exist or is in the trash.
+func (c *Client) Definition(ctx context.Context, formID string) (definition.Document, string, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/api/forms/"+url.PathEscape(formID)+"/definition?format=json", nil, "")
+	if err != nil {
+		return definition.Document{}, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return definition.Document{}, "", err
+	}
+	file, err := definition.Parse(body)
+	if err != nil {
+		return definition.Document{}, "", fmt.Errorf("definition of form %s: %w", formID, err)
+	}
+	return file.Document, resp.Header.Get("ETag"), nil
+}
+
+// Import creates or updates a form from doc and returns its ID. A non-empty
+// etag is sent as If-Match, so the update fails with ErrStale when the form
+// changed since the tag was read.
+func (c *Client) Import(ctx context.Context, doc definition.Document, etag string) (string, error) {
+	body, err := definition.Encode(doc, definition.JSON)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.do(ctx, http.MethodPost, "/api/forms/import", body, etag)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return "", fmt.Errorf("decode import response: %w", err)
+	}
+	return result.ID, nil
+}
+
+// Delete moves the form to the trash of the server.
+func (c *Client) Delete(ctx context.Context, formID string) error {
+	body, err := json.Marshal(map[string]string{"id": formID})
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(ctx, http.MethodDelete, "/api/forms", body, "")
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// do sends a request and turns error responses into errors. Rejected
+// definitions come back as a *definition.ValidationError. A non-empty etag is
+// sent as If-Match.
+func (c *Client) do(ctx context.Context, method, path string, body []byte, etag string) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	case http.StatusPreconditionFailed:
+		return nil, ErrStale
+	case http.StatusBadRequest:
+		var problems form.DefinitionErrorResponse
+		if json.Unmarshal(message, &problems) == nil && len(problems.Errors) > 0 {
+			return nil, &definition.ValidationError{Errors: problems.Errors}
+		}
+	}
+	return nil, fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(message)))
+}
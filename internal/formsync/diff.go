@@ -0,0 +1,103 @@
+package formsync
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// Diff compares two texts line by line and returns the changes in the
+// style of a unified diff: removed lines start with "-", added lines with
+// "+" and unchanged lines around them with a space. Runs of unchanged lines
+// further away from any change are left out. It returns "" when the texts
+// are equal.
+//
+// Definition documents are small, so the longest common subsequence is
+// computed directly instead of with Myers' algorithm.
+func Diff(from, to string) string {
+	a := splitLines(from)
+	b := splitLines(to)
+
+	// common[i][j] is the length of the longest common subsequence of
+	// a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var lines []string
+	changed := false
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, " "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || common[i+1][j] >= common[i][j+1]):
+			lines = append(lines, "-"+a[i])
+			changed = true
+			i++
+		default:
+			lines = append(lines, "+"+b[j])
+			changed = true
+			j++
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	return strings.Join(trimContext(lines), "\n") + "\n"
+}
+
+// trimContext replaces unchanged lines that are more than diffContext lines
+// away from a change with a line telling how many were skipped.
+func trimContext(lines []string) []string {
+	keep := make([]bool, len(lines))
+	for i, line := range lines {
+		if line[0] == ' ' {
+			continue
+		}
+		for k := max(0, i-diffContext); k <= min(len(lines)-1, i+diffContext); k++ {
+			keep[k] = true
+		}
+	}
+
+	var result []string
+	skipped := 0
+	for i, line := range lines {
+		if keep[i] {
+			if skipped > 0 {
+				result = append(result, fmt.Sprintf("@@ %d unchanged lines @@", skipped))
+				skipped = 0
+			}
+			result = append(result, line)
+			continue
+		}
+		skipped++
+	}
+	if skipped > 0 {
+		result = append(result, fmt.Sprintf("@@ %d unchanged lines @@", skipped))
+	}
+	return result
+}
+
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
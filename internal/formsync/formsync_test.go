@@ -0,0 +1,177 @@
+package formsync_test
+
+import (
+	"awesomeProject/internal/form/definition"
+	"awesomeProject/internal/formsync"
+	"awesomeProject/internal/formsync/mocks"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		expected string
+	}{
+		{
+			name:     "Equal texts",
+			from:     "a\nb\n",
+			to:       "a\nb\n",
+			expected: "",
+		},
+		{
+			name:     "Changed line",
+			from:     "title: Old\nquestions: []\n",
+			to:       "title: New\nquestions: []\n",
+			expected: "-title: Old\n+title: New\n questions: []\n",
+		},
+		{
+			name:     "Far away lines are skipped",
+			from:     "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			to:       "1\n2\n3\n4\n5\n6\n7\n8\nnine\n",
+			expected: "@@ 5 unchanged lines @@\n 6\n 7\n 8\n-9\n+nine\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formsync.Diff(tt.from, tt.to))
+		})
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+}
+
+func TestPlan(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "feedback.yaml", `schema_version: 2
+title: Feedback
+questions:
+  - key: rating
+    type: number
+    title: Rating
+`)
+	writeFile(t, dir, "new.yaml", `schema_version: 2
+title: New form
+questions: []
+`)
+	writeFile(t, dir, "notes.txt", "not a form")
+	state := formsync.State{Forms: map[string]formsync.FormState{
+		"feedback.yaml": {ID: "form-1", Keys: map[string]string{"rating": "question-1"}},
+		"retired.yaml":  {ID: "form-2"},
+	}}
+	require.NoError(t, state.Save(dir))
+
+	server := mocks.NewServer(t)
+	ctx := context.Background()
+	// someone renamed the question on the server
+	server.On("Definition", ctx, "form-1").Return(definition.Document{
+		SchemaVersion: definition.CurrentVersion,
+		ID:            "form-1",
+		Title:         "Feedback",
+		Questions:     []definition.Question{{Key: "question-1", Type: "number", Title: "Score"}},
+	}, `"v7"`, nil).Once()
+
+	forms, err := formsync.LoadDir(dir)
+	require.NoError(t, err)
+	loaded, err := formsync.LoadState(dir)
+	require.NoError(t, err)
+	plan, err := formsync.MakePlan(ctx, server, forms, loaded)
+	require.NoError(t, err)
+
+	var out strings.Builder
+	require.NoError(t, plan.Write(&out))
+	assert.Equal(t, `~ update feedback.yaml (form form-1)
+    @@ 2 unchanged lines @@
+     questions:
+       - key: rating
+         type: number
+    -    title: Score
+    +    title: Rating
++ create new.yaml
+- delete retired.yaml (form form-2)
+
+Plan: 1 to create, 1 to update, 1 to delete.
+`, out.String())
+
+	// the update keeps the question by its ID
+	server.On("Import", ctx, mock.MatchedBy(func(doc definition.Document) bool {
+		return doc.ID == "form-1" && doc.Questions[0].Key == "question-1" && doc.Questions[0].Title == "Rating"
+	}), `"v7"`).Return("form-1", nil).Once()
+	server.On("Definition", ctx, "form-1").Return(definition.Document{
+		Questions: []definition.Question{{Key: "question-1"}},
+	}, `"v8"`, nil).Once()
+	server.On("Import", ctx, mock.MatchedBy(func(doc definition.Document) bool {
+		return doc.ID == "" && doc.Title == "New form"
+	}), "").Return("form-3", nil).Once()
+	server.On("Definition", ctx, "form-3").Return(definition.Document{}, `"v1"`, nil).Once()
+	server.On("Delete", ctx, "form-2").Return(formsync.ErrNotFound).Once()
+
+	var applied []string
+	err = plan.Apply(ctx, server, dir, func(change formsync.Change) {
+		applied = append(applied, string(change.Action)+" "+change.FormID)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"update form-1", "create form-3", "delete form-2"}, applied)
+
+	saved, err := formsync.LoadState(dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]formsync.FormState{
+		"feedback.yaml": {ID: "form-1", Keys: map[string]string{"rating": "question-1"}},
+		"new.yaml":      {ID: "form-3"},
+	}, saved.Forms)
+}
+
+func TestPlan_Stale(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "feedback.yaml", "schema_version: 2\ntitle: Feedback\nquestions: []\n")
+	state := formsync.State{Forms: map[string]formsync.FormState{
+		"feedback.yaml": {ID: "form-1"},
+	}}
+	require.NoError(t, state.Save(dir))
+
+	server := mocks.NewServer(t)
+	ctx := context.Background()
+	server.On("Definition", ctx, "form-1").Return(definition.Document{
+		SchemaVersion: definition.CurrentVersion,
+		ID:            "form-1",
+		Title:         "Old feedback",
+	}, `"v7"`, nil).Once()
+
+	forms, err := formsync.LoadDir(dir)
+	require.NoError(t, err)
+	plan, err := formsync.MakePlan(ctx, server, forms, state)
+	require.NoError(t, err)
+
+	// the form changed on the server after the plan was made
+	server.On("Import", ctx, mock.Anything, `"v7"`).Return("", formsync.ErrStale).Once()
+
+	err = plan.Apply(ctx, server, dir, nil)
+	require.ErrorIs(t, err, formsync.ErrStale)
+	assert.Contains(t, err.Error(), "plan is stale, re-run plan")
+}
+
+func TestLoadDir_ReportsEveryProblem(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.yaml", "schema_version: 2\ntitle: A\nquestions:\n  - key: q\n    type: number\n")
+	writeFile(t, dir, "b.json", `{"schema_version": 2, "questions": []}`)
+
+	_, err := formsync.LoadDir(dir)
+	var problems *formsync.FileError
+	require.ErrorAs(t, err, &problems)
+	assert.Equal(t, []string{
+		"a.yaml:4: questions[0].title: is required",
+		"b.json:1: title: is required",
+	}, problems.Problems)
+}
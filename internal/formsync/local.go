@@ -0,0 +1,167 @@
+package formsync
+
+import (
+	"awesomeProject/internal/form/definition"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LocalForm is a parsed definition file.
+type LocalForm struct {
+	// Path is relative to the directory, with forward slashes.
+	Path     string
+	Document definition.Document
+}
+
+// FileError lists the problems of the definition files of a directory,
+// each as "file:line: path: message".
+type FileError struct {
+	Problems []string
+}
+
+func (e *FileError) Error() string {
+	return "invalid definition files:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// LoadDir parses every .yaml, .yml and .json file below dir, except the
+// state file and hidden files. All problems of all files are reported
+// together as a *FileError.
+func LoadDir(dir string) ([]LocalForm, error) {
+	var forms []LocalForm
+	var problems FileError
+	err := filepath.WalkDir(dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(entry.Name(), ".") && name != dir {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || entry.Name() == StateFile {
+			return nil
+		}
+		switch path.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		file, err := definition.Parse(data)
+		var invalid *definition.ValidationError
+		if errors.As(err, &invalid) {
+			for _, field := range invalid.Errors {
+				problems.Problems = append(problems.Problems, fmt.Sprintf("%s:%d: %s", rel, field.Line, field))
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		forms = append(forms, LocalForm{Path: rel, Document: file.Document})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(problems.Problems) > 0 {
+		return nil, &problems
+	}
+
+	sort.Slice(forms, func(i, j int) bool { return forms[i].Path < forms[j].Path })
+	return forms, nil
+}
+
+// rekey returns a deep enough copy of doc with every key of a section or
+// question, and every reference to one, replaced through keys. Keys
+// missing from keys are kept.
+func rekey(doc definition.Document, keys map[string]string) definition.Document {
+	mapKey := func(key string) string {
+		if mapped, ok := keys[key]; ok {
+			return mapped
+		}
+		return key
+	}
+
+	result := doc
+	result.Sections = make([]definition.Section, len(doc.Sections))
+	for i, section := range doc.Sections {
+		section.Key = mapKey(section.Key)
+		if section.Rules != nil {
+			rules := definition.SectionRules{ShowIf: rekeyCondition(section.Rules.ShowIf, mapKey)}
+			for _, jump := range section.Rules.Jumps {
+				if jump.To != definition.End {
+					jump.To = mapKey(jump.To)
+				}
+				jump.If = rekeyCondition(jump.If, mapKey)
+				rules.Jumps = append(rules.Jumps, jump)
+			}
+			section.Rules = &rules
+		}
+		result.Sections[i] = section
+	}
+	result.Questions = make([]definition.Question, len(doc.Questions))
+	for i, question := range doc.Questions {
+		question.Key = mapKey(question.Key)
+		if question.Section != "" {
+			question.Section = mapKey(question.Section)
+		}
+		if question.Rules != nil {
+			question.Rules = &definition.QuestionRules{
+				ShowIf:    rekeyCondition(question.Rules.ShowIf, mapKey),
+				HideIf:    rekeyCondition(question.Rules.HideIf, mapKey),
+				RequireIf: rekeyCondition(question.Rules.RequireIf, mapKey),
+			}
+		}
+		result.Questions[i] = question
+	}
+	if len(doc.Sections) == 0 {
+		result.Sections = nil
+	}
+	return result
+}
+
+func rekeyCondition(c *definition.Condition, mapKey func(string) string) *definition.Condition {
+	if c == nil {
+		return nil
+	}
+	result := *c
+	if result.Question != "" {
+		result.Question = mapKey(result.Question)
+	}
+	result.Not = rekeyCondition(c.Not, mapKey)
+	result.All = nil
+	for i := range c.All {
+		result.All = append(result.All, *rekeyCondition(&c.All[i], mapKey))
+	}
+	result.Any = nil
+	for i := range c.Any {
+		result.Any = append(result.Any, *rekeyCondition(&c.Any[i], mapKey))
+	}
+	return &result
+}
+
+// invert swaps the keys and values of keys.
+func invert(keys map[string]string) map[string]string {
+	result := make(map[string]string, len(keys))
+	for key, id := range keys {
+		result[id] = key
+	}
+	return result
+}
@@ -0,0 +1,245 @@
+package formsync
+
+import (
+	"awesomeProject/internal/form/definition"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type Action string
+
+const (
+	ActionNone   Action = "none"
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Change is what applying the plan does for one definition file.
+type Change struct {
+	Action Action
+	Path   string
+	// FormID is empty for forms that are yet to be created.
+	FormID string
+	// Diff shows how the form on the server ("-") differs from the file
+	// ("+"), for updates.
+	Diff string
+	// Reason explains changes that are not obvious from the files alone.
+	Reason string
+
+	// local is the document of the file, doc the one to import, with the
+	// keys the server knows replaced by their IDs. etag is the version of
+	// the form the plan was made against, for updates.
+	local definition.Document
+	doc   definition.Document
+	etag  string
+}
+
+// Plan is the list of changes that bring the server in line with the
+// files, ordered by path with deletes last.
+type Plan struct {
+	Changes []Change
+	state   State
+}
+
+// MakePlan compares the files with the forms on the server. Files the state
+// does not know create forms, unless they name a form by its ID. Forms the
+// state knows but no file defines any more are deleted. Forms that were
+// deleted on the server behind the back of the state are created again.
+func MakePlan(ctx context.Context, server Server, forms []LocalForm, state State) (*Plan, error) {
+	plan := &Plan{state: state}
+	seen := make(map[string]bool, len(forms))
+
+	for _, local := range forms {
+		seen[local.Path] = true
+		known := state.Forms[local.Path]
+		formID := known.ID
+		if local.Document.ID != "" {
+			if known.ID != "" && known.ID != local.Document.ID {
+				return nil, fmt.Errorf("%s: id %s does not match the form %s in %s", local.Path, local.Document.ID, known.ID, StateFile)
+			}
+			formID = local.Document.ID
+		}
+
+		change := Change{Path: local.Path, FormID: formID, local: local.Document, doc: rekey(local.Document, known.Keys)}
+		if formID == "" {
+			change.Action = ActionCreate
+			plan.Changes = append(plan.Changes, change)
+			continue
+		}
+
+		remote, etag, err := server.Definition(ctx, formID)
+		if errors.Is(err, ErrNotFound) {
+			if local.Document.ID != "" {
+				return nil, fmt.Errorf("%s: form %s: %w", local.Path, formID, err)
+			}
+			change.Action = ActionCreate
+			change.Reason = fmt.Sprintf("form %s no longer exists on the server", formID)
+			change.FormID = ""
+			change.doc.ID = ""
+			plan.Changes = append(plan.Changes, change)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", local.Path, err)
+		}
+
+		change.doc.ID = formID
+		change.etag = etag
+		change.Diff, err = drift(rekey(remote, invert(known.Keys)), local.Document)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", local.Path, err)
+		}
+		change.Action = ActionUpdate
+		if change.Diff == "" {
+			change.Action = ActionNone
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	var gone []string
+	for path := range state.Forms {
+		if !seen[path] {
+			gone = append(gone, path)
+		}
+	}
+	sort.Strings(gone)
+	for _, path := range gone {
+		plan.Changes = append(plan.Changes, Change{Action: ActionDelete, Path: path, FormID: state.Forms[path].ID})
+	}
+
+	return plan, nil
+}
+
+// drift renders both documents as YAML without their IDs and compares them.
+func drift(remote, local definition.Document) (string, error) {
+	remote.ID, local.ID = "", ""
+	from, err := definition.Encode(remote, definition.YAML)
+	if err != nil {
+		return "", err
+	}
+	to, err := definition.Encode(local, definition.YAML)
+	if err != nil {
+		return "", err
+	}
+	return Diff(string(from), string(to)), nil
+}
+
+// Count returns the number of changes with the action.
+func (p *Plan) Count(action Action) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+func (p *Plan) HasChanges() bool {
+	return p.Count(ActionNone) < len(p.Changes)
+}
+
+// Write prints the plan for people.
+func (p *Plan) Write(w io.Writer) error {
+	if !p.HasChanges() {
+		_, err := fmt.Fprintln(w, "No changes. The forms on the server match the files.")
+		return err
+	}
+
+	var b strings.Builder
+	for _, change := range p.Changes {
+		switch change.Action {
+		case ActionCreate:
+			fmt.Fprintf(&b, "+ create %s", change.Path)
+		case ActionUpdate:
+			fmt.Fprintf(&b, "~ update %s (form %s)", change.Path, change.FormID)
+		case ActionDelete:
+			fmt.Fprintf(&b, "- delete %s (form %s)", change.Path, change.FormID)
+		default:
+			continue
+		}
+		if change.Reason != "" {
+			fmt.Fprintf(&b, ": %s", change.Reason)
+		}
+		b.WriteString("\n")
+		for _, line := range splitLines(change.Diff) {
+			b.WriteString("    " + line + "\n")
+		}
+	}
+	fmt.Fprintf(&b, "\nPlan: %d to create, %d to update, %d to delete.\n", p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionDelete))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Apply makes the changes of the plan on the server and records them in
+// the state file of dir after every change, so a failed apply can simply
+// be planned and applied again. Updates only go through if the form is
+// still the one the plan was made against. done is called after every
+// change that reached the server.
+func (p *Plan) Apply(ctx context.Context, server Server, dir string, done func(Change)) error {
+	for _, change := range p.Changes {
+		switch change.Action {
+		case ActionNone:
+			if _, ok := p.state.Forms[change.Path]; ok {
+				continue
+			}
+			// a file that names an existing form by its ID
+			p.state.Forms[change.Path] = FormState{ID: change.FormID}
+
+		case ActionCreate, ActionUpdate:
+			formID, err := server.Import(ctx, change.doc, change.etag)
+			if errors.Is(err, ErrStale) {
+				return fmt.Errorf("%s: plan is stale, re-run plan: %w", change.Path, err)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", change.Path, err)
+			}
+			remote, _, err := server.Definition(ctx, formID)
+			if err != nil {
+				return fmt.Errorf("%s: %w", change.Path, err)
+			}
+			change.FormID = formID
+			p.state.Forms[change.Path] = FormState{ID: formID, Keys: keysOf(change.local, remote)}
+
+		case ActionDelete:
+			err := server.Delete(ctx, change.FormID)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return fmt.Errorf("%s: %w", change.Path, err)
+			}
+			delete(p.state.Forms, change.Path)
+		}
+
+		err := p.state.Save(dir)
+		if err != nil {
+			return err
+		}
+		if change.Action != ActionNone && done != nil {
+			done(change)
+		}
+	}
+	return nil
+}
+
+// keysOf maps the keys of an imported document to the IDs the server gave
+// its sections and questions. The server keeps the order of the document,
+// so they pair up by position.
+func keysOf(imported, remote definition.Document) map[string]string {
+	keys := make(map[string]string)
+	for i, section := range imported.Sections {
+		if i < len(remote.Sections) && section.Key != remote.Sections[i].Key {
+			keys[section.Key] = remote.Sections[i].Key
+		}
+	}
+	for i, question := range imported.Questions {
+		if i < len(remote.Questions) && question.Key != remote.Questions[i].Key {
+			keys[question.Key] = remote.Questions[i].Key
+		}
+	}
+	return keys
+}
@@ -0,0 +1,64 @@
+// Package formsync keeps the forms on a server in sync with a directory of
+// form definition files, see the definition package for their format. It
+// backs the formctl command.
+//
+// Definition files name sections and questions by keys of their own
+// choosing, while the server knows them by ID. The state file of the
+// directory remembers which form each file created and the IDs of its keys,
+// so later changes update those forms, sections and questions in place
+// instead of creating new ones. The state file should be committed together
+// with the definition files.
+package formsync
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// StateFile is the name of the state file in the directory of the
+// definition files.
+const StateFile = "formctl.state.json"
+
+type State struct {
+	// Forms is keyed by the path of the definition file relative to the
+	// directory, with forward slashes.
+	Forms map[string]FormState `json:"forms"`
+}
+
+type FormState struct {
+	ID string `json:"id"`
+	// Keys maps the keys of sections and questions in the file to their IDs.
+	Keys map[string]string `json:"keys,omitempty"`
+}
+
+// LoadState reads the state file of dir. A missing file is an empty state.
+func LoadState(dir string) (State, error) {
+	state := State{Forms: make(map[string]FormState)}
+	data, err := os.ReadFile(filepath.Join(dir, StateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return State{}, err
+	}
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		return State{}, err
+	}
+	if state.Forms == nil {
+		state.Forms = make(map[string]FormState)
+	}
+	return state, nil
+}
+
+// Save writes the state file of dir.
+func (s State) Save(dir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, StateFile), append(data, '\n'), 0o644)
+}
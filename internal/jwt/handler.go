@@ -4,9 +4,12 @@ import (
 	"awesomeProject/internal/user"
 	// "awesomeProject/internal/auth" // [REMOVED]
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net/http"
-	//"time" // [ADDED]
+	"time"
 
 	"github.com/go-playground/validator/v10" // [ADDED]
 	"github.com/google/uuid"
@@ -16,12 +19,20 @@ import (
 
 type jwtService interface {
 	// 接口來自 service.go
-	Create(ctx context.Context, userID uuid.UUID, time pgtype.Timestamptz) (Jwt, error)
+	Create(ctx context.Context, userID uuid.UUID, time pgtype.Timestamptz) (RefreshToken, error)
 	Update(ctx context.Context, id uuid.UUID, isAvailable bool) (Jwt, error)
 	// 'IsAvailable' 實現了輪換邏輯
-	IsAvailable(ctx context.Context, id uuid.UUID) (Jwt, error)
+	IsAvailable(ctx context.Context, token string) (RefreshToken, error)
 	// 'New' 用於創建新的 Access Token
-	New(ctx context.Context, id uuid.UUID, email string) (string, error)
+	New(ctx context.Context, id uuid.UUID, email string, familyID uuid.UUID) (string, error)
+	JWKS() JWKS
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeRefreshTokens(ctx context.Context, userID uuid.UUID) error
+}
+
+type revocationList interface {
+	RevokeToken(ctx context.Context, token Token) error
+	RevokeUser(ctx context.Context, userID uuid.UUID, before time.Time) error
 }
 
 type userService interface {
@@ -35,21 +46,23 @@ type Handler struct {
 	validator   *validator.Validate // [ADDED]
 	jwtService  jwtService
 	userService userService
+	revocations revocationList
 }
 
 // [MODIFIED] 注入 validator
-func NewHandler(logger *zap.Logger, validator *validator.Validate, jwtService jwtService, userService userService) *Handler {
+func NewHandler(logger *zap.Logger, validator *validator.Validate, jwtService jwtService, userService userService, revocations revocationList) *Handler {
 	return &Handler{
 		logger:      logger,
 		validator:   validator,
 		jwtService:  jwtService,
 		userService: userService,
+		revocations: revocations,
 	}
 }
 
 // [ADDED] Request 和 Response 結構
 type RefreshRequest struct {
-	RefreshToken string `json:"refresh_token" validate:"required,uuid"`
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
 type RefreshResponse struct {
@@ -75,17 +88,9 @@ func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 2. 解析 UUID
-	tokenID, err := uuid.Parse(req.RefreshToken)
-	if err != nil {
-		h.logger.Error("Failed to parse UUID from refresh token", zap.Error(err))
-		http.Error(w, "Invalid refresh token format", http.StatusBadRequest)
-		return
-	}
-
-	// 3. 調用服務以輪換 token
+	// 2. 調用服務以輪換 token
 	// 'IsAvailable' 會驗證舊 token、使其失效，並創建一個新 token
-	newRefreshToken, err := h.jwtService.IsAvailable(ctx, tokenID)
+	newRefreshToken, err := h.jwtService.IsAvailable(ctx, req.RefreshToken)
 	if err != nil {
 		h.logger.Warn("Refresh token rotation failed", zap.Error(err))
 		// 錯誤可能是 "already used", "expired", 或 "not found"
@@ -93,7 +98,7 @@ func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 4. 創建新的 Access Token
+	// 3. 創建新的 Access Token
 	// newRefreshToken 包含 UserEmail
 	User, err := h.userService.GetByID(ctx, newRefreshToken.UserID)
 	if err != nil {
@@ -102,17 +107,17 @@ func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newAccessToken, err := h.jwtService.New(ctx, newRefreshToken.UserID, User.Email)
+	newAccessToken, err := h.jwtService.New(ctx, newRefreshToken.UserID, User.Email, newRefreshToken.FamilyID)
 	if err != nil {
 		h.logger.Error("Failed to create new access token after refresh", zap.Error(err))
 		http.Error(w, "Failed to create new access token", http.StatusInternalServerError)
 		return
 	}
 
-	// 5. 發送包含新 tokens 的響應
+	// 4. 發送包含新 tokens 的響應
 	resp := RefreshResponse{
 		AccessToken:  newAccessToken,
-		RefreshToken: newRefreshToken.ID.String(), // 發送新 Refresh Token 的 ID
+		RefreshToken: newRefreshToken.Token, // only the hash of the token is stored
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -122,3 +127,83 @@ func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
+
+// Logout ends the current session: the access token of the request is
+// revoked right away, as is the refresh token family it was issued for.
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	token, ok := ctx.Value(TokenContextKey).(Token)
+	if !ok {
+		h.logger.Warn("Logout without access token")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.revocations.RevokeToken(ctx, token)
+	if err == nil {
+		err = h.jwtService.RevokeFamily(ctx, token.FamilyID)
+	}
+	if err != nil {
+		http.Error(w, "Failed to log out", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LogoutAll ends every session of the user: all access tokens issued so far
+// are revoked and no refresh token can be used anymore.
+func (h *Handler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	token, ok := ctx.Value(TokenContextKey).(Token)
+	if !ok {
+		h.logger.Warn("Logout without access token")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.revocations.RevokeUser(ctx, token.UserID, time.Now())
+	if err == nil {
+		err = h.jwtService.RevokeRefreshTokens(ctx, token.UserID)
+	}
+	if err != nil {
+		http.Error(w, "Failed to log out", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// jwksMaxAge is how long clients may cache the JWKS. A new key has to be
+// published for longer than this before it signs tokens.
+const jwksMaxAge = 5 * 60
+
+// JWKS publishes the public keys of the signing keys, so other services can
+// verify access tokens without sharing a secret. The response is cacheable
+// and carries an ETag for cheap revalidation.
+func (h *Handler) JWKS(w http.ResponseWriter, r *http.Request) {
+	body, err := json.Marshal(h.jwtService.JWKS())
+	if err != nil {
+		h.logger.Error("Failed to encode JWKS", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", jwksMaxAge))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(body)
+	if err != nil {
+		h.logger.Error("Failed to write response", zap.Error(err))
+	}
+}
@@ -0,0 +1,309 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoSigningKey = errors.New("no active signing key")
+	ErrUnknownKey   = errors.New("unknown or retired signing key")
+	ErrLastKey      = errors.New("cannot retire the last signing key")
+	ErrInvalidKey   = errors.New("invalid signing key")
+)
+
+// Algorithms of signing keys. HS256 keys are shared secrets; tokens signed
+// with RS256 and EdDSA keys can be verified by anyone with the public keys
+// published as JWKS.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// Algorithms lists the supported algorithms of signing keys.
+var Algorithms = []string{AlgHS256, AlgRS256, AlgEdDSA}
+
+const (
+	// secretSize is the size of generated HMAC secrets, as long as the
+	// output of SHA-256.
+	secretSize = 32
+	rsaKeyBits = 2048
+)
+
+// Key is one signing key. Tokens name the key they were signed with in
+// their kid header.
+type Key struct {
+	ID        string `json:"kid"`
+	Algorithm string `json:"alg"`
+	// Secret is the secret of HS256 keys.
+	Secret []byte `json:"secret,omitempty"`
+	// PrivateKey is the PKCS #8 encoded private key of RS256 and EdDSA keys.
+	PrivateKey []byte    `json:"private_key,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	// ActiveAt is when the key starts signing tokens. Until then it is only
+	// accepted and published, which gives every server and every verifier
+	// time to load it first.
+	ActiveAt  time.Time  `json:"active_at"`
+	RetiredAt *time.Time `json:"retired_at,omitempty"`
+
+	// signer is the parsed PrivateKey.
+	signer crypto.Signer
+}
+
+// parse checks that the key material fits the algorithm and decodes the
+// private key.
+func (k *Key) parse() error {
+	if k.ID == "" {
+		return fmt.Errorf("%w: missing kid", ErrInvalidKey)
+	}
+
+	switch k.Algorithm {
+	case AlgHS256:
+		if len(k.Secret) < secretSize {
+			return fmt.Errorf("%w: %s: HS256 secret must have at least %d bytes", ErrInvalidKey, k.ID, secretSize)
+		}
+		return nil
+	case AlgRS256, AlgEdDSA:
+		private, err := x509.ParsePKCS8PrivateKey(k.PrivateKey)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidKey, k.ID, err)
+		}
+		switch private := private.(type) {
+		case *rsa.PrivateKey:
+			if k.Algorithm == AlgRS256 {
+				k.signer = private
+				return nil
+			}
+		case ed25519.PrivateKey:
+			if k.Algorithm == AlgEdDSA {
+				k.signer = private
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %s: private key does not match alg %s", ErrInvalidKey, k.ID, k.Algorithm)
+	}
+	return fmt.Errorf("%w: %s: unsupported alg %q", ErrInvalidKey, k.ID, k.Algorithm)
+}
+
+func (k Key) method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+func (k Key) signingKey() any {
+	if k.Algorithm == AlgHS256 {
+		return k.Secret
+	}
+	return k.signer
+}
+
+func (k Key) verifyingKey() any {
+	if k.Algorithm == AlgHS256 {
+		return k.Secret
+	}
+	return k.signer.Public()
+}
+
+// KeySet is the content of the key file. Keys are kept in the order they
+// were generated; retired keys stay in the file for the record.
+type KeySet struct {
+	Keys []Key `json:"keys"`
+}
+
+// LoadKeySet reads the key file at path.
+func LoadKeySet(path string) (KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return KeySet{}, err
+	}
+
+	var set KeySet
+	err = json.Unmarshal(data, &set)
+	if err != nil {
+		return KeySet{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for i := range set.Keys {
+		err = set.Keys[i].parse()
+		if err != nil {
+			return KeySet{}, fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+
+	return set, nil
+}
+
+// Save writes the key set to path, readable by its owner only. The file is
+// replaced in one step, so servers reloading it never see half of it.
+func (s KeySet) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".jwt-keys-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(append(data, '\n'))
+	if err == nil {
+		err = tmp.Chmod(0o600)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Generate adds a new key for the algorithm that starts signing at
+// activeAt.
+func (s *KeySet) Generate(algorithm string, now, activeAt time.Time) (Key, error) {
+	suffix := make([]byte, 4)
+	_, err := rand.Read(suffix)
+	if err != nil {
+		return Key{}, err
+	}
+	key := Key{
+		ID:        now.UTC().Format("20060102") + "-" + hex.EncodeToString(suffix),
+		Algorithm: algorithm,
+		CreatedAt: now,
+		ActiveAt:  activeAt,
+	}
+
+	var private any
+	switch algorithm {
+	case AlgHS256:
+		key.Secret = make([]byte, secretSize)
+		_, err = rand.Read(key.Secret)
+	case AlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return Key{}, fmt.Errorf("%w: unsupported alg %q", ErrInvalidKey, algorithm)
+	}
+	if err != nil {
+		return Key{}, err
+	}
+	if private != nil {
+		key.PrivateKey, err = x509.MarshalPKCS8PrivateKey(private)
+		if err != nil {
+			return Key{}, err
+		}
+	}
+
+	err = key.parse()
+	if err != nil {
+		return Key{}, err
+	}
+	s.Keys = append(s.Keys, key)
+	return key, nil
+}
+
+// Retire stops accepting tokens signed with the key. Some other key has to
+// be able to sign right away, so the key that signs now can only be retired
+// once a newer key is active.
+func (s *KeySet) Retire(kid string, now time.Time) error {
+	i := slices.IndexFunc(s.Keys, func(key Key) bool { return key.ID == kid && key.RetiredAt == nil })
+	if i < 0 {
+		return ErrUnknownKey
+	}
+
+	rest := KeySet{Keys: slices.Clone(s.Keys)}
+	rest.Keys[i].RetiredAt = &now
+	if _, err := rest.Signing(now); err != nil {
+		return ErrLastKey
+	}
+
+	s.Keys[i].RetiredAt = &now
+	return nil
+}
+
+// Signing returns the key new tokens are signed with: the newest key that
+// is active and not retired.
+func (s KeySet) Signing(now time.Time) (Key, error) {
+	for i := len(s.Keys) - 1; i >= 0; i-- {
+		key := s.Keys[i]
+		if key.RetiredAt == nil && !key.ActiveAt.After(now) {
+			return key, nil
+		}
+	}
+	return Key{}, ErrNoSigningKey
+}
+
+// Verifying returns the key with the ID if tokens signed with it are still
+// accepted.
+func (s KeySet) Verifying(kid string) (Key, error) {
+	for _, key := range s.Keys {
+		if key.ID == kid && key.RetiredAt == nil {
+			return key, nil
+		}
+	}
+	return Key{}, ErrUnknownKey
+}
+
+// JWK is a public key in the JSON Web Key format of RFC 7517.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	ID        string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// N and E are the modulus and exponent of RSA keys.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Curve and X describe Ed25519 keys, RFC 8037.
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the RS256 and EdDSA keys that are not
+// retired, including keys that do not sign yet. HS256 keys have no public
+// part and are left out.
+func (s KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range s.Keys {
+		if key.RetiredAt != nil || key.signer == nil {
+			continue
+		}
+
+		jwk := JWK{ID: key.ID, Use: "sig", Algorithm: key.Algorithm}
+		switch public := key.signer.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
@@ -0,0 +1,103 @@
+package jwt_test
+
+import (
+	"awesomeProject/internal/jwt"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_KeyRotation(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Now()
+
+	var keys jwt.KeySet
+	old, err := keys.Generate(jwt.AlgHS256, now.Add(-time.Hour), now.Add(-time.Hour))
+	require.NoError(t, err)
+	oldToken, err := jwt.NewService(zaptest.NewLogger(t), time.Minute, nil, keys).New(ctx, userID, "alice@example.com", uuid.New())
+	require.NoError(t, err)
+
+	// a pending key is accepted but does not sign yet
+	pending, err := keys.Generate(jwt.AlgHS256, now, now.Add(time.Hour))
+	require.NoError(t, err)
+	signing, err := keys.Signing(now)
+	require.NoError(t, err)
+	assert.Equal(t, old.ID, signing.ID)
+
+	current, err := keys.Generate(jwt.AlgHS256, now, now)
+	require.NoError(t, err)
+	s := jwt.NewService(zaptest.NewLogger(t), time.Minute, nil, keys)
+	newToken, err := s.New(ctx, userID, "alice@example.com", uuid.New())
+	require.NoError(t, err)
+
+	parsed, err := s.Parse(ctx, "Bearer "+oldToken)
+	require.NoError(t, err, "tokens of older keys stay valid")
+	assert.Equal(t, userID, parsed)
+	parsed, err = s.Parse(ctx, "Bearer "+newToken)
+	require.NoError(t, err)
+	assert.Equal(t, userID, parsed)
+
+	require.NoError(t, keys.Retire(old.ID, now))
+	require.NoError(t, keys.Retire(pending.ID, now))
+	assert.ErrorIs(t, keys.Retire(current.ID, now), jwt.ErrLastKey)
+	assert.ErrorIs(t, keys.Retire(old.ID, now), jwt.ErrUnknownKey)
+
+	s = jwt.NewService(zaptest.NewLogger(t), time.Minute, nil, keys)
+	_, err = s.Parse(ctx, "Bearer "+oldToken)
+	assert.ErrorIs(t, err, jwt.ErrUnknownKey)
+	_, err = s.Parse(ctx, "Bearer "+newToken)
+	assert.NoError(t, err)
+}
+
+func TestKeySet_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	now := time.Now().Truncate(time.Second)
+
+	var keys jwt.KeySet
+	_, err := keys.Generate(jwt.AlgHS256, now, now)
+	require.NoError(t, err)
+	require.NoError(t, keys.Save(path))
+
+	loaded, err := jwt.LoadKeySet(path)
+	require.NoError(t, err)
+	assert.Equal(t, keys.Keys[0].ID, loaded.Keys[0].ID)
+	assert.Equal(t, keys.Keys[0].Secret, loaded.Keys[0].Secret)
+	assert.True(t, keys.Keys[0].ActiveAt.Equal(loaded.Keys[0].ActiveAt))
+}
+
+func TestHandler_JWKS(t *testing.T) {
+	now := time.Now()
+	var keys jwt.KeySet
+	_, err := keys.Generate(jwt.AlgHS256, now, now)
+	require.NoError(t, err)
+	_, err = keys.Generate(jwt.AlgEdDSA, now, now.Add(time.Hour))
+	require.NoError(t, err)
+	h := jwt.NewHandler(zaptest.NewLogger(t), nil, jwt.NewService(zaptest.NewLogger(t), time.Minute, nil, keys), nil, nil)
+
+	w := httptest.NewRecorder()
+	h.JWKS(w, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+
+	var set jwt.JWKS
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+	require.Len(t, set.Keys, 1, "pending keys are published, HS256 keys are not")
+	assert.Equal(t, keys.Keys[1].ID, set.Keys[0].ID)
+	assert.Equal(t, "OKP", set.Keys[0].KeyType)
+
+	r := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	r.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	h.JWKS(w, r)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
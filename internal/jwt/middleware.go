@@ -4,25 +4,35 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
-const UserContextKey = "user"
+const (
+	UserContextKey = "user"
+	// TokenContextKey holds the Token of the request, which logging out
+	// revokes.
+	TokenContextKey = "token"
+)
 
 type Verifier interface {
-	Parse(ctx context.Context, tokenString string) (uuid.UUID, error)
+	ParseToken(ctx context.Context, tokenString string) (Token, error)
+}
+
+type Revocations interface {
+	IsRevoked(token Token) bool
 }
 
 type Middleware struct {
-	logger   *zap.Logger
-	verifier Verifier
+	logger      *zap.Logger
+	verifier    Verifier
+	revocations Revocations
 }
 
-func NewMiddleware(logger *zap.Logger, verifier Verifier) Middleware {
+func NewMiddleware(logger *zap.Logger, verifier Verifier, revocations Revocations) Middleware {
 	return Middleware{
-		logger:   logger,
-		verifier: verifier,
+		logger:      logger,
+		verifier:    verifier,
+		revocations: revocations,
 	}
 }
 
@@ -37,16 +47,22 @@ func (m Middleware) HandlerFunc(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		id, err := m.verifier.Parse(ctx, token)
+		parsed, err := m.verifier.ParseToken(ctx, token)
 		if err != nil {
 			m.logger.Warn("Authorization header invalid", zap.Error(err))
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
+		if m.revocations.IsRevoked(parsed) {
+			m.logger.Warn("Authorization header invalid", zap.Error(ErrTokenRevoked), zap.String("jti", parsed.ID.String()))
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
 
 		// [MODIFIED] 更新日誌和 context
-		m.logger.Debug("Authorization header valid", zap.String("user_id", id.String()))
-		ctx = context.WithValue(ctx, UserContextKey, id)
+		m.logger.Debug("Authorization header valid", zap.String("user_id", parsed.UserID.String()))
+		ctx = context.WithValue(ctx, UserContextKey, parsed.UserID)
+		ctx = context.WithValue(ctx, TokenContextKey, parsed)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
@@ -9,18 +9,97 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Answer struct {
+	ResponseID uuid.UUID
+	QuestionID uuid.UUID
+	Value      []byte
+	Points     pgtype.Float8
+	GradedBy   pgtype.UUID
+	GradedAt   pgtype.Timestamptz
+}
+
 type Bookmark struct {
+	FormID     uuid.UUID
+	UserID     uuid.UUID
+	CreatedAt  pgtype.Timestamptz
+	RevisionID pgtype.UUID
+}
+
+type Form struct {
+	ID                 uuid.UUID
+	Title              string
+	Description        pgtype.Text
+	AuthorID           pgtype.Text
+	CreatedAt          pgtype.Timestamptz
+	Status             string
+	DeletedAt          pgtype.Timestamptz
+	Version            int32
+	IsQuiz             bool
+	RevealAnswers      string
+	TemplateVisibility pgtype.Text
+}
+
+type FormCollaborator struct {
 	FormID    uuid.UUID
-	UserID    uuid.UUID
+	Email     string
+	UserID    pgtype.UUID
+	Role      string
+	InvitedBy pgtype.UUID
 	CreatedAt pgtype.Timestamptz
 }
 
-type Form struct {
+type FormResponse struct {
+	ID           uuid.UUID
+	FormID       uuid.UUID
+	RespondentID pgtype.UUID
+	SubmittedAt  pgtype.Timestamptz
+}
+
+type FormShareLink struct {
+	ID             uuid.UUID
+	FormID         uuid.UUID
+	Slug           string
+	AccessCodeHash pgtype.Text
+	RequireSignIn  bool
+	ExpiresAt      pgtype.Timestamptz
+	CreatedBy      pgtype.UUID
+	CreatedAt      pgtype.Timestamptz
+}
+
+type FormRevision struct {
+	ID        uuid.UUID
+	FormID    uuid.UUID
+	Number    int32
+	Snapshot  []byte
+	CreatedBy pgtype.UUID
+	CreatedAt pgtype.Timestamptz
+}
+
+type FormSearchDocument struct {
+	FormID    uuid.UUID
+	Document  interface{}
+	Body      string
+	UpdatedAt pgtype.Timestamptz
+}
+
+type FormSection struct {
 	ID          uuid.UUID
+	FormID      uuid.UUID
 	Title       string
 	Description pgtype.Text
-	AuthorID    pgtype.Text
+	Position    int32
+	Rules       []byte
 	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+}
+
+type FormStatusTransition struct {
+	ID         uuid.UUID
+	FormID     uuid.UUID
+	FromStatus string
+	ToStatus   string
+	ChangedBy  pgtype.UUID
+	ChangedAt  pgtype.Timestamptz
 }
 
 type Jwt struct {
@@ -28,6 +107,38 @@ type Jwt struct {
 	UserID         uuid.UUID
 	ExpirationTime pgtype.Timestamptz
 	IsAvailable    bool
+	FamilyID       uuid.UUID
+	ParentID       pgtype.UUID
+	TokenHash      []byte
+	RevokedAt      pgtype.Timestamptz
+}
+
+type JwtRevocation struct {
+	Jti       uuid.UUID
+	UserID    uuid.UUID
+	ExpiresAt pgtype.Timestamptz
+}
+
+type JwtUserRevocation struct {
+	UserID        uuid.UUID
+	RevokedBefore pgtype.Timestamptz
+}
+
+type Question struct {
+	ID          uuid.UUID
+	FormID      uuid.UUID
+	Type        string
+	Title       string
+	Description pgtype.Text
+	Required    bool
+	Position    int32
+	Settings    []byte
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+	SectionID   pgtype.UUID
+	Rules       []byte
+	Points      float64
+	AnswerKey   []byte
 }
 
 type User struct {
@@ -1,6 +1,6 @@
 -- name: Create :one
-INSERT INTO jwt (user_id, expiration_time)
-VALUES ($1, $2)
+INSERT INTO jwt (user_id, expiration_time, family_id, parent_id, token_hash)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: Update :one
@@ -8,8 +8,42 @@ UPDATE jwt SET is_available = $2
 where id = $1
 RETURNING *;
 
--- name: IsAvailable :one
+-- name: GetByTokenHash :one
 SELECT * FROM jwt
-where id = $1
+where token_hash = $1
 limit 1;
 
+-- name: Consume :execrows
+UPDATE jwt SET is_available = false
+where id = $1 AND is_available;
+
+-- name: RevokeFamily :execrows
+UPDATE jwt SET is_available = false, revoked_at = now()
+where family_id = $1 AND is_available;
+
+-- name: RevokeUserRefreshTokens :execrows
+UPDATE jwt SET is_available = false, revoked_at = now()
+where user_id = $1 AND is_available;
+
+-- name: RevokeToken :exec
+INSERT INTO jwt_revocations (jti, user_id, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (jti) DO NOTHING;
+
+-- name: RevokeUserTokens :exec
+INSERT INTO jwt_user_revocations (user_id, revoked_before)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+    SET revoked_before = GREATEST(jwt_user_revocations.revoked_before, EXCLUDED.revoked_before);
+
+-- name: ListTokenRevocations :many
+SELECT * FROM jwt_revocations
+where expires_at > $1;
+
+-- name: ListUserRevocations :many
+SELECT * FROM jwt_user_revocations
+where revoked_before > $1;
+
+-- name: DeleteExpiredRevocations :exec
+DELETE FROM jwt_revocations
+where expires_at <= $1;
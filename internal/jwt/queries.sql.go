@@ -12,51 +12,201 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const consume = `-- name: Consume :execrows
+UPDATE jwt SET is_available = false
+where id = $1 AND is_available
+`
+
+func (q *Queries) Consume(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, consume, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const create = `-- name: Create :one
-INSERT INTO jwt (user_id, expiration_time)
-VALUES ($1, $2)
-RETURNING id, user_id, expiration_time, is_available
+INSERT INTO jwt (user_id, expiration_time, family_id, parent_id, token_hash)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, expiration_time, is_available, family_id, parent_id, token_hash, revoked_at
 `
 
 type CreateParams struct {
 	UserID         uuid.UUID
 	ExpirationTime pgtype.Timestamptz
+	FamilyID       uuid.UUID
+	ParentID       pgtype.UUID
+	TokenHash      []byte
 }
 
 func (q *Queries) Create(ctx context.Context, arg CreateParams) (Jwt, error) {
-	row := q.db.QueryRow(ctx, create, arg.UserID, arg.ExpirationTime)
+	row := q.db.QueryRow(ctx, create,
+		arg.UserID,
+		arg.ExpirationTime,
+		arg.FamilyID,
+		arg.ParentID,
+		arg.TokenHash,
+	)
 	var i Jwt
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
 		&i.ExpirationTime,
 		&i.IsAvailable,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.TokenHash,
+		&i.RevokedAt,
 	)
 	return i, err
 }
 
-const isAvailable = `-- name: IsAvailable :one
-SELECT id, user_id, expiration_time, is_available FROM jwt
-where id = $1
+const deleteExpiredRevocations = `-- name: DeleteExpiredRevocations :exec
+DELETE FROM jwt_revocations
+where expires_at <= $1
+`
+
+func (q *Queries) DeleteExpiredRevocations(ctx context.Context, expiresAt pgtype.Timestamptz) error {
+	_, err := q.db.Exec(ctx, deleteExpiredRevocations, expiresAt)
+	return err
+}
+
+const getByTokenHash = `-- name: GetByTokenHash :one
+SELECT id, user_id, expiration_time, is_available, family_id, parent_id, token_hash, revoked_at FROM jwt
+where token_hash = $1
 limit 1
 `
 
-func (q *Queries) IsAvailable(ctx context.Context, id uuid.UUID) (Jwt, error) {
-	row := q.db.QueryRow(ctx, isAvailable, id)
+func (q *Queries) GetByTokenHash(ctx context.Context, tokenHash []byte) (Jwt, error) {
+	row := q.db.QueryRow(ctx, getByTokenHash, tokenHash)
 	var i Jwt
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
 		&i.ExpirationTime,
 		&i.IsAvailable,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.TokenHash,
+		&i.RevokedAt,
 	)
 	return i, err
 }
 
+const listTokenRevocations = `-- name: ListTokenRevocations :many
+SELECT jti, user_id, expires_at FROM jwt_revocations
+where expires_at > $1
+`
+
+func (q *Queries) ListTokenRevocations(ctx context.Context, expiresAt pgtype.Timestamptz) ([]JwtRevocation, error) {
+	rows, err := q.db.Query(ctx, listTokenRevocations, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JwtRevocation
+	for rows.Next() {
+		var i JwtRevocation
+		if err := rows.Scan(&i.Jti, &i.UserID, &i.ExpiresAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserRevocations = `-- name: ListUserRevocations :many
+SELECT user_id, revoked_before FROM jwt_user_revocations
+where revoked_before > $1
+`
+
+func (q *Queries) ListUserRevocations(ctx context.Context, revokedBefore pgtype.Timestamptz) ([]JwtUserRevocation, error) {
+	rows, err := q.db.Query(ctx, listUserRevocations, revokedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JwtUserRevocation
+	for rows.Next() {
+		var i JwtUserRevocation
+		if err := rows.Scan(&i.UserID, &i.RevokedBefore); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeFamily = `-- name: RevokeFamily :execrows
+UPDATE jwt SET is_available = false, revoked_at = now()
+where family_id = $1 AND is_available
+`
+
+func (q *Queries) RevokeFamily(ctx context.Context, familyID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeFamily, familyID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeToken = `-- name: RevokeToken :exec
+INSERT INTO jwt_revocations (jti, user_id, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (jti) DO NOTHING
+`
+
+type RevokeTokenParams struct {
+	Jti       uuid.UUID
+	UserID    uuid.UUID
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) RevokeToken(ctx context.Context, arg RevokeTokenParams) error {
+	_, err := q.db.Exec(ctx, revokeToken, arg.Jti, arg.UserID, arg.ExpiresAt)
+	return err
+}
+
+const revokeUserRefreshTokens = `-- name: RevokeUserRefreshTokens :execrows
+UPDATE jwt SET is_available = false, revoked_at = now()
+where user_id = $1 AND is_available
+`
+
+func (q *Queries) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeUserRefreshTokens, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeUserTokens = `-- name: RevokeUserTokens :exec
+INSERT INTO jwt_user_revocations (user_id, revoked_before)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+    SET revoked_before = GREATEST(jwt_user_revocations.revoked_before, EXCLUDED.revoked_before)
+`
+
+type RevokeUserTokensParams struct {
+	UserID        uuid.UUID
+	RevokedBefore pgtype.Timestamptz
+}
+
+func (q *Queries) RevokeUserTokens(ctx context.Context, arg RevokeUserTokensParams) error {
+	_, err := q.db.Exec(ctx, revokeUserTokens, arg.UserID, arg.RevokedBefore)
+	return err
+}
+
 const update = `-- name: Update :one
 UPDATE jwt SET is_available = $2
 where id = $1
-RETURNING id, user_id, expiration_time, is_available
+RETURNING id, user_id, expiration_time, is_available, family_id, parent_id, token_hash, revoked_at
 `
 
 type UpdateParams struct {
@@ -72,6 +222,10 @@ func (q *Queries) Update(ctx context.Context, arg UpdateParams) (Jwt, error) {
 		&i.UserID,
 		&i.ExpirationTime,
 		&i.IsAvailable,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.TokenHash,
+		&i.RevokedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,157 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+//go:generate mockery --name=RevocationQuerier
+type RevocationQuerier interface {
+	RevokeToken(ctx context.Context, arg RevokeTokenParams) error
+	RevokeUserTokens(ctx context.Context, arg RevokeUserTokensParams) error
+	ListTokenRevocations(ctx context.Context, expiresAt pgtype.Timestamptz) ([]JwtRevocation, error)
+	ListUserRevocations(ctx context.Context, revokedBefore pgtype.Timestamptz) ([]JwtUserRevocation, error)
+	DeleteExpiredRevocations(ctx context.Context, expiresAt pgtype.Timestamptz) error
+}
+
+// RevocationList holds the access tokens revoked before they expire: single
+// tokens by their jti and all tokens a user got before logging out
+// everywhere. Revocations are stored in Postgres and checked against an
+// in-memory copy, so checking a token costs no query. Revocations made by
+// other servers show up once Sync reloads the list.
+type RevocationList struct {
+	logger     *zap.Logger
+	queries    RevocationQuerier
+	expiration time.Duration
+
+	mu     sync.RWMutex
+	tokens map[uuid.UUID]time.Time
+	users  map[uuid.UUID]time.Time
+}
+
+// NewRevocationList returns an empty list for access tokens that are valid
+// for expiration. Call Load before checking tokens.
+func NewRevocationList(logger *zap.Logger, querier RevocationQuerier, expiration time.Duration) *RevocationList {
+	return &RevocationList{
+		logger:     logger,
+		queries:    querier,
+		expiration: expiration,
+		tokens:     make(map[uuid.UUID]time.Time),
+		users:      make(map[uuid.UUID]time.Time),
+	}
+}
+
+// IsRevoked reports whether the token was revoked.
+func (l *RevocationList) IsRevoked(token Token) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if _, ok := l.tokens[token.ID]; ok {
+		return true
+	}
+	revokedBefore, ok := l.users[token.UserID]
+	return ok && !token.IssuedAt.After(revokedBefore)
+}
+
+// RevokeToken revokes the token until it expires.
+func (l *RevocationList) RevokeToken(ctx context.Context, token Token) error {
+	err := l.queries.RevokeToken(ctx, RevokeTokenParams{
+		Jti:       token.ID,
+		UserID:    token.UserID,
+		ExpiresAt: pgtype.Timestamptz{Time: token.ExpiresAt, Valid: true},
+	})
+	if err != nil {
+		l.logger.Error("Failed to revoke access token", zap.String("jti", token.ID.String()), zap.Error(err))
+		return err
+	}
+
+	l.mu.Lock()
+	l.tokens[token.ID] = token.ExpiresAt
+	l.mu.Unlock()
+
+	l.logger.Info("Revoked access token", zap.String("jti", token.ID.String()), zap.String("user_id", token.UserID.String()))
+
+	return nil
+}
+
+// RevokeUser revokes every token of the user issued before the time. Token
+// timestamps have whole seconds, so tokens issued in the same second are
+// revoked as well, or a token issued moments before could survive.
+func (l *RevocationList) RevokeUser(ctx context.Context, userID uuid.UUID, before time.Time) error {
+	err := l.queries.RevokeUserTokens(ctx, RevokeUserTokensParams{
+		UserID:        userID,
+		RevokedBefore: pgtype.Timestamptz{Time: before, Valid: true},
+	})
+	if err != nil {
+		l.logger.Error("Failed to revoke access tokens", zap.String("user_id", userID.String()), zap.Error(err))
+		return err
+	}
+
+	l.mu.Lock()
+	if before.After(l.users[userID]) {
+		l.users[userID] = before
+	}
+	l.mu.Unlock()
+
+	l.logger.Info("Revoked access tokens", zap.String("user_id", userID.String()), zap.Time("revoked_before", before))
+
+	return nil
+}
+
+// Load replaces the list with the revocations stored in Postgres. Expired
+// revocations are left out: their tokens are rejected anyway.
+func (l *RevocationList) Load(ctx context.Context) error {
+	now := time.Now()
+	tokenRows, err := l.queries.ListTokenRevocations(ctx, pgtype.Timestamptz{Time: now, Valid: true})
+	if err != nil {
+		l.logger.Error("Failed to list access token revocations", zap.Error(err))
+		return err
+	}
+	userRows, err := l.queries.ListUserRevocations(ctx, pgtype.Timestamptz{Time: now.Add(-l.expiration), Valid: true})
+	if err != nil {
+		l.logger.Error("Failed to list user token revocations", zap.Error(err))
+		return err
+	}
+
+	tokens := make(map[uuid.UUID]time.Time, len(tokenRows))
+	for _, row := range tokenRows {
+		tokens[row.Jti] = row.ExpiresAt.Time
+	}
+	users := make(map[uuid.UUID]time.Time, len(userRows))
+	for _, row := range userRows {
+		users[row.UserID] = row.RevokedBefore.Time
+	}
+
+	l.mu.Lock()
+	l.tokens = tokens
+	l.users = users
+	l.mu.Unlock()
+
+	return nil
+}
+
+// Sync reloads the list every interval until ctx is done and deletes
+// revocations of expired tokens. A failed reload is logged and the list
+// loaded before stays in use.
+func (l *RevocationList) Sync(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := l.queries.DeleteExpiredRevocations(ctx, pgtype.Timestamptz{Time: time.Now(), Valid: true})
+		if err != nil {
+			l.logger.Error("Failed to delete expired access token revocations", zap.Error(err))
+		}
+		_ = l.Load(ctx)
+	}
+}
@@ -0,0 +1,118 @@
+package jwt_test
+
+import (
+	"awesomeProject/internal/jwt"
+	"awesomeProject/internal/jwt/mocks"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestRevocationList(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Now()
+
+	querier := mocks.NewRevocationQuerier(t)
+	querier.On("RevokeToken", mock.Anything, mock.Anything).Return(nil)
+	querier.On("RevokeUserTokens", mock.Anything, mock.Anything).Return(nil)
+	list := jwt.NewRevocationList(zaptest.NewLogger(t), querier, time.Minute)
+
+	revoked := jwt.Token{ID: uuid.New(), UserID: userID, IssuedAt: now, ExpiresAt: now.Add(time.Minute)}
+	other := jwt.Token{ID: uuid.New(), UserID: userID, IssuedAt: now, ExpiresAt: now.Add(time.Minute)}
+	require.NoError(t, list.RevokeToken(ctx, revoked))
+	assert.True(t, list.IsRevoked(revoked))
+	assert.False(t, list.IsRevoked(other))
+
+	// logging out everywhere revokes the tokens issued before, not after
+	require.NoError(t, list.RevokeUser(ctx, userID, now.Add(time.Second)))
+	assert.True(t, list.IsRevoked(other))
+	assert.False(t, list.IsRevoked(jwt.Token{ID: uuid.New(), UserID: userID, IssuedAt: now.Add(2 * time.Second)}))
+	assert.False(t, list.IsRevoked(jwt.Token{ID: uuid.New(), UserID: uuid.New(), IssuedAt: now}))
+
+	// tokens only have whole seconds, one issued in the second of the logout
+	// may well be older than it
+	loggedOutAt := now.Add(time.Minute)
+	require.NoError(t, list.RevokeUser(ctx, userID, loggedOutAt))
+	assert.True(t, list.IsRevoked(jwt.Token{ID: uuid.New(), UserID: userID, IssuedAt: loggedOutAt.Truncate(time.Second)}))
+
+	// revocations of other servers show up on load
+	fromDB := jwt.Token{ID: uuid.New(), UserID: uuid.New(), IssuedAt: now}
+	querier.On("ListTokenRevocations", mock.Anything, mock.Anything).Return([]jwt.JwtRevocation{
+		{Jti: fromDB.ID, UserID: fromDB.UserID, ExpiresAt: pgtype.Timestamptz{Time: now.Add(time.Minute), Valid: true}},
+	}, nil)
+	querier.On("ListUserRevocations", mock.Anything, mock.Anything).Return([]jwt.JwtUserRevocation{}, nil)
+	require.NoError(t, list.Load(ctx))
+	assert.True(t, list.IsRevoked(fromDB))
+	assert.False(t, list.IsRevoked(revoked), "the list is replaced by what is stored")
+}
+
+func TestMiddleware_Revoked(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	familyID := uuid.New()
+	now := time.Now()
+
+	var keys jwt.KeySet
+	_, err := keys.Generate(jwt.AlgEdDSA, now, now)
+	require.NoError(t, err)
+	s := jwt.NewService(zaptest.NewLogger(t), time.Minute, nil, keys)
+	tokenString, err := s.New(ctx, userID, "alice@example.com", familyID)
+	require.NoError(t, err)
+
+	querier := mocks.NewRevocationQuerier(t)
+	querier.On("RevokeToken", mock.Anything, mock.Anything).Return(nil)
+	list := jwt.NewRevocationList(zaptest.NewLogger(t), querier, time.Minute)
+	m := jwt.NewMiddleware(zaptest.NewLogger(t), s, list)
+
+	var token jwt.Token
+	handler := m.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Context().Value(jwt.TokenContextKey).(jwt.Token)
+		assert.Equal(t, userID, r.Context().Value(jwt.UserContextKey))
+	})
+	serve := func() int {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+tokenString)
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve())
+	assert.Equal(t, userID, token.UserID)
+	assert.Equal(t, familyID, token.FamilyID)
+	assert.NotEqual(t, uuid.Nil, token.ID)
+
+	require.NoError(t, list.RevokeToken(ctx, token))
+	assert.Equal(t, http.StatusUnauthorized, serve())
+}
+
+func TestHandler_Logout(t *testing.T) {
+	ctx := context.Background()
+	token := jwt.Token{ID: uuid.New(), UserID: uuid.New(), FamilyID: uuid.New(), IssuedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute)}
+
+	querier := mocks.NewQuerier(t)
+	// the session ends even though the request does not name its refresh token
+	querier.On("RevokeFamily", mock.Anything, token.FamilyID).Return(int64(2), nil)
+	revocationQuerier := mocks.NewRevocationQuerier(t)
+	revocationQuerier.On("RevokeToken", mock.Anything, mock.Anything).Return(nil)
+	list := jwt.NewRevocationList(zaptest.NewLogger(t), revocationQuerier, time.Minute)
+	h := jwt.NewHandler(zaptest.NewLogger(t), nil, jwt.NewService(zaptest.NewLogger(t), time.Minute, querier, jwt.KeySet{}), nil, list)
+
+	r := httptest.NewRequest(http.MethodPost, "/api/logout", nil)
+	r = r.WithContext(context.WithValue(ctx, jwt.TokenContextKey, token))
+	w := httptest.NewRecorder()
+	h.Logout(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.True(t, list.IsRevoked(token))
+}
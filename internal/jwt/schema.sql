@@ -2,5 +2,24 @@ CREATE TABLE IF NOT EXISTS jwt (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     user_id UUID NOT NULL references users(id),
     expiration_time TIMESTAMPTZ NOT NULL DEFAULT now(),
-    is_available bool NOT NULL DEFAULT true
+    is_available bool NOT NULL DEFAULT true,
+    family_id UUID NOT NULL,
+    parent_id UUID references jwt(id) ON DELETE SET NULL,
+    token_hash BYTEA NOT NULL UNIQUE,
+    revoked_at TIMESTAMPTZ
+    );
+
+CREATE INDEX IF NOT EXISTS jwt_family_idx ON jwt (family_id);
+
+CREATE TABLE IF NOT EXISTS jwt_revocations (
+    jti UUID PRIMARY KEY,
+    user_id UUID NOT NULL references users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL
+    );
+
+CREATE INDEX IF NOT EXISTS jwt_revocations_expires_at_idx ON jwt_revocations (expires_at);
+
+CREATE TABLE IF NOT EXISTS jwt_user_revocations (
+    user_id UUID PRIMARY KEY references users(id) ON DELETE CASCADE,
+    revoked_before TIMESTAMPTZ NOT NULL
     );
\ No newline at end of file
@@ -2,51 +2,157 @@ package jwt
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
 
-const secret = "default_secret"
+var (
+	ErrRefreshTokenInvalid = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token already used")
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrRefreshTokenRevoked = errors.New("refresh token revoked")
+	ErrTokenRevoked        = errors.New("token revoked")
+)
+
+// refreshTokenBytes is the amount of randomness in a refresh token.
+const refreshTokenBytes = 32
 
+//go:generate mockery --name=Querier
 type Querier interface {
 	Create(ctx context.Context, arg CreateParams) (Jwt, error)
 	Update(ctx context.Context, arg UpdateParams) (Jwt, error)
-	IsAvailable(ctx context.Context, id uuid.UUID) (Jwt, error)
+	GetByTokenHash(ctx context.Context, tokenHash []byte) (Jwt, error)
+	Consume(ctx context.Context, id uuid.UUID) (int64, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) (int64, error)
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) (int64, error)
 }
 type Service struct {
 	logger     *zap.Logger
 	expiration time.Duration
 	queries    Querier
+	keys       *keyring
 }
 
-func NewService(logger *zap.Logger, expiration time.Duration, querier Querier) *Service {
+// keyring holds the current key set, which WatchKeys replaces while tokens
+// are signed and parsed.
+type keyring struct {
+	mu  sync.RWMutex
+	set KeySet
+}
+
+func (k *keyring) get() KeySet {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.set
+}
+
+func (k *keyring) replace(set KeySet) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.set = set
+}
+
+func NewService(logger *zap.Logger, expiration time.Duration, querier Querier, keys KeySet) *Service {
 	return &Service{
 		logger:     logger,
 		expiration: expiration,
 		queries:    querier,
+		keys:       &keyring{set: keys},
+	}
+}
+
+// WatchKeys reloads the key file at path whenever it changes, checking every
+// interval until ctx is done, so keys generated or retired with cmd/jwtkeys
+// take effect without a restart. A file that fails to load is logged and the
+// keys loaded before stay in use.
+func (s Service) WatchKeys(ctx context.Context, path string, interval time.Duration) {
+	var modified time.Time
+	if info, err := os.Stat(path); err == nil {
+		modified = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			s.logger.Error("Failed to check JWT key file", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		if info.ModTime().Equal(modified) {
+			continue
+		}
+		keys, err := LoadKeySet(path)
+		if err != nil {
+			s.logger.Error("Failed to reload JWT keys", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		modified = info.ModTime()
+		s.keys.replace(keys)
+		s.logger.Info("Reloaded JWT keys", zap.String("path", path), zap.Int("keys", len(keys.Keys)))
 	}
 }
 
+// JWKS returns the public keys tokens may be verified with.
+func (s Service) JWKS() JWKS {
+	return s.keys.get().JWKS()
+}
+
 type claims struct {
-	Message string
-	Id      uuid.UUID
-	Email   string
+	Message  string
+	Id       uuid.UUID
+	Email    string
+	FamilyID uuid.UUID `json:"family_id"`
 	jwt.RegisteredClaims
 }
 
-func (s Service) New(ctx context.Context, id uuid.UUID, email string) (string, error) {
+// Token is a parsed access token.
+type Token struct {
+	// ID is the jti claim, which tokens are revoked by.
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// FamilyID is the refresh token family of the session the token was
+	// issued for, which logging out revokes.
+	FamilyID uuid.UUID
+}
+
+// New signs an access token for the user in the session of the refresh
+// token family.
+func (s Service) New(ctx context.Context, id uuid.UUID, email string, familyID uuid.UUID) (string, error) {
 	jwtID := uuid.New()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
-		Message: "This is a Backend-Training JWT token",
-		Id:      id,
-		Email:   email,
+	key, err := s.keys.get().Signing(time.Now())
+	if err != nil {
+		s.logger.Error("Failed to find a JWT signing key", zap.Error(err))
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.method(), claims{
+		Message:  "This is a Backend-Training JWT token",
+		Id:       id,
+		Email:    email,
+		FamilyID: familyID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    "Backend-Training",
 			Subject:   "Backend-Training Token",
@@ -57,7 +163,9 @@ func (s Service) New(ctx context.Context, id uuid.UUID, email string) (string, e
 		},
 	})
 
-	tokenString, err := token.SignedString([]byte(secret))
+	token.Header["kid"] = key.ID
+
+	tokenString, err := token.SignedString(key.signingKey())
 	if err != nil {
 		s.logger.Error("Failed to sign token", zap.Error(err))
 		return "", err
@@ -69,19 +177,43 @@ func (s Service) New(ctx context.Context, id uuid.UUID, email string) (string, e
 }
 
 func (s Service) Parse(ctx context.Context, tokenString string) (uuid.UUID, error) {
+	token, err := s.ParseToken(ctx, tokenString)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	return token.UserID, nil
+}
+
+// ParseToken validates the access token like Parse and returns its claims
+// needed to revoke it.
+func (s Service) ParseToken(ctx context.Context, tokenString string) (Token, error) {
 	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 
+	keys := s.keys.get()
 	token, err := jwt.ParseWithClaims(tokenString, &claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		key, err := keys.Verifying(kid)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", err, kid)
+		}
+		// a token must not pick another algorithm than its key has, or a
+		// public key could be misused as HMAC secret
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("%w: %q is a %s key", jwt.ErrTokenSignatureInvalid, kid, key.Algorithm)
+		}
+		return key.verifyingKey(), nil
+	}, jwt.WithValidMethods(Algorithms))
 	if err != nil {
 		switch {
+		case errors.Is(err, ErrUnknownKey):
+			s.logger.Warn("Failed to parse JWT token signed with an unknown or retired key", zap.String("error", err.Error()))
+			return Token{}, err
 		case errors.Is(err, jwt.ErrTokenMalformed):
 			s.logger.Warn("Failed to parse JWT token due to malformed structure, this is not a JWT token", zap.String("error", err.Error()))
-			return uuid.UUID{}, err
+			return Token{}, err
 		case errors.Is(err, jwt.ErrSignatureInvalid):
 			s.logger.Warn("Failed to parse JWT token due to invalid signature", zap.String("error", err.Error()))
-			return uuid.UUID{}, err
+			return Token{}, err
 		case errors.Is(err, jwt.ErrTokenExpired):
 			expiredTime, getErr := token.Claims.GetExpirationTime()
 			if getErr != nil {
@@ -90,7 +222,7 @@ func (s Service) Parse(ctx context.Context, tokenString string) (uuid.UUID, erro
 				s.logger.Warn("Failed to parse JWT token due to expired timestamp", zap.String("error", err.Error()), zap.Time("expired_at", expiredTime.Time))
 			}
 
-			return uuid.UUID{}, err
+			return Token{}, err
 		case errors.Is(err, jwt.ErrTokenNotValidYet):
 			notBeforeTime, getErr := token.Claims.GetNotBefore()
 			if getErr != nil {
@@ -99,36 +231,76 @@ func (s Service) Parse(ctx context.Context, tokenString string) (uuid.UUID, erro
 				s.logger.Warn("Failed to parse JWT token due to not valid yet timestamp", zap.String("error", err.Error()), zap.Time("not_valid_yet", notBeforeTime.Time))
 			}
 
-			return uuid.UUID{}, err
+			return Token{}, err
 		default:
 			s.logger.Error("Failed to parse or validate JWT token", zap.Error(err))
-			return uuid.UUID{}, err
+			return Token{}, err
 		}
 	}
 
 	c, ok := token.Claims.(*claims)
 	if !ok {
 		s.logger.Warn("Invalid JWT token claims")
-		return uuid.UUID{}, errors.New("invalid token claims")
+		return Token{}, errors.New("invalid token claims")
+	}
+	jti, err := uuid.Parse(c.ID)
+	if err != nil || c.IssuedAt == nil || c.ExpiresAt == nil {
+		s.logger.Warn("Invalid JWT token claims", zap.String("jti", c.ID))
+		return Token{}, errors.New("invalid token claims")
 	}
 
 	s.logger.Debug("Parsed JWT token successfully")
 
 	// [MODIFIED] 返回 Email 而不是 Message
-	return c.Id, nil
+	return Token{
+		ID:        jti,
+		UserID:    c.Id,
+		FamilyID:  c.FamilyID,
+		IssuedAt:  c.IssuedAt.Time,
+		ExpiresAt: c.ExpiresAt.Time,
+	}, nil
 }
 
-func (s Service) Create(ctx context.Context, userID uuid.UUID, time pgtype.Timestamptz) (Jwt, error) {
-	result, err := s.queries.Create(ctx, CreateParams{
+// RefreshToken is an issued refresh token. Token is the secret handed to the
+// client, which is not stored; the database only knows its hash.
+type RefreshToken struct {
+	Jwt
+	Token string
+}
+
+// Create issues the refresh token of a new login, which starts a new token
+// family.
+func (s Service) Create(ctx context.Context, userID uuid.UUID, time pgtype.Timestamptz) (RefreshToken, error) {
+	return s.issue(ctx, CreateParams{
 		UserID:         userID,
 		ExpirationTime: time,
+		FamilyID:       uuid.New(),
 	})
+}
+
+// issue generates a refresh token and stores its hash with the params.
+func (s Service) issue(ctx context.Context, params CreateParams) (RefreshToken, error) {
+	b := make([]byte, refreshTokenBytes)
+	_, err := rand.Read(b)
+	if err != nil {
+		s.logger.Error("Failed to generate refresh token", zap.Error(err))
+		return RefreshToken{}, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	params.TokenHash = hashRefreshToken(token)
+
+	result, err := s.queries.Create(ctx, params)
 	if err != nil {
 		s.logger.Error("Failed to create JWT", zap.Error(err))
-		return Jwt{}, err
+		return RefreshToken{}, err
 	}
 	s.logger.Info("Create JWT", zap.String("jwt_id", result.ID.String()), zap.String("user_id", result.UserID.String()))
-	return result, err
+	return RefreshToken{Jwt: result, Token: token}, nil
+}
+
+func hashRefreshToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
 }
 
 func (s Service) Update(ctx context.Context, id uuid.UUID, isAvailable bool) (Jwt, error) {
@@ -144,44 +316,112 @@ func (s Service) Update(ctx context.Context, id uuid.UUID, isAvailable bool) (Jw
 }
 
 // IsAvailable [MODIFIED] 此函數現在實現了 Refresh Token 的輪換邏輯
-func (s Service) IsAvailable(ctx context.Context, id uuid.UUID) (Jwt, error) {
-	result, err := s.queries.IsAvailable(ctx, id)
+//
+// Every rotated token belongs to the family of the login it descends from.
+// A token that was already used being presented again means it leaked, so
+// the whole family is revoked and both the thief and the user have to sign
+// in again.
+func (s Service) IsAvailable(ctx context.Context, token string) (RefreshToken, error) {
+	result, err := s.queries.GetByTokenHash(ctx, hashRefreshToken(token))
 	if err != nil {
-		s.logger.Error("Failed to query jwt", zap.String("jwt_id", id.String()), zap.Error(err))
-		return Jwt{}, err // 可能是 pgx.ErrNoRows，表示 token 不存在
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("Unknown refresh token")
+			return RefreshToken{}, ErrRefreshTokenInvalid
+		}
+		s.logger.Error("Failed to query jwt", zap.Error(err))
+		return RefreshToken{}, err
+	}
+	id := result.ID
+
+	// tokens revoked by logging out are no sign of theft
+	if result.RevokedAt.Valid {
+		s.logger.Warn("Refresh token revoked", zap.String("jwt_id", id.String()))
+		return RefreshToken{}, ErrRefreshTokenRevoked
 	}
 
 	// 檢查1：是否已被使用
 	if !result.IsAvailable {
-		s.logger.Warn("Refresh token already used", zap.String("jwt_id", id.String()))
-		return Jwt{}, errors.New("refresh token already used")
+		s.revokeFamily(ctx, result)
+		return RefreshToken{}, ErrRefreshTokenReused
 	}
 
 	// 檢查2：是否已過期
+	// expired tokens are not marked as used, or presenting one again would
+	// look like reuse
 	if result.ExpirationTime.Time.Before(time.Now()) {
 		s.logger.Warn("Refresh token expired", zap.String("jwt_id", id.String()), zap.Time("expiration_time", result.ExpirationTime.Time))
-		// 將過期的 token 標記為不可用
-		_, updateErr := s.Update(ctx, id, false)
-		if updateErr != nil {
-			s.logger.Error("Failed to invalidate expired token", zap.Error(updateErr))
-		}
-		return Jwt{}, errors.New("refresh token expired")
+		return RefreshToken{}, ErrRefreshTokenExpired
 	}
 
 	// Token 有效，將其標記為不可用（僅限一次使用）
-	_, err = s.Update(ctx, id, false)
+	// only one of concurrent refreshes with the same token consumes it, the
+	// others are reuse
+	rows, err := s.queries.Consume(ctx, id)
 	if err != nil {
 		s.logger.Error("Failed to invalidate token during rotation", zap.String("jwt_id", id.String()), zap.Error(err))
-		return Jwt{}, err
+		return RefreshToken{}, err
+	}
+	if rows == 0 {
+		s.revokeFamily(ctx, result)
+		return RefreshToken{}, ErrRefreshTokenReused
 	}
 
 	// 建立一個新的 Refresh Token（30 分鐘後過期）
-	newJwt, err := s.Create(ctx, result.UserID, pgtype.Timestamptz{Time: time.Now().Add(30 * time.Minute), Valid: true})
+	newToken, err := s.issue(ctx, CreateParams{
+		UserID:         result.UserID,
+		ExpirationTime: pgtype.Timestamptz{Time: time.Now().Add(30 * time.Minute), Valid: true},
+		FamilyID:       result.FamilyID,
+		ParentID:       pgtype.UUID{Bytes: result.ID, Valid: true},
+	})
 	if err != nil {
 		s.logger.Error("Failed to create new refresh token during rotation", zap.Error(err))
-		return Jwt{}, err
+		return RefreshToken{}, err
 	}
 
 	// 返回 *新的* Refresh Token
-	return newJwt, nil
+	return newToken, nil
+}
+
+// revokeFamily revokes every token of the family the reused token belongs
+// to. Failing to do so is logged, the reused token is rejected either way.
+func (s Service) revokeFamily(ctx context.Context, reused Jwt) {
+	revoked, err := s.queries.RevokeFamily(ctx, reused.FamilyID)
+	if err != nil {
+		s.logger.Error("Failed to revoke refresh token family after reuse", zap.String("jwt_id", reused.ID.String()), zap.String("family_id", reused.FamilyID.String()), zap.Error(err))
+		return
+	}
+
+	s.logger.Warn("Security event: refresh token reused, revoked its token family",
+		zap.String("event", "refresh_token_reuse"),
+		zap.String("jwt_id", reused.ID.String()),
+		zap.String("family_id", reused.FamilyID.String()),
+		zap.String("user_id", reused.UserID.String()),
+		zap.Int64("revoked", revoked),
+	)
+}
+
+// RevokeFamily revokes the refresh token family of a session, which ends it.
+func (s Service) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	revoked, err := s.queries.RevokeFamily(ctx, familyID)
+	if err != nil {
+		s.logger.Error("Failed to revoke refresh token family", zap.String("family_id", familyID.String()), zap.Error(err))
+		return err
+	}
+
+	s.logger.Info("Revoked refresh token family", zap.String("family_id", familyID.String()), zap.Int64("revoked", revoked))
+
+	return nil
+}
+
+// RevokeRefreshTokens revokes every refresh token of the user.
+func (s Service) RevokeRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	revoked, err := s.queries.RevokeUserRefreshTokens(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to revoke refresh tokens", zap.String("user_id", userID.String()), zap.Error(err))
+		return err
+	}
+
+	s.logger.Info("Revoked refresh tokens", zap.String("user_id", userID.String()), zap.Int64("revoked", revoked))
+
+	return nil
 }
@@ -0,0 +1,112 @@
+package jwt_test
+
+import (
+	"awesomeProject/internal/jwt"
+	"awesomeProject/internal/jwt/mocks"
+	"context"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_IsAvailable(t *testing.T) {
+	testUserID := uuid.New()
+	familyID := uuid.New()
+	future := pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true}
+	past := pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+
+	hash := func(token string) []byte {
+		sum := sha256.Sum256([]byte(token))
+		return sum[:]
+	}
+
+	unused := jwt.Jwt{ID: uuid.New(), UserID: testUserID, ExpirationTime: future, IsAvailable: true, FamilyID: familyID}
+	used := jwt.Jwt{ID: uuid.New(), UserID: testUserID, ExpirationTime: future, IsAvailable: false, FamilyID: familyID}
+	loggedOut := jwt.Jwt{ID: uuid.New(), UserID: testUserID, ExpirationTime: future, IsAvailable: false, FamilyID: familyID, RevokedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}
+	expired := jwt.Jwt{ID: uuid.New(), UserID: testUserID, ExpirationTime: past, IsAvailable: true, FamilyID: familyID}
+
+	tests := []struct {
+		name        string
+		token       string
+		setMock     func(querier *mocks.Querier)
+		expectError error
+	}{
+		{
+			name:  "Rotation keeps the family",
+			token: "unused",
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetByTokenHash", mock.Anything, hash("unused")).Return(unused, nil)
+				querier.On("Consume", mock.Anything, unused.ID).Return(int64(1), nil)
+				querier.On("Create", mock.Anything, mock.MatchedBy(func(arg jwt.CreateParams) bool {
+					return arg.UserID == testUserID && arg.FamilyID == familyID &&
+						arg.ParentID == pgtype.UUID{Bytes: unused.ID, Valid: true} && len(arg.TokenHash) == sha256.Size
+				})).Return(jwt.Jwt{ID: uuid.New(), UserID: testUserID, FamilyID: familyID}, nil)
+			},
+		},
+		{
+			name:  "Reuse revokes the family",
+			token: "used",
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetByTokenHash", mock.Anything, hash("used")).Return(used, nil)
+				querier.On("RevokeFamily", mock.Anything, familyID).Return(int64(1), nil)
+			},
+			expectError: jwt.ErrRefreshTokenReused,
+		},
+		{
+			name:  "Logged out token is not reuse",
+			token: "logged-out",
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetByTokenHash", mock.Anything, hash("logged-out")).Return(loggedOut, nil)
+			},
+			expectError: jwt.ErrRefreshTokenRevoked,
+		},
+		{
+			name:  "Concurrent use of the same token is reuse",
+			token: "unused",
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetByTokenHash", mock.Anything, hash("unused")).Return(unused, nil)
+				querier.On("Consume", mock.Anything, unused.ID).Return(int64(0), nil)
+				querier.On("RevokeFamily", mock.Anything, familyID).Return(int64(1), nil)
+			},
+			expectError: jwt.ErrRefreshTokenReused,
+		},
+		{
+			name:  "Expired token is not reuse",
+			token: "expired",
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetByTokenHash", mock.Anything, hash("expired")).Return(expired, nil)
+			},
+			expectError: jwt.ErrRefreshTokenExpired,
+		},
+		{
+			name:  "Row IDs are not tokens",
+			token: unused.ID.String(),
+			setMock: func(querier *mocks.Querier) {
+				querier.On("GetByTokenHash", mock.Anything, hash(unused.ID.String())).Return(jwt.Jwt{}, pgx.ErrNoRows)
+			},
+			expectError: jwt.ErrRefreshTokenInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			tt.setMock(querier)
+
+			s := jwt.NewService(zaptest.NewLogger(t), time.Minute, querier, jwt.KeySet{})
+			result, err := s.IsAvailable(context.Background(), tt.token)
+			assert.ErrorIs(t, err, tt.expectError)
+			if tt.expectError == nil {
+				assert.Equal(t, familyID, result.FamilyID)
+				assert.Len(t, result.Token, 43, "32 random bytes")
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidLimit  = errors.New("invalid limit")
+)
+
+// Cursor points at the last item of a page. Key is the value of the sort
+// column of that item and ID breaks ties between items with the same key.
+// Sort records the ordering the cursor was created for, so a cursor can't be
+// reused with a different ordering.
+type Cursor struct {
+	Sort string    `json:"s,omitempty"`
+	Key  string    `json:"k"`
+	ID   uuid.UUID `json:"id"`
+}
+
+// Params is the requested page, parsed from the limit and cursor query parameters.
+type Params struct {
+	Limit  int32
+	Cursor *Cursor
+}
+
+// Encode returns the opaque representation of the cursor handed to clients.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(value string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	err = json.Unmarshal(data, &cursor)
+	if err != nil || cursor.ID == uuid.Nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// ParseParams reads limit and cursor from the query. A missing limit falls
+// back to DefaultLimit and a missing cursor means the first page.
+func ParseParams(query url.Values) (Params, error) {
+	params := Params{Limit: DefaultLimit}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return Params{}, ErrInvalidLimit
+		}
+		params.Limit = int32(limit)
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		cursor, err := DecodeCursor(value)
+		if err != nil {
+			return Params{}, err
+		}
+		params.Cursor = &cursor
+	}
+
+	return params, nil
+}
+
+// FetchLimit is the number of rows to query. One extra row is fetched to
+// know whether there is a next page.
+func (p Params) FetchLimit() int32 {
+	return p.Limit + 1
+}
+
+// CursorFor checks that the cursor, if any, was created for the given sort.
+func (p Params) CursorFor(sort string) (*Cursor, error) {
+	if p.Cursor == nil {
+		return nil, nil
+	}
+	if p.Cursor.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+	return p.Cursor, nil
+}
+
+// Trim drops the extra row fetched with FetchLimit and returns the encoded
+// cursor of the last item, or an empty string when this is the last page.
+func Trim[T any](items []T, params Params, cursorOf func(T) Cursor) ([]T, string) {
+	if len(items) <= int(params.Limit) {
+		return items, ""
+	}
+
+	items = items[:params.Limit]
+	return items, cursorOf(items[len(items)-1]).Encode()
+}
@@ -0,0 +1,75 @@
+package pagination_test
+
+import (
+	"awesomeProject/internal/pagination"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseParams(t *testing.T) {
+	cursor := pagination.Cursor{Sort: "created_at:desc", Key: "2024-01-01T00:00:00Z", ID: uuid.New()}
+	tests := []struct {
+		name        string
+		query       url.Values
+		expect      pagination.Params
+		expectError error
+	}{
+		{
+			name:   "Defaults",
+			query:  url.Values{},
+			expect: pagination.Params{Limit: pagination.DefaultLimit},
+		},
+		{
+			name:   "Limit and cursor",
+			query:  url.Values{"limit": {"5"}, "cursor": {cursor.Encode()}},
+			expect: pagination.Params{Limit: 5, Cursor: &cursor},
+		},
+		{
+			name:        "Limit above maximum",
+			query:       url.Values{"limit": {"1000"}},
+			expectError: pagination.ErrInvalidLimit,
+		},
+		{
+			name:        "Limit is not a number",
+			query:       url.Values{"limit": {"ten"}},
+			expectError: pagination.ErrInvalidLimit,
+		},
+		{
+			name:        "Malformed cursor",
+			query:       url.Values{"cursor": {"not a cursor"}},
+			expectError: pagination.ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := pagination.ParseParams(tt.query)
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expect, params)
+		})
+	}
+}
+
+func TestTrim(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	cursorOf := func(id uuid.UUID) pagination.Cursor {
+		return pagination.Cursor{ID: id}
+	}
+
+	items, next := pagination.Trim(ids, pagination.Params{Limit: 3}, cursorOf)
+	assert.Len(t, items, 3)
+	assert.Empty(t, next)
+
+	items, next = pagination.Trim(ids, pagination.Params{Limit: 2}, cursorOf)
+	assert.Len(t, items, 2)
+	decoded, err := pagination.DecodeCursor(next)
+	assert.NoError(t, err)
+	assert.Equal(t, ids[1], decoded.ID)
+}
@@ -0,0 +1,280 @@
+package response
+
+import (
+	"awesomeProject/internal/form"
+	"awesomeProject/internal/xlsx"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrInvalidFormat = errors.New("unknown export format")
+
+type ExportFormat string
+
+const (
+	ExportCSV   ExportFormat = "csv"
+	ExportXLSX  ExportFormat = "xlsx"
+	ExportJSONL ExportFormat = "jsonl"
+)
+
+func (f ExportFormat) IsValid() bool {
+	return f == ExportCSV || f == ExportXLSX || f == ExportJSONL
+}
+
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportCSV:
+		return "text/csv; charset=utf-8"
+	case ExportXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case ExportJSONL:
+		return "application/jsonl; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
+
+// exportResponses is not part of queries.sql because sqlc can only return
+// whole result sets, while exports have to stream.
+const exportResponses = `
+SELECT r.id, r.respondent_id, r.submitted_at,
+       COALESCE((SELECT jsonb_object_agg(a.question_id, a.value) FROM answers a WHERE a.response_id = r.id), '{}')::jsonb AS answers
+FROM form_responses r
+WHERE r.form_id = $1
+ORDER BY r.submitted_at, r.id
+`
+
+// ExportRow is a response with its answers as a JSON object keyed by
+// question ID.
+type ExportRow struct {
+	ID           uuid.UUID
+	RespondentID pgtype.UUID
+	SubmittedAt  pgtype.Timestamptz
+	Answers      []byte
+}
+
+// ExportResponses calls yield for every response of the form, oldest first,
+// while the rows are read from the connection. It stops at the first error
+// returned by yield.
+func (q *Queries) ExportResponses(ctx context.Context, formID uuid.UUID, yield func(ExportRow) error) error {
+	rows, err := q.db.Query(ctx, exportResponses, formID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var i ExportRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RespondentID,
+			&i.SubmittedAt,
+			&i.Answers,
+		); err != nil {
+			return err
+		}
+		if err := yield(i); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// exportColumns are the leading columns of every export, followed by one
+// column per question.
+var exportColumns = []string{"response_id", "respondent_id", "submitted_at"}
+
+// exporter writes rows in one format. values holds the raw answer per
+// question column and is nil where the question was not answered.
+type exporter interface {
+	writeHeader(columns []string) error
+	writeRow(fields []string, values []json.RawMessage) error
+	close() error
+}
+
+func newExporter(format ExportFormat, w io.Writer) (exporter, error) {
+	switch format {
+	case ExportCSV:
+		return &csvExporter{w: csv.NewWriter(w)}, nil
+	case ExportXLSX:
+		sheet, err := xlsx.NewWriter(w, "Responses")
+		if err != nil {
+			return nil, err
+		}
+		return &xlsxExporter{w: sheet}, nil
+	case ExportJSONL:
+		return &jsonlExporter{w: w}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrInvalidFormat, format)
+}
+
+// questionColumns titles the question columns. Questions with the same title
+// get a number appended, so every column name is unique.
+func questionColumns(questions []form.Question) []string {
+	columns := make([]string, 0, len(questions))
+	used := make(map[string]bool, len(questions)+len(exportColumns))
+	for _, column := range exportColumns {
+		used[column] = true
+	}
+	for _, question := range questions {
+		title := question.Title
+		for n := 2; used[title]; n++ {
+			title = fmt.Sprintf("%s (%d)", question.Title, n)
+		}
+		used[title] = true
+		columns = append(columns, title)
+	}
+	return columns
+}
+
+// flatten turns an answer into the text of a spreadsheet cell. Multiple
+// choice answers are joined with "; ".
+func flatten(value json.RawMessage) string {
+	if form.IsEmptyAnswer(value) {
+		return ""
+	}
+
+	var text string
+	if json.Unmarshal(value, &text) == nil {
+		return text
+	}
+	var number float64
+	if json.Unmarshal(value, &number) == nil {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	var choices []string
+	if json.Unmarshal(value, &choices) == nil {
+		return strings.Join(choices, "; ")
+	}
+	return string(value)
+}
+
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func (e *csvExporter) writeHeader(columns []string) error {
+	return e.w.Write(columns)
+}
+
+func (e *csvExporter) writeRow(fields []string, values []json.RawMessage) error {
+	record := make([]string, 0, len(fields)+len(values))
+	record = append(record, fields...)
+	for _, value := range values {
+		var number float64
+		if json.Unmarshal(value, &number) == nil {
+			record = append(record, flatten(value))
+			continue
+		}
+		record = append(record, neutralizeFormula(flatten(value)))
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvExporter) close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// neutralizeFormula keeps spreadsheet programs from running text answers
+// that look like formulas when the CSV file is opened.
+func neutralizeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+type xlsxExporter struct {
+	w *xlsx.Writer
+}
+
+func (e *xlsxExporter) writeHeader(columns []string) error {
+	cells := make([]xlsx.Cell, 0, len(columns))
+	for _, column := range columns {
+		cells = append(cells, xlsx.String(column))
+	}
+	return e.w.WriteRow(cells)
+}
+
+func (e *xlsxExporter) writeRow(fields []string, values []json.RawMessage) error {
+	cells := make([]xlsx.Cell, 0, len(fields)+len(values))
+	for _, field := range fields {
+		cells = append(cells, xlsx.String(field))
+	}
+	for _, value := range values {
+		var number float64
+		if json.Unmarshal(value, &number) == nil {
+			cells = append(cells, xlsx.Number(number))
+			continue
+		}
+		cells = append(cells, xlsx.String(flatten(value)))
+	}
+	return e.w.WriteRow(cells)
+}
+
+func (e *xlsxExporter) close() error {
+	return e.w.Close()
+}
+
+// jsonlExporter writes one JSON object per response. Answers keep their JSON
+// type, so multiple choice answers stay arrays and unanswered questions are
+// null.
+type jsonlExporter struct {
+	w       io.Writer
+	columns []string
+	buf     bytes.Buffer
+}
+
+func (e *jsonlExporter) writeHeader(columns []string) error {
+	e.columns = columns
+	return nil
+}
+
+func (e *jsonlExporter) writeRow(fields []string, values []json.RawMessage) error {
+	e.buf.Reset()
+	e.buf.WriteByte('{')
+	for i, column := range e.columns {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return err
+		}
+		e.buf.Write(key)
+		e.buf.WriteByte(':')
+
+		switch {
+		case i < len(fields):
+			value, err := json.Marshal(fields[i])
+			if err != nil {
+				return err
+			}
+			e.buf.Write(value)
+		case form.IsEmptyAnswer(values[i-len(fields)]):
+			e.buf.WriteString("null")
+		default:
+			err := json.Compact(&e.buf, values[i-len(fields)])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	e.buf.WriteString("}\n")
+
+	_, err := e.w.Write(e.buf.Bytes())
+	return err
+}
+
+func (e *jsonlExporter) close() error {
+	return nil
+}
@@ -0,0 +1,666 @@
+package response
+
+import (
+	"awesomeProject/internal/form"
+	"awesomeProject/internal/jwt"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type SubmitRequest struct {
+	Answers map[string]json.RawMessage `json:"answers" validate:"required"`
+}
+
+type GradeRequest struct {
+	Points *float64 `json:"points" validate:"required,min=0"`
+}
+
+// Response leaves out respondent_id for anonymous responses.
+type Response struct {
+	ID           string                     `json:"id"`
+	FormID       string                     `json:"form_id"`
+	RespondentID string                     `json:"respondent_id,omitempty"`
+	SubmittedAt  time.Time                  `json:"submittedAt"`
+	Answers      map[string]json.RawMessage `json:"answers,omitempty"`
+}
+
+type ScoreResponse struct {
+	ResponseID string                  `json:"response_id"`
+	Points     float64                 `json:"points"`
+	MaxPoints  float64                 `json:"max_points"`
+	Pending    int                     `json:"pending"`
+	Revealed   bool                    `json:"answers_revealed"`
+	Questions  []QuestionScoreResponse `json:"questions"`
+}
+
+type QuestionScoreResponse struct {
+	QuestionID    string          `json:"question_id"`
+	Answer        json.RawMessage `json:"answer"`
+	Points        *float64        `json:"points"`
+	MaxPoints     float64         `json:"max_points"`
+	CorrectAnswer json.RawMessage `json:"correct_answer,omitempty"`
+}
+
+type GradeResponse struct {
+	ResponseID string    `json:"response_id"`
+	QuestionID string    `json:"question_id"`
+	Points     float64   `json:"points"`
+	GradedBy   string    `json:"graded_by"`
+	GradedAt   time.Time `json:"gradedAt"`
+}
+
+type GradebookResponse struct {
+	MaxPoints    float64                 `json:"max_points"`
+	AverageScore float64                 `json:"average_score"`
+	Attempts     []AttemptResponse       `json:"attempts"`
+	Questions    []QuestionStatsResponse `json:"questions"`
+}
+
+type AttemptResponse struct {
+	ResponseID   string    `json:"response_id"`
+	RespondentID string    `json:"respondent_id,omitempty"`
+	SubmittedAt  time.Time `json:"submittedAt"`
+	Score        float64   `json:"score"`
+	Pending      int64     `json:"pending"`
+}
+
+// QuestionStatsResponse describes how hard a question was. correct_rate is
+// the share of attempts with full marks and null for questions without points.
+type QuestionStatsResponse struct {
+	QuestionID    string   `json:"question_id"`
+	MaxPoints     float64  `json:"max_points"`
+	Answered      int64    `json:"answered"`
+	Graded        int64    `json:"graded"`
+	AveragePoints float64  `json:"average_points"`
+	FullMarks     int64    `json:"full_marks"`
+	CorrectRate   *float64 `json:"correct_rate"`
+}
+
+type SummaryResponse struct {
+	Responses int64                     `json:"responses"`
+	PerDay    []DayCountResponse        `json:"per_day"`
+	Questions []QuestionSummaryResponse `json:"questions"`
+}
+
+type DayCountResponse struct {
+	Day   string `json:"day"`
+	Count int64  `json:"count"`
+}
+
+// QuestionSummaryResponse holds choices for choice questions, a histogram for
+// number and date questions and words for text questions.
+type QuestionSummaryResponse struct {
+	QuestionID string             `json:"question_id"`
+	Title      string             `json:"title"`
+	Type       string             `json:"type"`
+	Answered   int64              `json:"answered"`
+	Choices    []CountResponse    `json:"choices,omitempty"`
+	Histogram  *HistogramResponse `json:"histogram,omitempty"`
+	Words      []CountResponse    `json:"words,omitempty"`
+}
+
+// CountResponse counts a choice or a word.
+type CountResponse struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// HistogramResponse gives the bounds of date questions as dates, the bounds
+// of number questions as numbers.
+type HistogramResponse struct {
+	Min     any              `json:"min"`
+	Max     any              `json:"max"`
+	Mean    any              `json:"mean"`
+	Buckets []BucketResponse `json:"buckets"`
+}
+
+type BucketResponse struct {
+	From  any   `json:"from"`
+	To    any   `json:"to"`
+	Count int64 `json:"count"`
+}
+
+type ValidationErrorResponse struct {
+	Message string            `json:"message"`
+	Errors  map[string]string `json:"errors"`
+}
+
+type Store interface {
+	Submit(ctx context.Context, formID, respondentID uuid.UUID, answers map[uuid.UUID]json.RawMessage) (FormResponse, error)
+	List(ctx context.Context, formID, userID uuid.UUID) ([]FormResponse, error)
+	Get(ctx context.Context, formID, responseID, userID uuid.UUID) (FormResponse, []Answer, error)
+	Score(ctx context.Context, formID, responseID, userID uuid.UUID) (Score, error)
+	Grade(ctx context.Context, formID, responseID, questionID, graderID uuid.UUID, points float64) (Answer, error)
+	Gradebook(ctx context.Context, formID uuid.UUID) (Gradebook, error)
+	Export(ctx context.Context, formID uuid.UUID, format ExportFormat, w io.Writer) error
+	Summary(ctx context.Context, formID uuid.UUID) (Summary, error)
+}
+
+type Handler struct {
+	logger    *zap.Logger
+	validator *validator.Validate
+	store     Store
+}
+
+func NewHandler(logger *zap.Logger, validator *validator.Validate, store Store) *Handler {
+	return &Handler{
+		logger:    logger,
+		validator: validator,
+		store:     store,
+	}
+}
+
+func (h *Handler) Submit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	var req SubmitRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// validate request
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.logger.Error("Validation failed", zap.Error(err))
+		http.Error(w, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	answers := make(map[uuid.UUID]json.RawMessage, len(req.Answers))
+	for key, value := range req.Answers {
+		questionID, err := uuid.Parse(key)
+		if err != nil {
+			h.logger.Warn("Invalid question ID in answers", zap.String("question_id", key))
+			http.Error(w, "Invalid question ID", http.StatusBadRequest)
+			return
+		}
+		answers[questionID] = value
+	}
+
+	userID, ok := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	if !ok {
+		h.logger.Error("Failed to get user ID from context")
+		http.Error(w, "Invalid user context", http.StatusInternalServerError)
+		return
+	}
+
+	submitted, err := h.store.Submit(ctx, formID, userID, answers)
+	if err != nil {
+		h.writeError(w, "Failed to submit response", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(newResponse(submitted, nil))
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	if !ok {
+		h.logger.Error("Failed to get user ID from context")
+		http.Error(w, "Invalid user context", http.StatusInternalServerError)
+		return
+	}
+
+	responses, err := h.store.List(ctx, formID, userID)
+	if err != nil {
+		h.writeError(w, "Failed to list responses", err)
+		return
+	}
+
+	resp := make([]Response, 0, len(responses))
+	for _, item := range responses {
+		resp = append(resp, newResponse(item, nil))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+	responseID, err := uuid.Parse(r.PathValue("responseId"))
+	if err != nil {
+		h.logger.Warn("Invalid response ID", zap.String("response_id", r.PathValue("responseId")))
+		http.Error(w, "Invalid response ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	if !ok {
+		h.logger.Error("Failed to get user ID from context")
+		http.Error(w, "Invalid user context", http.StatusInternalServerError)
+		return
+	}
+
+	submitted, answers, err := h.store.Get(ctx, formID, responseID, userID)
+	if err != nil {
+		h.writeError(w, "Failed to get response", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(newResponse(submitted, answers))
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// Score returns the score of a quiz attempt to the respondent or a collaborator of the form.
+func (h *Handler) Score(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+	responseID, err := uuid.Parse(r.PathValue("responseId"))
+	if err != nil {
+		h.logger.Warn("Invalid response ID", zap.String("response_id", r.PathValue("responseId")))
+		http.Error(w, "Invalid response ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	if !ok {
+		h.logger.Error("Failed to get user ID from context")
+		http.Error(w, "Invalid user context", http.StatusInternalServerError)
+		return
+	}
+
+	score, err := h.store.Score(ctx, formID, responseID, userID)
+	if err != nil {
+		h.writeError(w, "Failed to get score", err)
+		return
+	}
+
+	resp := ScoreResponse{
+		ResponseID: score.Response.ID.String(),
+		Points:     score.Points,
+		MaxPoints:  score.MaxPoints,
+		Pending:    score.Pending,
+		Revealed:   score.Revealed,
+		Questions:  make([]QuestionScoreResponse, 0, len(score.Questions)),
+	}
+	for _, item := range score.Questions {
+		resp.Questions = append(resp.Questions, QuestionScoreResponse{
+			QuestionID:    item.QuestionID.String(),
+			Answer:        item.Answer,
+			Points:        item.Points,
+			MaxPoints:     item.MaxPoints,
+			CorrectAnswer: item.CorrectAnswer,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// Grade sets the points of one answer by hand.
+func (h *Handler) Grade(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+	responseID, err := uuid.Parse(r.PathValue("responseId"))
+	if err != nil {
+		h.logger.Warn("Invalid response ID", zap.String("response_id", r.PathValue("responseId")))
+		http.Error(w, "Invalid response ID", http.StatusBadRequest)
+		return
+	}
+	questionID, err := uuid.Parse(r.PathValue("questionId"))
+	if err != nil {
+		h.logger.Warn("Invalid question ID", zap.String("question_id", r.PathValue("questionId")))
+		http.Error(w, "Invalid question ID", http.StatusBadRequest)
+		return
+	}
+
+	var req GradeRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// validate request
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.logger.Error("Validation failed", zap.Error(err))
+		http.Error(w, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	if !ok {
+		h.logger.Error("Failed to get user ID from context")
+		http.Error(w, "Invalid user context", http.StatusInternalServerError)
+		return
+	}
+
+	answer, err := h.store.Grade(ctx, formID, responseID, questionID, userID, *req.Points)
+	if err != nil {
+		h.writeError(w, "Failed to grade answer", err)
+		return
+	}
+
+	resp := GradeResponse{
+		ResponseID: answer.ResponseID.String(),
+		QuestionID: answer.QuestionID.String(),
+		Points:     answer.Points.Float64,
+		GradedBy:   userID.String(),
+		GradedAt:   answer.GradedAt.Time,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// Gradebook returns the scores of all attempts of a quiz and per-question statistics.
+func (h *Handler) Gradebook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	gradebook, err := h.store.Gradebook(ctx, formID)
+	if err != nil {
+		h.writeError(w, "Failed to get gradebook", err)
+		return
+	}
+
+	resp := GradebookResponse{
+		MaxPoints:    gradebook.MaxPoints,
+		AverageScore: gradebook.AverageScore,
+		Attempts:     make([]AttemptResponse, 0, len(gradebook.Attempts)),
+		Questions:    make([]QuestionStatsResponse, 0, len(gradebook.Questions)),
+	}
+	for _, attempt := range gradebook.Attempts {
+		resp.Attempts = append(resp.Attempts, AttemptResponse{
+			ResponseID:   attempt.ID.String(),
+			RespondentID: respondentString(attempt.RespondentID),
+			SubmittedAt:  attempt.SubmittedAt.Time,
+			Score:        attempt.Score,
+			Pending:      attempt.Pending,
+		})
+	}
+	for _, stats := range gradebook.Questions {
+		resp.Questions = append(resp.Questions, QuestionStatsResponse{
+			QuestionID:    stats.QuestionID.String(),
+			MaxPoints:     stats.MaxPoints,
+			Answered:      stats.Answered,
+			Graded:        stats.Graded,
+			AveragePoints: stats.AveragePoints,
+			FullMarks:     stats.FullMarks,
+			CorrectRate:   stats.CorrectRate,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// Summary returns per-question aggregates of all responses of a form.
+func (h *Handler) Summary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.store.Summary(ctx, formID)
+	if err != nil {
+		h.writeError(w, "Failed to summarize responses", err)
+		return
+	}
+
+	resp := SummaryResponse{
+		Responses: summary.Responses,
+		PerDay:    make([]DayCountResponse, 0, len(summary.PerDay)),
+		Questions: make([]QuestionSummaryResponse, 0, len(summary.Questions)),
+	}
+	for _, day := range summary.PerDay {
+		resp.PerDay = append(resp.PerDay, DayCountResponse{
+			Day:   day.Day.Format(form.DateLayout),
+			Count: day.Count,
+		})
+	}
+	for _, question := range summary.Questions {
+		item := QuestionSummaryResponse{
+			QuestionID: question.QuestionID.String(),
+			Title:      question.Title,
+			Type:       string(question.Type),
+			Answered:   question.Answered,
+			Histogram:  newHistogramResponse(question.Type, question.Histogram),
+		}
+		if question.Choices != nil {
+			item.Choices = make([]CountResponse, 0, len(question.Choices))
+			for _, choice := range question.Choices {
+				item.Choices = append(item.Choices, CountResponse{Value: choice.Choice, Count: choice.Count})
+			}
+		}
+		if question.Words != nil {
+			item.Words = make([]CountResponse, 0, len(question.Words))
+			for _, word := range question.Words {
+				item.Words = append(item.Words, CountResponse{Value: word.Word, Count: word.Count})
+			}
+		}
+		resp.Questions = append(resp.Questions, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// Export downloads all responses of a form as csv (the default), xlsx or jsonl, see Service.Export.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	format := ExportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = ExportCSV
+	}
+	if !format.IsValid() {
+		h.logger.Warn("Invalid export format", zap.String("format", string(format)))
+		http.Error(w, "Invalid format, use csv, xlsx or jsonl", http.StatusBadRequest)
+		return
+	}
+
+	out := &exportWriter{w: w, format: format, name: "responses-" + formID.String()}
+	err = h.store.Export(ctx, formID, format, out)
+	if err != nil {
+		if out.started {
+			// the status is already sent, the client sees a truncated file
+			h.logger.Error("Failed to export responses", zap.Error(err))
+			return
+		}
+		h.writeError(w, "Failed to export responses", err)
+	}
+}
+
+// exportWriter sends the headers of the download on the first write, so
+// errors that happen before any data can still get a proper status.
+type exportWriter struct {
+	w       http.ResponseWriter
+	format  ExportFormat
+	name    string
+	started bool
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		e.w.Header().Set("Content-Type", e.format.ContentType())
+		e.w.Header().Set("Content-Disposition", `attachment; filename="`+e.name+"."+string(e.format)+`"`)
+		e.w.WriteHeader(http.StatusOK)
+	}
+	return e.w.Write(p)
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, message string, err error) {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		h.logger.Warn(message, zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		encodeErr := json.NewEncoder(w).Encode(ValidationErrorResponse{
+			Message: "Invalid answers",
+			Errors:  validationErr.Fields,
+		})
+		if encodeErr != nil {
+			h.logger.Error("Failed to encode response", zap.Error(encodeErr))
+		}
+	case errors.Is(err, ErrNotFound), errors.Is(err, form.ErrNotFound):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, "Not found", http.StatusNotFound)
+	case errors.Is(err, form.ErrForbidden):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	case errors.Is(err, form.ErrReadOnly):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, "Form is not accepting responses", http.StatusConflict)
+	case errors.Is(err, ErrNotQuiz):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, "Form is not a quiz", http.StatusConflict)
+	case errors.Is(err, ErrInvalidGrade), errors.Is(err, ErrInvalidFormat):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Error(message, zap.Error(err))
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+func newResponse(submitted FormResponse, answers []Answer) Response {
+	resp := Response{
+		ID:           submitted.ID.String(),
+		FormID:       submitted.FormID.String(),
+		RespondentID: respondentString(submitted.RespondentID),
+		SubmittedAt:  submitted.SubmittedAt.Time,
+	}
+
+	if answers != nil {
+		resp.Answers = make(map[string]json.RawMessage, len(answers))
+		for _, answer := range answers {
+			resp.Answers[answer.QuestionID.String()] = answer.Value
+		}
+	}
+
+	return resp
+}
+
+// newHistogramResponse turns the days since 1970-01-01 of date histograms
+// back into dates. A bucket then covers the days from its start up to the
+// start of the next bucket.
+func newHistogramResponse(t form.QuestionType, histogram *Histogram) *HistogramResponse {
+	if histogram == nil {
+		return nil
+	}
+
+	value := func(v float64) any { return v }
+	if t == form.QuestionTypeDate {
+		value = func(v float64) any {
+			return time.Unix(0, 0).UTC().AddDate(0, 0, int(math.Floor(v))).Format(form.DateLayout)
+		}
+	}
+
+	resp := &HistogramResponse{
+		Min:     value(histogram.Min),
+		Max:     value(histogram.Max),
+		Mean:    value(histogram.Mean),
+		Buckets: make([]BucketResponse, 0, len(histogram.Buckets)),
+	}
+	for _, bucket := range histogram.Buckets {
+		resp.Buckets = append(resp.Buckets, BucketResponse{
+			From:  value(bucket.From),
+			To:    value(bucket.To),
+			Count: bucket.Count,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,133 @@
+-- name: Submit :one
+WITH response AS (
+    INSERT INTO form_responses (form_id, respondent_id)
+    VALUES (sqlc.arg(form_id), sqlc.arg(respondent_id))
+    RETURNING *
+), inserted AS (
+    INSERT INTO answers (response_id, question_id, value, points, graded_at)
+    SELECT response.id, a.question_id, a.value::jsonb,
+           CASE WHEN a.graded THEN a.points END,
+           CASE WHEN a.graded THEN now() END
+    FROM response, unnest(sqlc.arg(question_ids)::uuid[], sqlc.arg(answer_values)::text[],
+                          sqlc.arg(answer_points)::float8[], sqlc.arg(answer_graded)::bool[]) AS a (question_id, value, points, graded)
+)
+SELECT * FROM response;
+
+-- name: List :many
+SELECT * FROM form_responses
+WHERE form_id = $1
+ORDER BY submitted_at DESC, id;
+
+-- name: Get :one
+SELECT * FROM form_responses
+WHERE id = $1 AND form_id = $2;
+
+-- name: ListAnswers :many
+SELECT * FROM answers
+WHERE response_id = $1;
+
+
+-- name: GradeAnswer :one
+UPDATE answers
+SET points    = sqlc.arg(points)::float8,
+    graded_by = sqlc.arg(graded_by)::uuid,
+    graded_at = now()
+WHERE response_id = sqlc.arg(response_id) AND question_id = sqlc.arg(question_id)
+RETURNING *;
+
+-- name: ListScores :many
+SELECT r.id, r.respondent_id, r.submitted_at,
+       COALESCE(SUM(a.points), 0)::float8 AS score,
+       COUNT(a.question_id) FILTER (WHERE a.points IS NULL) AS pending
+FROM form_responses r
+LEFT JOIN answers a ON a.response_id = r.id
+WHERE r.form_id = $1
+GROUP BY r.id
+ORDER BY r.submitted_at, r.id;
+
+-- name: QuestionStats :many
+SELECT q.id AS question_id, q.points AS max_points,
+       COUNT(a.response_id) AS answered,
+       COUNT(a.points) AS graded,
+       COALESCE(AVG(a.points), 0)::float8 AS average_points,
+       COUNT(a.points) FILTER (WHERE q.points > 0 AND a.points >= q.points) AS full_marks
+FROM questions q
+LEFT JOIN answers a ON a.question_id = q.id
+WHERE q.form_id = $1
+GROUP BY q.id
+ORDER BY q.position, q.created_at;
+
+-- name: AnswerCounts :many
+SELECT q.id AS question_id, COUNT(a.response_id) AS answered
+FROM questions q
+LEFT JOIN answers a ON a.question_id = q.id
+WHERE q.form_id = $1
+GROUP BY q.id;
+
+-- name: ResponsesPerDay :many
+SELECT (submitted_at AT TIME ZONE 'UTC')::date AS day, COUNT(*) AS count
+FROM form_responses
+WHERE form_id = $1
+GROUP BY day
+ORDER BY day;
+
+-- name: ChoiceCounts :many
+SELECT a.question_id, c.choice::text AS choice, COUNT(*) AS count
+FROM answers a
+JOIN questions q ON q.id = a.question_id
+CROSS JOIN LATERAL jsonb_array_elements_text(
+    CASE WHEN jsonb_typeof(a.value) = 'array' THEN a.value ELSE jsonb_build_array(a.value) END
+) AS c (choice)
+WHERE q.form_id = $1
+  AND q.type IN ('single_choice', 'multiple_choice')
+GROUP BY a.question_id, c.choice
+ORDER BY a.question_id, count DESC, choice;
+
+-- name: Histograms :many
+WITH vals AS (
+    SELECT a.question_id,
+           CASE WHEN q.type = 'date'
+                THEN ((a.value #>> '{}')::date - DATE '1970-01-01')::float8
+                ELSE (a.value #>> '{}')::float8
+           END AS v
+    FROM answers a
+    JOIN questions q ON q.id = a.question_id
+    WHERE q.form_id = sqlc.arg(form_id)
+      AND q.type IN ('number', 'date')
+), bounds AS (
+    SELECT question_id, MIN(v) AS lo, MAX(v) AS hi, AVG(v) AS mean
+    FROM vals
+    GROUP BY question_id
+)
+SELECT vals.question_id, bounds.lo::float8 AS lo, bounds.hi::float8 AS hi, bounds.mean::float8 AS mean,
+       (CASE WHEN bounds.hi = bounds.lo THEN 1
+             ELSE LEAST(width_bucket(vals.v, bounds.lo, bounds.hi, sqlc.arg(buckets)::int), sqlc.arg(buckets)::int)
+        END)::int AS bucket,
+       COUNT(*) AS count
+FROM vals
+JOIN bounds ON bounds.question_id = vals.question_id
+GROUP BY vals.question_id, bounds.lo, bounds.hi, bounds.mean, bucket
+ORDER BY vals.question_id, bucket;
+
+-- name: WordFrequencies :many
+SELECT ranked.question_id, ranked.word::text AS word, ranked.count
+FROM (
+    SELECT a.question_id, w.word, COUNT(*) AS count,
+           ROW_NUMBER() OVER (PARTITION BY a.question_id ORDER BY COUNT(*) DESC, w.word) AS rank
+    FROM answers a
+    JOIN questions q ON q.id = a.question_id
+    CROSS JOIN LATERAL regexp_split_to_table(lower(a.value #>> '{}'), '[^[:alnum:]]+') AS w (word)
+    WHERE q.form_id = sqlc.arg(form_id)
+      AND q.type IN ('short_text', 'paragraph')
+      AND length(w.word) > 1
+      AND NOT w.word = ANY (sqlc.arg(stop_words)::text[])
+    GROUP BY a.question_id, w.word
+) ranked
+WHERE ranked.rank <= sqlc.arg(words_per_question)::int
+ORDER BY ranked.question_id, ranked.count DESC, ranked.word;
+
+-- name: HasRestrictedShareLink :one
+SELECT EXISTS (
+    SELECT 1 FROM form_share_links
+    WHERE form_id = $1 AND (access_code_hash IS NOT NULL OR expires_at IS NOT NULL)
+) AS exists;
\ No newline at end of file
@@ -0,0 +1,129 @@
+package response
+
+import (
+	"awesomeProject/internal/form"
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// Score is the result of one quiz attempt.
+type Score struct {
+	Response  FormResponse
+	Points    float64
+	MaxPoints float64
+	// Pending counts the answers that still have to be graded by hand.
+	Pending int
+	// Revealed is true when the correct answers are included.
+	Revealed  bool
+	Questions []QuestionScore
+}
+
+type QuestionScore struct {
+	QuestionID uuid.UUID
+	// Answer is nil when the question was not answered.
+	Answer json.RawMessage
+	// Points is nil while the answer waits to be graded.
+	Points        *float64
+	MaxPoints     float64
+	CorrectAnswer json.RawMessage
+}
+
+// Gradebook sums up all attempts of a quiz for its owners.
+type Gradebook struct {
+	MaxPoints    float64
+	AverageScore float64
+	Attempts     []ListScoresRow
+	Questions    []QuestionStats
+}
+
+// QuestionStats tells how respondents did on one question. CorrectRate is
+// the share of all attempts that got full marks on it, so lower means harder.
+// It is nil for questions without points.
+type QuestionStats struct {
+	QuestionID    uuid.UUID
+	MaxPoints     float64
+	Answered      int64
+	Graded        int64
+	AveragePoints float64
+	FullMarks     int64
+	CorrectRate   *float64
+}
+
+// scoreOf adds up the graded answers of a response in question order.
+// Unanswered questions score 0.
+func scoreOf(result FormResponse, questions []form.Question, answers []Answer, reveal bool) Score {
+	byQuestion := make(map[uuid.UUID]Answer, len(answers))
+	for _, answer := range answers {
+		byQuestion[answer.QuestionID] = answer
+	}
+
+	score := Score{
+		Response:  result,
+		Revealed:  reveal,
+		Questions: make([]QuestionScore, 0, len(questions)),
+	}
+	for _, question := range questions {
+		item := QuestionScore{
+			QuestionID: question.ID,
+			MaxPoints:  question.Points,
+		}
+		if reveal {
+			item.CorrectAnswer = question.AnswerKey
+		}
+
+		answer, answered := byQuestion[question.ID]
+		switch {
+		case !answered:
+			item.Points = new(float64)
+		case answer.Points.Valid:
+			item.Answer = answer.Value
+			points := answer.Points.Float64
+			item.Points = &points
+			score.Points += points
+		default:
+			item.Answer = answer.Value
+			score.Pending++
+		}
+		score.MaxPoints += question.Points
+		score.Questions = append(score.Questions, item)
+	}
+
+	return score
+}
+
+// gradebookOf combines the scores of all attempts with the statistics per
+// question.
+func gradebookOf(questions []form.Question, scores []ListScoresRow, stats []QuestionStatsRow) Gradebook {
+	gradebook := Gradebook{
+		Attempts:  scores,
+		Questions: make([]QuestionStats, 0, len(stats)),
+	}
+	for _, question := range questions {
+		gradebook.MaxPoints += question.Points
+	}
+	for _, attempt := range scores {
+		gradebook.AverageScore += attempt.Score
+	}
+	if len(scores) > 0 {
+		gradebook.AverageScore /= float64(len(scores))
+	}
+
+	for _, row := range stats {
+		item := QuestionStats{
+			QuestionID:    row.QuestionID,
+			MaxPoints:     row.MaxPoints,
+			Answered:      row.Answered,
+			Graded:        row.Graded,
+			AveragePoints: row.AveragePoints,
+			FullMarks:     row.FullMarks,
+		}
+		if row.MaxPoints > 0 && len(scores) > 0 {
+			rate := float64(row.FullMarks) / float64(len(scores))
+			item.CorrectRate = &rate
+		}
+		gradebook.Questions = append(gradebook.Questions, item)
+	}
+
+	return gradebook
+}
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS form_responses
+(
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    form_id       UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    respondent_id UUID REFERENCES users (id),
+    submitted_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS answers
+(
+    response_id UUID  NOT NULL REFERENCES form_responses (id) ON DELETE CASCADE,
+    question_id UUID  NOT NULL REFERENCES questions (id) ON DELETE CASCADE,
+    value       JSONB NOT NULL,
+    points      DOUBLE PRECISION,
+    graded_by   UUID REFERENCES users (id) ON DELETE SET NULL,
+    graded_at   TIMESTAMPTZ,
+    PRIMARY KEY (response_id, question_id)
+)
\ No newline at end of file
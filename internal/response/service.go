@@ -0,0 +1,518 @@
+package response
+
+import (
+	"awesomeProject/internal/form"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNotFound     = errors.New("response not found")
+	ErrNotQuiz      = errors.New("form is not a quiz")
+	ErrInvalidGrade = errors.New("invalid grade")
+)
+
+// ValidationError lists the answers that were rejected, keyed by question ID.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	keys := make([]string, 0, len(e.Fields))
+	for key := range e.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	messages := make([]string, 0, len(keys))
+	for _, key := range keys {
+		messages = append(messages, fmt.Sprintf("%s: %s", key, e.Fields[key]))
+	}
+	return "invalid answers: " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return form.ErrInvalidAnswer
+}
+
+//go:generate mockery --name=Querier
+type Querier interface {
+	Submit(ctx context.Context, arg SubmitParams) (FormResponse, error)
+	HasRestrictedShareLink(ctx context.Context, formID uuid.UUID) (bool, error)
+	List(ctx context.Context, formID uuid.UUID) ([]FormResponse, error)
+	Get(ctx context.Context, arg GetParams) (FormResponse, error)
+	ListAnswers(ctx context.Context, responseID uuid.UUID) ([]Answer, error)
+	GradeAnswer(ctx context.Context, arg GradeAnswerParams) (Answer, error)
+	ListScores(ctx context.Context, formID uuid.UUID) ([]ListScoresRow, error)
+	QuestionStats(ctx context.Context, formID uuid.UUID) ([]QuestionStatsRow, error)
+	ExportResponses(ctx context.Context, formID uuid.UUID, yield func(ExportRow) error) error
+	ResponsesPerDay(ctx context.Context, formID uuid.UUID) ([]ResponsesPerDayRow, error)
+	AnswerCounts(ctx context.Context, formID uuid.UUID) ([]AnswerCountsRow, error)
+	ChoiceCounts(ctx context.Context, formID uuid.UUID) ([]ChoiceCountsRow, error)
+	Histograms(ctx context.Context, arg HistogramsParams) ([]HistogramsRow, error)
+	WordFrequencies(ctx context.Context, arg WordFrequenciesParams) ([]WordFrequenciesRow, error)
+}
+
+//go:generate mockery --name=FormStore
+type FormStore interface {
+	Authorize(ctx context.Context, formID uuid.UUID, required form.Role) (form.Form, error)
+	Get(ctx context.Context, formID uuid.UUID) (form.Form, error)
+	OpenForResponses(ctx context.Context, formID uuid.UUID) (form.Form, error)
+	GradingQuestions(ctx context.Context, formID uuid.UUID) ([]form.Question, error)
+	ListSections(ctx context.Context, formID uuid.UUID) ([]form.FormSection, error)
+}
+
+type Service struct {
+	logger  *zap.Logger
+	queries Querier
+	forms   FormStore
+	summary *summaryCache
+}
+
+func NewService(logger *zap.Logger, querier Querier, forms FormStore) *Service {
+	return &Service{
+		logger:  logger,
+		queries: querier,
+		forms:   forms,
+		summary: newSummaryCache(),
+	}
+}
+
+// Submit validates the answers against the questions and rules of the form and stores them as one response.
+// Only published forms accept responses. Answers to a quiz are graded right away where the question has an
+// answer key, the others wait for Grade. A form with a share link that asks for an access code or expires only
+// takes responses from its collaborators here, everyone else has to go through the link.
+func (s *Service) Submit(ctx context.Context, formID, respondentID uuid.UUID, answers map[uuid.UUID]json.RawMessage) (FormResponse, error) {
+	restricted, err := s.queries.HasRestrictedShareLink(ctx, formID)
+	if err != nil {
+		s.logger.Error("Failed to check share links", zap.Error(err))
+		return FormResponse{}, err
+	}
+	if restricted {
+		_, err = s.forms.Authorize(ctx, formID, form.RoleViewer)
+		if err != nil {
+			return FormResponse{}, err
+		}
+	}
+
+	return s.submit(ctx, formID, respondentID, answers)
+}
+
+// SubmitShared is Submit for share links, which check the access code and expiry of the link themselves.
+// respondentID is uuid.Nil for anonymous submissions.
+func (s *Service) SubmitShared(ctx context.Context, formID, respondentID uuid.UUID, answers map[uuid.UUID]json.RawMessage) (FormResponse, error) {
+	return s.submit(ctx, formID, respondentID, answers)
+}
+
+func (s *Service) submit(ctx context.Context, formID, respondentID uuid.UUID, answers map[uuid.UUID]json.RawMessage) (FormResponse, error) {
+	f, err := s.forms.OpenForResponses(ctx, formID)
+	if err != nil {
+		return FormResponse{}, err
+	}
+
+	questions, err := s.forms.GradingQuestions(ctx, formID)
+	if err != nil {
+		return FormResponse{}, err
+	}
+	sections, err := s.forms.ListSections(ctx, formID)
+	if err != nil {
+		return FormResponse{}, err
+	}
+
+	questionIDs, values, err := validateAnswers(sections, questions, answers)
+	if err != nil {
+		return FormResponse{}, err
+	}
+	points, graded := gradeAnswers(f, questions, questionIDs, values)
+
+	result, err := s.queries.Submit(ctx, SubmitParams{
+		FormID:       formID,
+		RespondentID: respondentParam(respondentID),
+		QuestionIds:  questionIDs,
+		AnswerValues: values,
+		AnswerPoints: points,
+		AnswerGraded: graded,
+	})
+	if err != nil {
+		s.logger.Error("Failed to submit response", zap.Error(err))
+		return FormResponse{}, err
+	}
+	s.summary.invalidate(formID)
+
+	s.logger.Info("Submitted response", zap.String("form_id", formID.String()), zap.String("response_id", result.ID.String()))
+
+	return result, nil
+}
+
+// respondentParam stores uuid.Nil, the respondent of an anonymous submission, as NULL.
+func respondentParam(respondentID uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: respondentID, Valid: respondentID != uuid.Nil}
+}
+
+// isRespondent reports whether the user submitted the response. Anonymous responses belong to nobody.
+func isRespondent(result FormResponse, userID uuid.UUID) bool {
+	return result.RespondentID.Valid && uuid.UUID(result.RespondentID.Bytes) == userID
+}
+
+// respondentString is the ID of the respondent, or "" for anonymous responses.
+func respondentString(respondentID pgtype.UUID) string {
+	if !respondentID.Valid {
+		return ""
+	}
+	return uuid.UUID(respondentID.Bytes).String()
+}
+
+// List returns every response of a form. Any collaborator of the form may list them.
+func (s *Service) List(ctx context.Context, formID, userID uuid.UUID) ([]FormResponse, error) {
+	_, err := s.forms.Authorize(ctx, formID, form.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.queries.List(ctx, formID)
+	if err != nil {
+		s.logger.Error("Failed to list responses", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Get returns one response with its answers. Collaborators of the form and the respondent may read it.
+func (s *Service) Get(ctx context.Context, formID, responseID, userID uuid.UUID) (FormResponse, []Answer, error) {
+	result, err := s.queries.Get(ctx, GetParams{
+		ID:     responseID,
+		FormID: formID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return FormResponse{}, nil, ErrNotFound
+		}
+		s.logger.Error("Failed to get response", zap.Error(err))
+		return FormResponse{}, nil, err
+	}
+
+	if !isRespondent(result, userID) {
+		_, err = s.forms.Authorize(ctx, formID, form.RoleViewer)
+		if err != nil {
+			return FormResponse{}, nil, err
+		}
+	}
+
+	answers, err := s.queries.ListAnswers(ctx, responseID)
+	if err != nil {
+		s.logger.Error("Failed to list answers", zap.Error(err))
+		return FormResponse{}, nil, err
+	}
+
+	return result, answers, nil
+}
+
+// Score grades one attempt of a quiz. Collaborators of the form and the respondent may read it. Only
+// editors always see the correct answers, everyone else when the reveal policy of the form allows it.
+func (s *Service) Score(ctx context.Context, formID, responseID, userID uuid.UUID) (Score, error) {
+	result, err := s.queries.Get(ctx, GetParams{
+		ID:     responseID,
+		FormID: formID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Score{}, ErrNotFound
+		}
+		s.logger.Error("Failed to get response", zap.Error(err))
+		return Score{}, err
+	}
+
+	var f form.Form
+	if isRespondent(result, userID) {
+		f, err = s.forms.Get(ctx, formID)
+	} else {
+		f, err = s.forms.Authorize(ctx, formID, form.RoleViewer)
+	}
+	if err != nil {
+		return Score{}, err
+	}
+	if !f.IsQuiz {
+		return Score{}, ErrNotQuiz
+	}
+
+	questions, err := s.forms.GradingQuestions(ctx, formID)
+	if err != nil {
+		return Score{}, err
+	}
+	answers, err := s.queries.ListAnswers(ctx, responseID)
+	if err != nil {
+		s.logger.Error("Failed to list answers", zap.Error(err))
+		return Score{}, err
+	}
+
+	reveal := form.RevealsAnswers(f)
+	if !reveal {
+		_, err = s.forms.Authorize(ctx, formID, form.RoleEditor)
+		if err != nil && !errors.Is(err, form.ErrForbidden) {
+			return Score{}, err
+		}
+		reveal = err == nil
+	}
+	return scoreOf(result, questions, answers, reveal), nil
+}
+
+// Grade sets the points of one answer by hand, usually for text answers without an answer key. It also
+// overrides automatic grades. Editors of the form may grade.
+func (s *Service) Grade(ctx context.Context, formID, responseID, questionID, graderID uuid.UUID, points float64) (Answer, error) {
+	f, err := s.forms.Authorize(ctx, formID, form.RoleEditor)
+	if err != nil {
+		return Answer{}, err
+	}
+	if !f.IsQuiz {
+		return Answer{}, ErrNotQuiz
+	}
+
+	questions, err := s.forms.GradingQuestions(ctx, formID)
+	if err != nil {
+		return Answer{}, err
+	}
+	i := slices.IndexFunc(questions, func(q form.Question) bool { return q.ID == questionID })
+	if i < 0 {
+		return Answer{}, ErrNotFound
+	}
+	if points < 0 || points > questions[i].Points {
+		return Answer{}, fmt.Errorf("%w: points must be between 0 and %g", ErrInvalidGrade, questions[i].Points)
+	}
+
+	_, err = s.queries.Get(ctx, GetParams{
+		ID:     responseID,
+		FormID: formID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Answer{}, ErrNotFound
+		}
+		s.logger.Error("Failed to get response", zap.Error(err))
+		return Answer{}, err
+	}
+
+	result, err := s.queries.GradeAnswer(ctx, GradeAnswerParams{
+		Points:     points,
+		GradedBy:   graderID,
+		ResponseID: responseID,
+		QuestionID: questionID,
+	})
+	if err != nil {
+		// the question was not answered
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Answer{}, ErrNotFound
+		}
+		s.logger.Error("Failed to grade answer", zap.Error(err))
+		return Answer{}, err
+	}
+
+	s.logger.Info("Graded answer", zap.String("response_id", responseID.String()), zap.String("question_id", questionID.String()), zap.Float64("points", points))
+
+	return result, nil
+}
+
+// Gradebook returns the score of every attempt of a quiz and how respondents did on each question. Only
+// owners of the form may read it.
+func (s *Service) Gradebook(ctx context.Context, formID uuid.UUID) (Gradebook, error) {
+	f, err := s.forms.Authorize(ctx, formID, form.RoleOwner)
+	if err != nil {
+		return Gradebook{}, err
+	}
+	if !f.IsQuiz {
+		return Gradebook{}, ErrNotQuiz
+	}
+
+	questions, err := s.forms.GradingQuestions(ctx, formID)
+	if err != nil {
+		return Gradebook{}, err
+	}
+	scores, err := s.queries.ListScores(ctx, formID)
+	if err != nil {
+		s.logger.Error("Failed to list scores", zap.Error(err))
+		return Gradebook{}, err
+	}
+	stats, err := s.queries.QuestionStats(ctx, formID)
+	if err != nil {
+		s.logger.Error("Failed to get question statistics", zap.Error(err))
+		return Gradebook{}, err
+	}
+
+	return gradebookOf(questions, scores, stats), nil
+}
+
+// Summary aggregates the answers to every question of the form: counts per choice, histograms of numbers
+// and dates and the most common words of text answers. Any collaborator of the form may read it. The
+// aggregates are computed by the database and cached until the next response arrives.
+func (s *Service) Summary(ctx context.Context, formID uuid.UUID) (Summary, error) {
+	_, err := s.forms.Authorize(ctx, formID, form.RoleViewer)
+	if err != nil {
+		return Summary{}, err
+	}
+	questions, err := s.forms.GradingQuestions(ctx, formID)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	aggregates, token := s.summary.get(formID)
+	if aggregates == nil {
+		aggregates, err = s.aggregate(ctx, formID)
+		if err != nil {
+			s.logger.Error("Failed to aggregate responses", zap.String("form_id", formID.String()), zap.Error(err))
+			return Summary{}, err
+		}
+		s.summary.put(formID, token, aggregates)
+	}
+
+	return summaryOf(questions, aggregates), nil
+}
+
+func (s *Service) aggregate(ctx context.Context, formID uuid.UUID) (*summaryAggregates, error) {
+	var aggregates summaryAggregates
+	var err error
+	aggregates.perDay, err = s.queries.ResponsesPerDay(ctx, formID)
+	if err != nil {
+		return nil, err
+	}
+	aggregates.answered, err = s.queries.AnswerCounts(ctx, formID)
+	if err != nil {
+		return nil, err
+	}
+	aggregates.choices, err = s.queries.ChoiceCounts(ctx, formID)
+	if err != nil {
+		return nil, err
+	}
+	aggregates.histograms, err = s.queries.Histograms(ctx, HistogramsParams{
+		FormID:  formID,
+		Buckets: histogramBuckets,
+	})
+	if err != nil {
+		return nil, err
+	}
+	aggregates.words, err = s.queries.WordFrequencies(ctx, WordFrequenciesParams{
+		FormID:           formID,
+		StopWords:        stopWords,
+		WordsPerQuestion: wordsPerQuestion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &aggregates, nil
+}
+
+// Export writes every response of the form to w, one row per response and one column per question titled
+// like the question. Rows are streamed from the database as they are written, so memory use does not grow
+// with the number of responses. Nothing is written to w before the caller is known to be an owner.
+func (s *Service) Export(ctx context.Context, formID uuid.UUID, format ExportFormat, w io.Writer) error {
+	if !format.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidFormat, format)
+	}
+
+	_, err := s.forms.Authorize(ctx, formID, form.RoleOwner)
+	if err != nil {
+		return err
+	}
+	questions, err := s.forms.GradingQuestions(ctx, formID)
+	if err != nil {
+		return err
+	}
+
+	out, err := newExporter(format, w)
+	if err != nil {
+		return err
+	}
+	err = out.writeHeader(append(slices.Clone(exportColumns), questionColumns(questions)...))
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	values := make([]json.RawMessage, len(questions))
+	err = s.queries.ExportResponses(ctx, formID, func(row ExportRow) error {
+		var answers map[uuid.UUID]json.RawMessage
+		err := json.Unmarshal(row.Answers, &answers)
+		if err != nil {
+			return fmt.Errorf("decode answers of response %s: %w", row.ID, err)
+		}
+		for i, question := range questions {
+			values[i] = answers[question.ID]
+		}
+		count++
+		return out.writeRow([]string{
+			row.ID.String(),
+			respondentString(row.RespondentID),
+			row.SubmittedAt.Time.UTC().Format(time.RFC3339),
+		}, values)
+	})
+	if err != nil {
+		s.logger.Error("Failed to export responses", zap.String("form_id", formID.String()), zap.Int("written", count), zap.Error(err))
+		return err
+	}
+
+	err = out.close()
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Exported responses", zap.String("form_id", formID.String()), zap.String("format", string(format)), zap.Int("count", count))
+
+	return nil
+}
+
+// validateAnswers checks the answers against the questions and rules of the
+// form, see form.CheckAnswers, and returns the answers that should be stored
+// in question order.
+func validateAnswers(sections []form.FormSection, questions []form.Question, answers map[uuid.UUID]json.RawMessage) ([]uuid.UUID, []string, error) {
+	_, fields, err := form.CheckAnswers(sections, questions, answers)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(fields) > 0 {
+		return nil, nil, &ValidationError{Fields: fields}
+	}
+
+	var questionIDs []uuid.UUID
+	var values []string
+	for _, question := range questions {
+		value, ok := answers[question.ID]
+		if !ok || form.IsEmptyAnswer(value) {
+			continue
+		}
+		questionIDs = append(questionIDs, question.ID)
+		values = append(values, string(value))
+	}
+
+	return questionIDs, values, nil
+}
+
+// gradeAnswers grades the validated answers of a quiz, see form.Question.Grade.
+// Answers to other forms are never graded.
+func gradeAnswers(f form.Form, questions []form.Question, questionIDs []uuid.UUID, values []string) ([]float64, []bool) {
+	points := make([]float64, len(questionIDs))
+	graded := make([]bool, len(questionIDs))
+	if !f.IsQuiz {
+		return points, graded
+	}
+
+	byID := make(map[uuid.UUID]form.Question, len(questions))
+	for _, question := range questions {
+		byID[question.ID] = question
+	}
+	for i, questionID := range questionIDs {
+		points[i], graded[i] = byID[questionID].Grade(json.RawMessage(values[i]))
+	}
+
+	return points, graded
+}
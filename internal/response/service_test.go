@@ -0,0 +1,524 @@
+package response_test
+
+import (
+	"awesomeProject/internal/form"
+	"awesomeProject/internal/response"
+	"awesomeProject/internal/response/mocks"
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_Submit(t *testing.T) {
+	testFormID := uuid.New()
+	testUserID := uuid.New()
+	nameID := uuid.New()
+	colourID := uuid.New()
+	reasonID := uuid.New()
+	questions := []form.Question{
+		{
+			ID:       nameID,
+			FormID:   testFormID,
+			Type:     "short_text",
+			Title:    "Name",
+			Required: true,
+			Settings: []byte(`{}`),
+		},
+		{
+			ID:       colourID,
+			FormID:   testFormID,
+			Type:     "single_choice",
+			Title:    "Favourite colour",
+			Settings: []byte(`{"choices":["red","blue"]}`),
+		},
+		{
+			ID:       reasonID,
+			FormID:   testFormID,
+			Type:     "paragraph",
+			Title:    "Why red?",
+			Settings: []byte(`{}`),
+			Rules:    []byte(`{"show_if":{"question":"` + colourID.String() + `","op":"equals","value":"red"}}`),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		answers     map[uuid.UUID]json.RawMessage
+		restricted  bool
+		setMock     func(querier *mocks.Querier, forms *mocks.FormStore)
+		expectError error
+	}{
+		{
+			name: "Valid answers are stored",
+			answers: map[uuid.UUID]json.RawMessage{
+				nameID:   json.RawMessage(`"Alice"`),
+				colourID: json.RawMessage(`"red"`),
+			},
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("OpenForResponses", mock.Anything, testFormID).Return(form.Form{ID: testFormID, Status: "published"}, nil)
+				forms.On("GradingQuestions", mock.Anything, testFormID).Return(questions, nil)
+				forms.On("ListSections", mock.Anything, testFormID).Return(nil, nil)
+				querier.On("Submit", mock.Anything, response.SubmitParams{
+					FormID:       testFormID,
+					RespondentID: pgtype.UUID{Bytes: testUserID, Valid: true},
+					QuestionIds:  []uuid.UUID{nameID, colourID},
+					AnswerValues: []string{`"Alice"`, `"red"`},
+					AnswerPoints: []float64{0, 0},
+					AnswerGraded: []bool{false, false},
+				}).Return(response.FormResponse{ID: uuid.New(), FormID: testFormID, RespondentID: pgtype.UUID{Bytes: testUserID, Valid: true}}, nil)
+			},
+		},
+		{
+			name: "Quiz answers with an answer key are graded",
+			answers: map[uuid.UUID]json.RawMessage{
+				nameID:   json.RawMessage(`" alice"`),
+				colourID: json.RawMessage(`"red"`),
+				reasonID: json.RawMessage(`"It is warm"`),
+			},
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				quiz := slices.Clone(questions)
+				quiz[0].Points, quiz[0].AnswerKey = 2, []byte(`"Alice"`)
+				quiz[1].Points, quiz[1].AnswerKey = 1, []byte(`"blue"`)
+				quiz[2].Points = 3
+				forms.On("OpenForResponses", mock.Anything, testFormID).Return(form.Form{ID: testFormID, Status: "published", IsQuiz: true}, nil)
+				forms.On("GradingQuestions", mock.Anything, testFormID).Return(quiz, nil)
+				forms.On("ListSections", mock.Anything, testFormID).Return(nil, nil)
+				querier.On("Submit", mock.Anything, response.SubmitParams{
+					FormID:       testFormID,
+					RespondentID: pgtype.UUID{Bytes: testUserID, Valid: true},
+					QuestionIds:  []uuid.UUID{nameID, colourID, reasonID},
+					AnswerValues: []string{`" alice"`, `"red"`, `"It is warm"`},
+					AnswerPoints: []float64{2, 0, 0},
+					AnswerGraded: []bool{true, true, false},
+				}).Return(response.FormResponse{ID: uuid.New(), FormID: testFormID, RespondentID: pgtype.UUID{Bytes: testUserID, Valid: true}}, nil)
+			},
+		},
+		{
+			name: "Optional question may be skipped",
+			answers: map[uuid.UUID]json.RawMessage{
+				nameID: json.RawMessage(`"Alice"`),
+			},
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("OpenForResponses", mock.Anything, testFormID).Return(form.Form{ID: testFormID, Status: "published"}, nil)
+				forms.On("GradingQuestions", mock.Anything, testFormID).Return(questions, nil)
+				forms.On("ListSections", mock.Anything, testFormID).Return(nil, nil)
+				querier.On("Submit", mock.Anything, mock.Anything).Return(response.FormResponse{ID: uuid.New()}, nil)
+			},
+		},
+		{
+			name: "Missing required answer",
+			answers: map[uuid.UUID]json.RawMessage{
+				colourID: json.RawMessage(`"red"`),
+			},
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("OpenForResponses", mock.Anything, testFormID).Return(form.Form{ID: testFormID, Status: "published"}, nil)
+				forms.On("GradingQuestions", mock.Anything, testFormID).Return(questions, nil)
+				forms.On("ListSections", mock.Anything, testFormID).Return(nil, nil)
+			},
+			expectError: form.ErrInvalidAnswer,
+		},
+		{
+			name: "Choice outside of the options",
+			answers: map[uuid.UUID]json.RawMessage{
+				nameID:   json.RawMessage(`"Alice"`),
+				colourID: json.RawMessage(`"green"`),
+			},
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("OpenForResponses", mock.Anything, testFormID).Return(form.Form{ID: testFormID, Status: "published"}, nil)
+				forms.On("GradingQuestions", mock.Anything, testFormID).Return(questions, nil)
+				forms.On("ListSections", mock.Anything, testFormID).Return(nil, nil)
+			},
+			expectError: form.ErrInvalidAnswer,
+		},
+		{
+			name: "Answer to a question of another form",
+			answers: map[uuid.UUID]json.RawMessage{
+				nameID:     json.RawMessage(`"Alice"`),
+				uuid.New(): json.RawMessage(`"unexpected"`),
+			},
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("OpenForResponses", mock.Anything, testFormID).Return(form.Form{ID: testFormID, Status: "published"}, nil)
+				forms.On("GradingQuestions", mock.Anything, testFormID).Return(questions, nil)
+				forms.On("ListSections", mock.Anything, testFormID).Return(nil, nil)
+			},
+			expectError: form.ErrInvalidAnswer,
+		},
+		{
+			name: "Answer to a hidden question",
+			answers: map[uuid.UUID]json.RawMessage{
+				nameID:   json.RawMessage(`"Alice"`),
+				colourID: json.RawMessage(`"blue"`),
+				reasonID: json.RawMessage(`"It is not"`),
+			},
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("OpenForResponses", mock.Anything, testFormID).Return(form.Form{ID: testFormID, Status: "published"}, nil)
+				forms.On("GradingQuestions", mock.Anything, testFormID).Return(questions, nil)
+				forms.On("ListSections", mock.Anything, testFormID).Return(nil, nil)
+			},
+			expectError: form.ErrInvalidAnswer,
+		},
+		{
+			name:    "Form does not exist",
+			answers: map[uuid.UUID]json.RawMessage{},
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("OpenForResponses", mock.Anything, testFormID).Return(form.Form{}, form.ErrNotFound)
+			},
+			expectError: form.ErrNotFound,
+		},
+		{
+			name: "Closed form rejects responses",
+			answers: map[uuid.UUID]json.RawMessage{
+				nameID: json.RawMessage(`"Alice"`),
+			},
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("OpenForResponses", mock.Anything, testFormID).Return(form.Form{}, form.ErrReadOnly)
+			},
+			expectError: form.ErrReadOnly,
+		},
+		{
+			name: "Collaborator may skip the restricted share link",
+			answers: map[uuid.UUID]json.RawMessage{
+				nameID: json.RawMessage(`"Alice"`),
+			},
+			restricted: true,
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("Authorize", mock.Anything, testFormID, form.RoleViewer).Return(form.Form{ID: testFormID, Status: "published"}, nil)
+				forms.On("OpenForResponses", mock.Anything, testFormID).Return(form.Form{ID: testFormID, Status: "published"}, nil)
+				forms.On("GradingQuestions", mock.Anything, testFormID).Return(questions, nil)
+				forms.On("ListSections", mock.Anything, testFormID).Return(nil, nil)
+				querier.On("Submit", mock.Anything, mock.Anything).Return(response.FormResponse{ID: uuid.New()}, nil)
+			},
+		},
+		{
+			name: "Others have to use the restricted share link",
+			answers: map[uuid.UUID]json.RawMessage{
+				nameID: json.RawMessage(`"Alice"`),
+			},
+			restricted: true,
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("Authorize", mock.Anything, testFormID, form.RoleViewer).Return(form.Form{}, form.ErrForbidden)
+			},
+			expectError: form.ErrForbidden,
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			forms := mocks.NewFormStore(t)
+			querier.On("HasRestrictedShareLink", mock.Anything, testFormID).Return(tt.restricted, nil)
+			tt.setMock(querier, forms)
+			service := response.NewService(logger, querier, forms)
+
+			_, err := service.Submit(context.Background(), testFormID, testUserID, tt.answers)
+			if tt.expectError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestService_Score(t *testing.T) {
+	testFormID := uuid.New()
+	testResponseID := uuid.New()
+	respondentID := uuid.New()
+	textID := uuid.New()
+	numberID := uuid.New()
+	skippedID := uuid.New()
+	questions := []form.Question{
+		{ID: textID, Type: "paragraph", Points: 5},
+		{ID: numberID, Type: "number", Points: 1, AnswerKey: []byte(`4`)},
+		{ID: skippedID, Type: "number", Points: 2, AnswerKey: []byte(`7`)},
+	}
+	answers := []response.Answer{
+		{ResponseID: testResponseID, QuestionID: textID, Value: []byte(`"Because"`)},
+		{ResponseID: testResponseID, QuestionID: numberID, Value: []byte(`4`), Points: pgtype.Float8{Float64: 1, Valid: true}},
+	}
+	submitted := response.FormResponse{ID: testResponseID, FormID: testFormID, RespondentID: pgtype.UUID{Bytes: respondentID, Valid: true}}
+
+	tests := []struct {
+		name          string
+		userID        uuid.UUID
+		setMock       func(querier *mocks.Querier, forms *mocks.FormStore)
+		expectPoints  float64
+		expectPending int
+		expectReveal  bool
+		expectError   error
+	}{
+		{
+			name:   "Respondent without revealed answers",
+			userID: respondentID,
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("Get", mock.Anything, testFormID).Return(form.Form{ID: testFormID, IsQuiz: true, RevealAnswers: "after_close", Status: "published"}, nil)
+				forms.On("GradingQuestions", mock.Anything, testFormID).Return(questions, nil)
+				querier.On("ListAnswers", mock.Anything, testResponseID).Return(answers, nil)
+				forms.On("Authorize", mock.Anything, testFormID, form.RoleEditor).Return(form.Form{}, form.ErrForbidden)
+			},
+			expectPoints:  1,
+			expectPending: 1,
+		},
+		{
+			name:   "Respondent after the quiz closed",
+			userID: respondentID,
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("Get", mock.Anything, testFormID).Return(form.Form{ID: testFormID, IsQuiz: true, RevealAnswers: "after_close", Status: "closed"}, nil)
+				forms.On("GradingQuestions", mock.Anything, testFormID).Return(questions, nil)
+				querier.On("ListAnswers", mock.Anything, testResponseID).Return(answers, nil)
+			},
+			expectPoints:  1,
+			expectPending: 1,
+			expectReveal:  true,
+		},
+		{
+			name:   "Editor always sees the answers",
+			userID: uuid.New(),
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("Authorize", mock.Anything, testFormID, form.RoleViewer).Return(form.Form{ID: testFormID, IsQuiz: true, RevealAnswers: "never"}, nil)
+				forms.On("GradingQuestions", mock.Anything, testFormID).Return(questions, nil)
+				querier.On("ListAnswers", mock.Anything, testResponseID).Return(answers, nil)
+				forms.On("Authorize", mock.Anything, testFormID, form.RoleEditor).Return(form.Form{ID: testFormID, IsQuiz: true, RevealAnswers: "never"}, nil)
+			},
+			expectPoints:  1,
+			expectPending: 1,
+			expectReveal:  true,
+		},
+		{
+			name:   "Viewer does not see the answers the policy hides",
+			userID: uuid.New(),
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("Authorize", mock.Anything, testFormID, form.RoleViewer).Return(form.Form{ID: testFormID, IsQuiz: true, RevealAnswers: "never"}, nil)
+				forms.On("GradingQuestions", mock.Anything, testFormID).Return(questions, nil)
+				querier.On("ListAnswers", mock.Anything, testResponseID).Return(answers, nil)
+				forms.On("Authorize", mock.Anything, testFormID, form.RoleEditor).Return(form.Form{}, form.ErrForbidden)
+			},
+			expectPoints:  1,
+			expectPending: 1,
+		},
+		{
+			name:   "Form is not a quiz",
+			userID: respondentID,
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("Get", mock.Anything, testFormID).Return(form.Form{ID: testFormID}, nil)
+			},
+			expectError: response.ErrNotQuiz,
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			forms := mocks.NewFormStore(t)
+			querier.On("Get", mock.Anything, response.GetParams{ID: testResponseID, FormID: testFormID}).Return(submitted, nil)
+			tt.setMock(querier, forms)
+			service := response.NewService(logger, querier, forms)
+
+			score, err := service.Score(context.Background(), testFormID, testResponseID, tt.userID)
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectPoints, score.Points)
+			assert.Equal(t, 8.0, score.MaxPoints)
+			assert.Equal(t, tt.expectPending, score.Pending)
+			assert.Equal(t, tt.expectReveal, score.Revealed)
+			assert.Nil(t, score.Questions[0].Points)
+			assert.Equal(t, 0.0, *score.Questions[2].Points)
+			if tt.expectReveal {
+				assert.JSONEq(t, `7`, string(score.Questions[2].CorrectAnswer))
+			} else {
+				assert.Nil(t, score.Questions[2].CorrectAnswer)
+			}
+		})
+	}
+}
+
+func TestService_Export(t *testing.T) {
+	testFormID := uuid.New()
+	nameID := uuid.New()
+	colourID := uuid.New()
+	ageID := uuid.New()
+	questions := []form.Question{
+		{ID: nameID, Type: "short_text", Title: "Name"},
+		{ID: colourID, Type: "multiple_choice", Title: "Colours"},
+		{ID: ageID, Type: "number", Title: "Name"},
+	}
+	respondentID := uuid.New()
+	first := response.ExportRow{
+		ID:           uuid.New(),
+		RespondentID: pgtype.UUID{Bytes: respondentID, Valid: true},
+		SubmittedAt:  pgtype.Timestamptz{Time: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), Valid: true},
+		Answers:      []byte(`{"` + nameID.String() + `": "=1+1", "` + colourID.String() + `": ["red", "blue"], "` + ageID.String() + `": -3}`),
+	}
+	// an anonymous response of a share link
+	second := response.ExportRow{
+		ID:          uuid.New(),
+		SubmittedAt: pgtype.Timestamptz{Time: time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC), Valid: true},
+		Answers:     []byte(`{}`),
+	}
+	rows := func(_ context.Context, _ uuid.UUID, yield func(response.ExportRow) error) error {
+		for _, row := range []response.ExportRow{first, second} {
+			if err := yield(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name        string
+		format      response.ExportFormat
+		setMock     func(querier *mocks.Querier, forms *mocks.FormStore)
+		expect      string
+		expectError error
+	}{
+		{
+			name:   "CSV flattens answers",
+			format: response.ExportCSV,
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("Authorize", mock.Anything, testFormID, form.RoleOwner).Return(form.Form{ID: testFormID}, nil)
+				forms.On("GradingQuestions", mock.Anything, testFormID).Return(questions, nil)
+				querier.On("ExportResponses", mock.Anything, testFormID, mock.Anything).Return(rows)
+			},
+			expect: "response_id,respondent_id,submitted_at,Name,Colours,Name (2)\n" +
+				first.ID.String() + "," + respondentID.String() + ",2024-05-01T12:00:00Z,'=1+1,red; blue,-3\n" +
+				second.ID.String() + ",,2024-05-02T12:00:00Z,,,\n",
+		},
+		{
+			name:   "JSON Lines keep answer types",
+			format: response.ExportJSONL,
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("Authorize", mock.Anything, testFormID, form.RoleOwner).Return(form.Form{ID: testFormID}, nil)
+				forms.On("GradingQuestions", mock.Anything, testFormID).Return(questions, nil)
+				querier.On("ExportResponses", mock.Anything, testFormID, mock.Anything).Return(rows)
+			},
+			expect: `{"response_id":"` + first.ID.String() + `","respondent_id":"` + respondentID.String() + `","submitted_at":"2024-05-01T12:00:00Z","Name":"=1+1","Colours":["red","blue"],"Name (2)":-3}` + "\n" +
+				`{"response_id":"` + second.ID.String() + `","respondent_id":"","submitted_at":"2024-05-02T12:00:00Z","Name":null,"Colours":null,"Name (2)":null}` + "\n",
+		},
+		{
+			name:   "Only owners may export",
+			format: response.ExportCSV,
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("Authorize", mock.Anything, testFormID, form.RoleOwner).Return(form.Form{}, form.ErrForbidden)
+			},
+			expectError: form.ErrForbidden,
+		},
+		{
+			name:        "Unknown format",
+			format:      "pdf",
+			setMock:     func(querier *mocks.Querier, forms *mocks.FormStore) {},
+			expectError: response.ErrInvalidFormat,
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			forms := mocks.NewFormStore(t)
+			tt.setMock(querier, forms)
+			service := response.NewService(logger, querier, forms)
+
+			var out bytes.Buffer
+			err := service.Export(context.Background(), testFormID, tt.format, &out)
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Zero(t, out.Len())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expect, out.String())
+		})
+	}
+}
+
+func TestService_Summary(t *testing.T) {
+	testFormID := uuid.New()
+	colourID := uuid.New()
+	ageID := uuid.New()
+	commentID := uuid.New()
+	questions := []form.Question{
+		{ID: colourID, Type: "single_choice", Title: "Colour", Settings: []byte(`{"choices": ["red", "green", "blue"]}`)},
+		{ID: ageID, Type: "number", Title: "Age"},
+		{ID: commentID, Type: "paragraph", Title: "Comment"},
+	}
+
+	logger := zaptest.NewLogger(t)
+	querier := mocks.NewQuerier(t)
+	forms := mocks.NewFormStore(t)
+	service := response.NewService(logger, querier, forms)
+
+	forms.On("Authorize", mock.Anything, testFormID, form.RoleViewer).Return(form.Form{ID: testFormID}, nil)
+	forms.On("GradingQuestions", mock.Anything, testFormID).Return(questions, nil)
+	expectAggregates := func(times int) {
+		querier.On("ResponsesPerDay", mock.Anything, testFormID).Return([]response.ResponsesPerDayRow{
+			{Day: pgtype.Date{Time: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Valid: true}, Count: 2},
+			{Day: pgtype.Date{Time: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC), Valid: true}, Count: 1},
+		}, nil).Times(times)
+		querier.On("AnswerCounts", mock.Anything, testFormID).Return([]response.AnswerCountsRow{
+			{QuestionID: colourID, Answered: 3},
+			{QuestionID: ageID, Answered: 3},
+		}, nil).Times(times)
+		querier.On("ChoiceCounts", mock.Anything, testFormID).Return([]response.ChoiceCountsRow{
+			{QuestionID: colourID, Choice: "blue", Count: 2},
+			{QuestionID: colourID, Choice: "purple", Count: 1},
+		}, nil).Times(times)
+		querier.On("Histograms", mock.Anything, response.HistogramsParams{FormID: testFormID, Buckets: 10}).Return([]response.HistogramsRow{
+			{QuestionID: ageID, Lo: 20, Hi: 40, Mean: 30, Bucket: 1, Count: 1},
+			{QuestionID: ageID, Lo: 20, Hi: 40, Mean: 30, Bucket: 10, Count: 2},
+		}, nil).Times(times)
+		querier.On("WordFrequencies", mock.Anything, mock.MatchedBy(func(arg response.WordFrequenciesParams) bool {
+			return arg.FormID == testFormID && slices.Contains(arg.StopWords, "the")
+		})).Return([]response.WordFrequenciesRow(nil), nil).Times(times)
+	}
+
+	// the second read is served from the cache
+	expectAggregates(1)
+	for range 2 {
+		summary, err := service.Summary(context.Background(), testFormID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), summary.Responses)
+		assert.Len(t, summary.PerDay, 2)
+		assert.Equal(t, []response.ChoiceCount{
+			{Choice: "red", Count: 0},
+			{Choice: "green", Count: 0},
+			{Choice: "blue", Count: 2},
+			{Choice: "purple", Count: 1},
+		}, summary.Questions[0].Choices)
+		histogram := summary.Questions[1].Histogram
+		if assert.NotNil(t, histogram) {
+			assert.Len(t, histogram.Buckets, 10)
+			assert.Equal(t, response.Bucket{From: 20, To: 22, Count: 1}, histogram.Buckets[0])
+			assert.Equal(t, int64(0), histogram.Buckets[5].Count)
+			assert.Equal(t, response.Bucket{From: 38, To: 40, Count: 2}, histogram.Buckets[9])
+		}
+		assert.Nil(t, summary.Questions[2].Histogram)
+		assert.Empty(t, summary.Questions[2].Words)
+	}
+
+	// a new response invalidates the cache
+	querier.On("HasRestrictedShareLink", mock.Anything, testFormID).Return(false, nil)
+	forms.On("OpenForResponses", mock.Anything, testFormID).Return(form.Form{ID: testFormID}, nil)
+	forms.On("ListSections", mock.Anything, testFormID).Return([]form.FormSection(nil), nil)
+	querier.On("Submit", mock.Anything, mock.Anything).Return(response.FormResponse{ID: uuid.New(), FormID: testFormID}, nil)
+	_, err := service.Submit(context.Background(), testFormID, uuid.New(), map[uuid.UUID]json.RawMessage{})
+	assert.NoError(t, err)
+
+	expectAggregates(1)
+	_, err = service.Summary(context.Background(), testFormID)
+	assert.NoError(t, err)
+	querier.AssertNumberOfCalls(t, "ResponsesPerDay", 2)
+}
@@ -0,0 +1,273 @@
+package response
+
+import (
+	"awesomeProject/internal/form"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// histogramBuckets is the number of buckets of number and date histograms.
+	histogramBuckets = 10
+	// wordsPerQuestion limits the word frequencies of a text question to the
+	// most common words.
+	wordsPerQuestion = 25
+	// maxCachedSummaries bounds the memory held by the summary cache.
+	maxCachedSummaries = 1024
+)
+
+// stopWords are left out of word frequencies.
+var stopWords = []string{
+	"a", "an", "and", "are", "as", "at", "be", "but", "by", "for", "from", "has", "have", "he", "her", "his",
+	"i", "if", "in", "is", "it", "its", "me", "my", "no", "not", "of", "on", "or", "our", "she", "so", "that",
+	"the", "their", "them", "there", "they", "this", "to", "too", "very", "was", "we", "were", "what", "when",
+	"which", "who", "will", "with", "you", "your",
+}
+
+// Summary aggregates all responses of a form.
+type Summary struct {
+	Responses int64
+	// PerDay counts the responses per UTC day, days without responses are
+	// left out.
+	PerDay    []DayCount
+	Questions []QuestionSummary
+}
+
+type DayCount struct {
+	Day   time.Time
+	Count int64
+}
+
+// QuestionSummary aggregates the answers to one question. Which of Choices,
+// Histogram and Words is set depends on the type of the question.
+type QuestionSummary struct {
+	QuestionID uuid.UUID
+	Title      string
+	Type       form.QuestionType
+	Answered   int64
+	// Choices counts every configured choice, including those nobody
+	// picked, followed by answers that are no longer a choice.
+	Choices []ChoiceCount
+	// Histogram is nil when the question has no answers. Dates are given
+	// as days since 1970-01-01.
+	Histogram *Histogram
+	// Words are the most common words of text answers.
+	Words []WordCount
+}
+
+type ChoiceCount struct {
+	Choice string
+	Count  int64
+}
+
+type Histogram struct {
+	Min     float64
+	Max     float64
+	Mean    float64
+	Buckets []Bucket
+}
+
+// Bucket counts the answers from From up to To. Only the last bucket
+// includes To.
+type Bucket struct {
+	From  float64
+	To    float64
+	Count int64
+}
+
+type WordCount struct {
+	Word  string
+	Count int64
+}
+
+// summaryAggregates are the query results a summary is built from.
+type summaryAggregates struct {
+	perDay     []ResponsesPerDayRow
+	answered   []AnswerCountsRow
+	choices    []ChoiceCountsRow
+	histograms []HistogramsRow
+	words      []WordFrequenciesRow
+}
+
+// summaryCache keeps the aggregates of a form until a new response arrives.
+// Only the aggregates are cached, they are combined with the current
+// questions on every read, so edits to the form show up right away.
+//
+// Every invalidation takes a new sequence number. A summary computed while a
+// response arrived is not stored, because the sequence number of its form
+// has changed in the meantime.
+type summaryCache struct {
+	mu      sync.Mutex
+	seq     uint64
+	entries map[uuid.UUID]*summaryEntry
+}
+
+type summaryEntry struct {
+	seq        uint64
+	aggregates *summaryAggregates
+}
+
+func newSummaryCache() *summaryCache {
+	return &summaryCache{entries: make(map[uuid.UUID]*summaryEntry)}
+}
+
+// get returns the cached aggregates of a form. On a miss it returns the
+// token to pass to put.
+func (c *summaryCache) get(formID uuid.UUID) (*summaryAggregates, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[formID]
+	if !ok {
+		if len(c.entries) >= maxCachedSummaries {
+			c.evict()
+		}
+		entry = &summaryEntry{seq: c.seq}
+		c.entries[formID] = entry
+	}
+	return entry.aggregates, entry.seq
+}
+
+// put stores the aggregates unless the form was invalidated since get.
+func (c *summaryCache) put(formID uuid.UUID, token uint64, aggregates *summaryAggregates) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[formID]
+	if !ok || entry.seq != token {
+		return
+	}
+	entry.aggregates = aggregates
+}
+
+func (c *summaryCache) invalidate(formID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.seq++
+	if entry, ok := c.entries[formID]; ok {
+		entry.seq = c.seq
+		entry.aggregates = nil
+	}
+}
+
+// evict drops a random half of the entries. Must be called with mu held.
+func (c *summaryCache) evict() {
+	n := len(c.entries) / 2
+	for formID := range c.entries {
+		if n == 0 {
+			return
+		}
+		delete(c.entries, formID)
+		n--
+	}
+}
+
+// summaryOf combines the aggregates with the questions of the form, in
+// question order.
+func summaryOf(questions []form.Question, aggregates *summaryAggregates) Summary {
+	summary := Summary{
+		PerDay:    make([]DayCount, 0, len(aggregates.perDay)),
+		Questions: make([]QuestionSummary, 0, len(questions)),
+	}
+	for _, row := range aggregates.perDay {
+		summary.Responses += row.Count
+		summary.PerDay = append(summary.PerDay, DayCount{Day: row.Day.Time, Count: row.Count})
+	}
+
+	answered := make(map[uuid.UUID]int64, len(aggregates.answered))
+	for _, row := range aggregates.answered {
+		answered[row.QuestionID] = row.Answered
+	}
+	choices := make(map[uuid.UUID][]ChoiceCountsRow)
+	for _, row := range aggregates.choices {
+		choices[row.QuestionID] = append(choices[row.QuestionID], row)
+	}
+	histograms := make(map[uuid.UUID][]HistogramsRow)
+	for _, row := range aggregates.histograms {
+		histograms[row.QuestionID] = append(histograms[row.QuestionID], row)
+	}
+	words := make(map[uuid.UUID][]WordCount)
+	for _, row := range aggregates.words {
+		words[row.QuestionID] = append(words[row.QuestionID], WordCount{Word: row.Word, Count: row.Count})
+	}
+
+	for _, question := range questions {
+		item := QuestionSummary{
+			QuestionID: question.ID,
+			Title:      question.Title,
+			Type:       form.QuestionType(question.Type),
+			Answered:   answered[question.ID],
+		}
+		switch {
+		case item.Type.IsChoice():
+			// settings were validated when the question was saved
+			settings, _ := question.ParseSettings()
+			item.Choices = choicesOf(settings.Choices, choices[question.ID])
+		case item.Type == form.QuestionTypeNumber || item.Type == form.QuestionTypeDate:
+			item.Histogram = histogramOf(histograms[question.ID])
+		case item.Type.IsText():
+			item.Words = words[question.ID]
+		}
+		summary.Questions = append(summary.Questions, item)
+	}
+
+	return summary
+}
+
+// choicesOf lists the configured choices in their order, followed by the
+// other answers sorted by count as returned by ChoiceCounts.
+func choicesOf(configured []string, rows []ChoiceCountsRow) []ChoiceCount {
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Choice] = row.Count
+	}
+
+	result := make([]ChoiceCount, 0, len(configured)+len(rows))
+	seen := make(map[string]bool, len(configured))
+	for _, choice := range configured {
+		seen[choice] = true
+		result = append(result, ChoiceCount{Choice: choice, Count: counts[choice]})
+	}
+	for _, row := range rows {
+		if !seen[row.Choice] {
+			result = append(result, ChoiceCount{Choice: row.Choice, Count: row.Count})
+		}
+	}
+	return result
+}
+
+// histogramOf fills in the buckets nobody answered in. All answers fall into
+// a single bucket when they are equal.
+func histogramOf(rows []HistogramsRow) *Histogram {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	histogram := &Histogram{
+		Min:  rows[0].Lo,
+		Max:  rows[0].Hi,
+		Mean: rows[0].Mean,
+	}
+	if histogram.Min == histogram.Max {
+		histogram.Buckets = []Bucket{{From: histogram.Min, To: histogram.Max, Count: rows[0].Count}}
+		return histogram
+	}
+
+	width := (histogram.Max - histogram.Min) / histogramBuckets
+	histogram.Buckets = make([]Bucket, histogramBuckets)
+	for i := range histogram.Buckets {
+		histogram.Buckets[i].From = histogram.Min + float64(i)*width
+		histogram.Buckets[i].To = histogram.Min + float64(i+1)*width
+	}
+	histogram.Buckets[histogramBuckets-1].To = histogram.Max
+	for _, row := range rows {
+		// width_bucket numbers the buckets from 1
+		if row.Bucket >= 1 && int(row.Bucket) <= histogramBuckets {
+			histogram.Buckets[row.Bucket-1].Count += row.Count
+		}
+	}
+	return histogram
+}
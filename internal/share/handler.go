@@ -0,0 +1,311 @@
+package share
+
+import (
+	"awesomeProject/internal/form"
+	"awesomeProject/internal/response"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AccessCodeHeader carries the access code of a share link. It is not part
+// of the URL, so it does not end up in browser histories and access logs.
+const AccessCodeHeader = "X-Access-Code"
+
+type LinkRequest struct {
+	AccessCode    string     `json:"access_code" validate:"omitempty,min=4,max=64"`
+	ExpiresAt     *time.Time `json:"expiresAt"`
+	RequireSignIn bool       `json:"require_sign_in"`
+}
+
+type LinkResponse struct {
+	ID            string     `json:"id"`
+	FormID        string     `json:"form_id"`
+	Slug          string     `json:"slug"`
+	URL           string     `json:"url"`
+	HasAccessCode bool       `json:"has_access_code"`
+	RequireSignIn bool       `json:"require_sign_in"`
+	ExpiresAt     *time.Time `json:"expiresAt"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// PublicFormResponse is a form as respondents of a share link see it. It does
+// not name the form, so the link cannot be skipped by answering the form
+// through the API.
+type PublicFormResponse struct {
+	Title       string                  `json:"title"`
+	Description string                  `json:"description"`
+	IsQuiz      bool                    `json:"is_quiz,omitempty"`
+	Sections    []form.SectionResponse  `json:"sections"`
+	Questions   []form.QuestionResponse `json:"questions"`
+}
+
+type SubmissionResponse struct {
+	ID          string    `json:"id"`
+	SubmittedAt time.Time `json:"submittedAt"`
+}
+
+type Store interface {
+	Create(ctx context.Context, formID uuid.UUID, input LinkInput) (FormShareLink, error)
+	List(ctx context.Context, formID uuid.UUID) ([]FormShareLink, error)
+	Delete(ctx context.Context, formID, linkID uuid.UUID) error
+	View(ctx context.Context, slug, accessCode string) (PublicForm, error)
+	Submit(ctx context.Context, slug, accessCode string, answers map[uuid.UUID]json.RawMessage) (response.FormResponse, error)
+}
+
+type Handler struct {
+	logger    *zap.Logger
+	validator *validator.Validate
+	baseURL   string
+	store     Store
+}
+
+// NewHandler creates the handler of share links. baseURL is where the public
+// /f/{slug} routes are served, it prefixes the URLs of the links.
+func NewHandler(logger *zap.Logger, validator *validator.Validate, baseURL string, store Store) *Handler {
+	return &Handler{
+		logger:    logger,
+		validator: validator,
+		baseURL:   baseURL,
+		store:     store,
+	}
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	var req LinkRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// validate request
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.logger.Error("Validation failed", zap.Error(err))
+		http.Error(w, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	link, err := h.store.Create(ctx, formID, LinkInput{
+		AccessCode:    req.AccessCode,
+		ExpiresAt:     req.ExpiresAt,
+		RequireSignIn: req.RequireSignIn,
+	})
+	if err != nil {
+		h.writeError(w, "Failed to create share link", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, h.newLinkResponse(link))
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+
+	links, err := h.store.List(ctx, formID)
+	if err != nil {
+		h.writeError(w, "Failed to list share links", err)
+		return
+	}
+
+	resp := make([]LinkResponse, 0, len(links))
+	for _, link := range links {
+		resp = append(resp, h.newLinkResponse(link))
+	}
+
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	formID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.logger.Warn("Invalid form ID", zap.String("form_id", r.PathValue("id")))
+		http.Error(w, "Invalid form ID", http.StatusBadRequest)
+		return
+	}
+	linkID, err := uuid.Parse(r.PathValue("linkId"))
+	if err != nil {
+		h.logger.Warn("Invalid share link ID", zap.String("link_id", r.PathValue("linkId")))
+		http.Error(w, "Invalid share link ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.store.Delete(ctx, formID, linkID)
+	if err != nil {
+		h.writeError(w, "Failed to delete share link", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// View is the public route that shows the form behind a share link.
+func (h *Handler) View(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	public, err := h.store.View(ctx, r.PathValue("slug"), r.Header.Get(AccessCodeHeader))
+	if err != nil {
+		h.writeError(w, "Failed to open share link", err)
+		return
+	}
+
+	resp := PublicFormResponse{
+		Title:       public.Form.Title,
+		Description: public.Form.Description.String,
+		IsQuiz:      public.Form.IsQuiz,
+		Sections:    make([]form.SectionResponse, 0, len(public.Sections)),
+		Questions:   make([]form.QuestionResponse, 0, len(public.Questions)),
+	}
+	for _, section := range public.Sections {
+		item, err := form.NewSectionResponse(section)
+		if err != nil {
+			h.logger.Error("Failed to decode section", zap.Error(err))
+			http.Error(w, "Failed to open share link", http.StatusInternalServerError)
+			return
+		}
+		item.FormID = ""
+		resp.Sections = append(resp.Sections, item)
+	}
+	for _, question := range public.Questions {
+		item, err := form.NewQuestionResponse(question)
+		if err != nil {
+			h.logger.Error("Failed to decode question", zap.Error(err))
+			http.Error(w, "Failed to open share link", http.StatusInternalServerError)
+			return
+		}
+		item.FormID = ""
+		resp.Questions = append(resp.Questions, item)
+	}
+
+	// the form may be closed or the link revoked at any time
+	w.Header().Set("Cache-Control", "no-store")
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// Submit is the public route that stores a response through a share link.
+func (h *Handler) Submit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req response.SubmitRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Error("Failed to decode request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// validate request
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.logger.Error("Validation failed", zap.Error(err))
+		http.Error(w, "Validation failed", http.StatusBadRequest)
+		return
+	}
+
+	answers := make(map[uuid.UUID]json.RawMessage, len(req.Answers))
+	for key, value := range req.Answers {
+		questionID, err := uuid.Parse(key)
+		if err != nil {
+			h.logger.Warn("Invalid question ID in answers", zap.String("question_id", key))
+			http.Error(w, "Invalid question ID", http.StatusBadRequest)
+			return
+		}
+		answers[questionID] = value
+	}
+
+	submitted, err := h.store.Submit(ctx, r.PathValue("slug"), r.Header.Get(AccessCodeHeader), answers)
+	if err != nil {
+		h.writeError(w, "Failed to submit response", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, SubmissionResponse{
+		ID:          submitted.ID.String(),
+		SubmittedAt: submitted.SubmittedAt.Time,
+	})
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, resp any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// writeError answers unknown, revoked and hidden forms alike with 404, so
+// the public routes do not tell which slugs or forms exist.
+func (h *Handler) writeError(w http.ResponseWriter, message string, err error) {
+	var validationErr *response.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		h.logger.Warn(message, zap.Error(err))
+		h.writeJSON(w, http.StatusBadRequest, response.ValidationErrorResponse{
+			Message: "Invalid answers",
+			Errors:  validationErr.Fields,
+		})
+	case errors.Is(err, ErrNotFound), errors.Is(err, form.ErrNotFound):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, "Not found", http.StatusNotFound)
+	case errors.Is(err, ErrExpired):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, "Share link expired", http.StatusGone)
+	case errors.Is(err, ErrSignInRequired), errors.Is(err, ErrAccessCodeRequired):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, ErrWrongAccessCode), errors.Is(err, form.ErrForbidden):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	case errors.Is(err, form.ErrReadOnly):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, "Form is not accepting responses", http.StatusConflict)
+	case errors.Is(err, ErrInvalidLink), errors.Is(err, form.ErrInvalidAnswer):
+		h.logger.Warn(message, zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Error(message, zap.Error(err))
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) newLinkResponse(link FormShareLink) LinkResponse {
+	resp := LinkResponse{
+		ID:            link.ID.String(),
+		FormID:        link.FormID.String(),
+		Slug:          link.Slug,
+		URL:           h.baseURL + "/f/" + link.Slug,
+		HasAccessCode: link.AccessCodeHash.Valid,
+		RequireSignIn: link.RequireSignIn,
+		CreatedAt:     link.CreatedAt.Time,
+	}
+	if link.ExpiresAt.Valid {
+		resp.ExpiresAt = &link.ExpiresAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,64 @@
+package share
+
+import (
+	"awesomeProject/internal/jwt"
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// Middleware guards the public share link routes in place of
+// jwt.Middleware, which would turn away everyone without an account.
+type Middleware struct {
+	logger      *zap.Logger
+	verifier    jwt.Verifier
+	revocations jwt.Revocations
+	limiter     *RateLimiter
+}
+
+func NewMiddleware(logger *zap.Logger, verifier jwt.Verifier, revocations jwt.Revocations, limiter *RateLimiter) Middleware {
+	return Middleware{
+		logger:      logger,
+		verifier:    verifier,
+		revocations: revocations,
+		limiter:     limiter,
+	}
+}
+
+// HandlerFunc limits the requests per share link, so one link cannot flood
+// its form with responses, and signs in the user when the request carries a
+// token. Requests without a token pass as anonymous, requests with an
+// invalid one are rejected rather than silently made anonymous.
+func (m Middleware) HandlerFunc(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		slug := r.PathValue("slug")
+		allowed, wait := m.limiter.Allow(slug)
+		if !allowed {
+			m.logger.Warn("Share link rate limit exceeded", zap.String("slug", slug))
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		token := r.Header.Get("Authorization")
+		if token != "" {
+			parsed, err := m.verifier.ParseToken(ctx, token)
+			if err == nil && m.revocations.IsRevoked(parsed) {
+				err = jwt.ErrTokenRevoked
+			}
+			if err != nil {
+				m.logger.Warn("Authorization header invalid", zap.Error(err))
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			ctx = context.WithValue(ctx, jwt.UserContextKey, parsed.UserID)
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+}
@@ -0,0 +1,17 @@
+-- name: CreateLink :one
+INSERT INTO form_share_links (form_id, slug, access_code_hash, require_sign_in, expires_at, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListLinks :many
+SELECT * FROM form_share_links
+WHERE form_id = $1
+ORDER BY created_at, id;
+
+-- name: GetLinkBySlug :one
+SELECT * FROM form_share_links
+WHERE slug = $1;
+
+-- name: DeleteLink :execrows
+DELETE FROM form_share_links
+WHERE id = $1 AND form_id = $2;
\ No newline at end of file
@@ -0,0 +1,77 @@
+package share
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter lets every key make a burst of requests and refills it at a
+// steady rate, a token bucket per key.
+type RateLimiter struct {
+	mu        sync.Mutex
+	perToken  time.Duration
+	burst     float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter allows limit requests per key in every period, all at once
+// or spread out.
+func NewRateLimiter(limit int, period time.Duration) *RateLimiter {
+	return &RateLimiter{
+		perToken: period / time.Duration(limit),
+		burst:    float64(limit),
+		buckets:  make(map[string]*bucket),
+	}
+}
+
+// Allow takes a request from the bucket of the key. When the bucket is
+// empty it returns false and how long until the next request is allowed.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration(math.Ceil((1 - b.tokens) * float64(l.perToken)))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+func (l *RateLimiter) refill(b *bucket, now time.Time) float64 {
+	return min(l.burst, b.tokens+float64(now.Sub(b.last))/float64(l.perToken))
+}
+
+// sweep forgets the buckets that filled up again, at most once per time it
+// takes to fill an empty bucket, so links that are no longer used do not
+// keep memory.
+func (l *RateLimiter) sweep(now time.Time) {
+	full := time.Duration(l.burst) * l.perToken
+	if now.Sub(l.lastSweep) < full {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
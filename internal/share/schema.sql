@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS form_share_links
+(
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    form_id          UUID        NOT NULL REFERENCES forms (id) ON DELETE CASCADE,
+    slug             TEXT        NOT NULL UNIQUE,
+    access_code_hash TEXT,
+    require_sign_in  BOOLEAN     NOT NULL DEFAULT false,
+    expires_at       TIMESTAMPTZ,
+    created_by       UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS form_share_links_form_idx ON form_share_links (form_id, created_at)
\ No newline at end of file
@@ -0,0 +1,266 @@
+// Package share publishes forms through share links, so people without an
+// account can fill them in. A link is an unguessable slug that opens one form
+// at /f/{slug}. It may ask for an access code, stop working after a while or
+// ask respondents to sign in first. Once a form has a link with an access code
+// or an expiry, only its collaborators may still answer it without a link.
+package share
+
+import (
+	"awesomeProject/internal/form"
+	"awesomeProject/internal/jwt"
+	"awesomeProject/internal/response"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrNotFound           = errors.New("share link not found")
+	ErrExpired            = errors.New("share link expired")
+	ErrInvalidLink        = errors.New("invalid share link")
+	ErrAccessCodeRequired = errors.New("access code required")
+	ErrWrongAccessCode    = errors.New("wrong access code")
+	ErrSignInRequired     = errors.New("sign-in required")
+)
+
+// slugBytes is the amount of randomness in a slug, enough that slugs cannot
+// be guessed or enumerated.
+const slugBytes = 16
+
+//go:generate mockery --name=Querier
+type Querier interface {
+	CreateLink(ctx context.Context, arg CreateLinkParams) (FormShareLink, error)
+	ListLinks(ctx context.Context, formID uuid.UUID) ([]FormShareLink, error)
+	GetLinkBySlug(ctx context.Context, slug string) (FormShareLink, error)
+	DeleteLink(ctx context.Context, arg DeleteLinkParams) (int64, error)
+}
+
+//go:generate mockery --name=FormStore
+type FormStore interface {
+	Authorize(ctx context.Context, formID uuid.UUID, required form.Role) (form.Form, error)
+	OpenForResponses(ctx context.Context, formID uuid.UUID) (form.Form, error)
+	ListSections(ctx context.Context, formID uuid.UUID) ([]form.FormSection, error)
+	ListQuestions(ctx context.Context, formID uuid.UUID) ([]form.Question, error)
+}
+
+//go:generate mockery --name=ResponseStore
+type ResponseStore interface {
+	SubmitShared(ctx context.Context, formID, respondentID uuid.UUID, answers map[uuid.UUID]json.RawMessage) (response.FormResponse, error)
+}
+
+// LinkInput are the settings of a new share link. An empty access code and
+// a nil expiry leave the link open.
+type LinkInput struct {
+	AccessCode    string
+	ExpiresAt     *time.Time
+	RequireSignIn bool
+}
+
+// PublicForm is what respondents of a share link see: the form with its
+// sections and questions, without answer keys.
+type PublicForm struct {
+	Form      form.Form
+	Sections  []form.FormSection
+	Questions []form.Question
+}
+
+type Service struct {
+	logger    *zap.Logger
+	queries   Querier
+	forms     FormStore
+	responses ResponseStore
+}
+
+func NewService(logger *zap.Logger, querier Querier, forms FormStore, responses ResponseStore) *Service {
+	return &Service{
+		logger:    logger,
+		queries:   querier,
+		forms:     forms,
+		responses: responses,
+	}
+}
+
+// Create adds a share link to the form. Editors of the form may share it.
+// Only a hash of the access code is stored.
+func (s *Service) Create(ctx context.Context, formID uuid.UUID, input LinkInput) (FormShareLink, error) {
+	_, err := s.forms.Authorize(ctx, formID, form.RoleEditor)
+	if err != nil {
+		return FormShareLink{}, err
+	}
+
+	params := CreateLinkParams{
+		FormID:        formID,
+		RequireSignIn: input.RequireSignIn,
+	}
+	if input.ExpiresAt != nil {
+		if !input.ExpiresAt.After(time.Now()) {
+			return FormShareLink{}, fmt.Errorf("%w: expiresAt must be in the future", ErrInvalidLink)
+		}
+		params.ExpiresAt = pgtype.Timestamptz{Time: *input.ExpiresAt, Valid: true}
+	}
+	if input.AccessCode != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(input.AccessCode), bcrypt.DefaultCost)
+		if err != nil {
+			return FormShareLink{}, fmt.Errorf("%w: %v", ErrInvalidLink, err)
+		}
+		params.AccessCodeHash = pgtype.Text{String: string(hash), Valid: true}
+	}
+	if userID, ok := ctx.Value(jwt.UserContextKey).(uuid.UUID); ok {
+		params.CreatedBy = pgtype.UUID{Bytes: userID, Valid: true}
+	}
+	params.Slug, err = newSlug()
+	if err != nil {
+		s.logger.Error("Failed to generate share link slug", zap.Error(err))
+		return FormShareLink{}, err
+	}
+
+	result, err := s.queries.CreateLink(ctx, params)
+	if err != nil {
+		s.logger.Error("Failed to create share link", zap.Error(err))
+		return FormShareLink{}, err
+	}
+
+	s.logger.Info("Created share link", zap.String("form_id", formID.String()), zap.String("link_id", result.ID.String()))
+
+	return result, nil
+}
+
+// List returns the share links of the form, oldest first. Editors of the
+// form may list them.
+func (s *Service) List(ctx context.Context, formID uuid.UUID) ([]FormShareLink, error) {
+	_, err := s.forms.Authorize(ctx, formID, form.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.queries.ListLinks(ctx, formID)
+	if err != nil {
+		s.logger.Error("Failed to list share links", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Delete revokes a share link. Its slug stops working right away, responses
+// submitted through it are kept.
+func (s *Service) Delete(ctx context.Context, formID, linkID uuid.UUID) error {
+	_, err := s.forms.Authorize(ctx, formID, form.RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.queries.DeleteLink(ctx, DeleteLinkParams{
+		ID:     linkID,
+		FormID: formID,
+	})
+	if err != nil {
+		s.logger.Error("Failed to delete share link", zap.Error(err))
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	s.logger.Info("Deleted share link", zap.String("form_id", formID.String()), zap.String("link_id", linkID.String()))
+
+	return nil
+}
+
+// Open checks that the link with the slug may be used: it exists, has not
+// expired, the access code matches and, when the link requires it, a user
+// signed in.
+func (s *Service) Open(ctx context.Context, slug, accessCode string) (FormShareLink, error) {
+	link, err := s.queries.GetLinkBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return FormShareLink{}, ErrNotFound
+		}
+		s.logger.Error("Failed to get share link", zap.Error(err))
+		return FormShareLink{}, err
+	}
+
+	if link.ExpiresAt.Valid && !time.Now().Before(link.ExpiresAt.Time) {
+		return FormShareLink{}, ErrExpired
+	}
+	if link.RequireSignIn {
+		if _, ok := ctx.Value(jwt.UserContextKey).(uuid.UUID); !ok {
+			return FormShareLink{}, ErrSignInRequired
+		}
+	}
+	if link.AccessCodeHash.Valid {
+		if accessCode == "" {
+			return FormShareLink{}, ErrAccessCodeRequired
+		}
+		err = bcrypt.CompareHashAndPassword([]byte(link.AccessCodeHash.String), []byte(accessCode))
+		if err != nil {
+			s.logger.Warn("Wrong access code for share link", zap.String("link_id", link.ID.String()))
+			return FormShareLink{}, ErrWrongAccessCode
+		}
+	}
+
+	return link, nil
+}
+
+// View returns the form behind the link for respondents. The form has to
+// accept responses.
+func (s *Service) View(ctx context.Context, slug, accessCode string) (PublicForm, error) {
+	link, err := s.Open(ctx, slug, accessCode)
+	if err != nil {
+		return PublicForm{}, err
+	}
+
+	f, err := s.forms.OpenForResponses(ctx, link.FormID)
+	if err != nil {
+		return PublicForm{}, err
+	}
+	sections, err := s.forms.ListSections(ctx, link.FormID)
+	if err != nil {
+		return PublicForm{}, err
+	}
+	// answer keys are left out for anyone but editors
+	questions, err := s.forms.ListQuestions(ctx, link.FormID)
+	if err != nil {
+		return PublicForm{}, err
+	}
+
+	return PublicForm{Form: f, Sections: sections, Questions: questions}, nil
+}
+
+// Submit stores the answers as a response to the form behind the link. The
+// response belongs to the signed in user, if any, and is anonymous otherwise.
+func (s *Service) Submit(ctx context.Context, slug, accessCode string, answers map[uuid.UUID]json.RawMessage) (response.FormResponse, error) {
+	link, err := s.Open(ctx, slug, accessCode)
+	if err != nil {
+		return response.FormResponse{}, err
+	}
+
+	respondentID, _ := ctx.Value(jwt.UserContextKey).(uuid.UUID)
+	result, err := s.responses.SubmitShared(ctx, link.FormID, respondentID, answers)
+	if err != nil {
+		return response.FormResponse{}, err
+	}
+
+	s.logger.Info("Submitted response through share link", zap.String("link_id", link.ID.String()), zap.String("response_id", result.ID.String()))
+
+	return result, nil
+}
+
+func newSlug() (string, error) {
+	b := make([]byte, slugBytes)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,208 @@
+package share_test
+
+import (
+	"awesomeProject/internal/form"
+	"awesomeProject/internal/jwt"
+	"awesomeProject/internal/response"
+	"awesomeProject/internal/share"
+	"awesomeProject/internal/share/mocks"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestService_Submit(t *testing.T) {
+	testFormID := uuid.New()
+	testUserID := uuid.New()
+	questionID := uuid.New()
+	answers := map[uuid.UUID]json.RawMessage{questionID: json.RawMessage(`"Alice"`)}
+	hash, err := bcrypt.GenerateFromPassword([]byte("1234"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	open := share.FormShareLink{ID: uuid.New(), FormID: testFormID, Slug: "open"}
+	withCode := share.FormShareLink{ID: uuid.New(), FormID: testFormID, Slug: "code", AccessCodeHash: pgtype.Text{String: string(hash), Valid: true}}
+	signIn := share.FormShareLink{ID: uuid.New(), FormID: testFormID, Slug: "sign-in", RequireSignIn: true}
+	expired := share.FormShareLink{ID: uuid.New(), FormID: testFormID, Slug: "expired", ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}}
+
+	tests := []struct {
+		name        string
+		slug        string
+		code        string
+		userID      *uuid.UUID
+		setMock     func(querier *mocks.Querier, responses *mocks.ResponseStore)
+		expectError error
+	}{
+		{
+			name: "Anonymous response through an open link",
+			slug: "open",
+			setMock: func(querier *mocks.Querier, responses *mocks.ResponseStore) {
+				querier.On("GetLinkBySlug", mock.Anything, "open").Return(open, nil)
+				responses.On("SubmitShared", mock.Anything, testFormID, uuid.Nil, answers).Return(response.FormResponse{ID: uuid.New()}, nil)
+			},
+		},
+		{
+			name:   "Signed in respondents keep their name",
+			slug:   "sign-in",
+			userID: &testUserID,
+			setMock: func(querier *mocks.Querier, responses *mocks.ResponseStore) {
+				querier.On("GetLinkBySlug", mock.Anything, "sign-in").Return(signIn, nil)
+				responses.On("SubmitShared", mock.Anything, testFormID, testUserID, answers).Return(response.FormResponse{ID: uuid.New()}, nil)
+			},
+		},
+		{
+			name: "Link requires sign-in",
+			slug: "sign-in",
+			setMock: func(querier *mocks.Querier, responses *mocks.ResponseStore) {
+				querier.On("GetLinkBySlug", mock.Anything, "sign-in").Return(signIn, nil)
+			},
+			expectError: share.ErrSignInRequired,
+		},
+		{
+			name: "Right access code",
+			slug: "code",
+			code: "1234",
+			setMock: func(querier *mocks.Querier, responses *mocks.ResponseStore) {
+				querier.On("GetLinkBySlug", mock.Anything, "code").Return(withCode, nil)
+				responses.On("SubmitShared", mock.Anything, testFormID, uuid.Nil, answers).Return(response.FormResponse{ID: uuid.New()}, nil)
+			},
+		},
+		{
+			name: "Missing access code",
+			slug: "code",
+			setMock: func(querier *mocks.Querier, responses *mocks.ResponseStore) {
+				querier.On("GetLinkBySlug", mock.Anything, "code").Return(withCode, nil)
+			},
+			expectError: share.ErrAccessCodeRequired,
+		},
+		{
+			name: "Wrong access code",
+			slug: "code",
+			code: "4321",
+			setMock: func(querier *mocks.Querier, responses *mocks.ResponseStore) {
+				querier.On("GetLinkBySlug", mock.Anything, "code").Return(withCode, nil)
+			},
+			expectError: share.ErrWrongAccessCode,
+		},
+		{
+			name: "Expired link",
+			slug: "expired",
+			setMock: func(querier *mocks.Querier, responses *mocks.ResponseStore) {
+				querier.On("GetLinkBySlug", mock.Anything, "expired").Return(expired, nil)
+			},
+			expectError: share.ErrExpired,
+		},
+		{
+			name: "Unknown or revoked link",
+			slug: "gone",
+			setMock: func(querier *mocks.Querier, responses *mocks.ResponseStore) {
+				querier.On("GetLinkBySlug", mock.Anything, "gone").Return(share.FormShareLink{}, pgx.ErrNoRows)
+			},
+			expectError: share.ErrNotFound,
+		},
+		{
+			name: "Form no longer accepts responses",
+			slug: "open",
+			setMock: func(querier *mocks.Querier, responses *mocks.ResponseStore) {
+				querier.On("GetLinkBySlug", mock.Anything, "open").Return(open, nil)
+				responses.On("SubmitShared", mock.Anything, testFormID, uuid.Nil, answers).Return(response.FormResponse{}, form.ErrReadOnly)
+			},
+			expectError: form.ErrReadOnly,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			forms := mocks.NewFormStore(t)
+			responses := mocks.NewResponseStore(t)
+			tt.setMock(querier, responses)
+
+			ctx := context.Background()
+			if tt.userID != nil {
+				ctx = context.WithValue(ctx, jwt.UserContextKey, *tt.userID)
+			}
+			s := share.NewService(zaptest.NewLogger(t), querier, forms, responses)
+			_, err := s.Submit(ctx, tt.slug, tt.code, answers)
+			assert.ErrorIs(t, err, tt.expectError)
+		})
+	}
+}
+
+func TestService_Create(t *testing.T) {
+	testFormID := uuid.New()
+	testUserID := uuid.New()
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		input       share.LinkInput
+		setMock     func(querier *mocks.Querier, forms *mocks.FormStore)
+		expectError error
+	}{
+		{
+			name:  "Access code is hashed",
+			input: share.LinkInput{AccessCode: "secret", RequireSignIn: true},
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("Authorize", mock.Anything, testFormID, form.RoleEditor).Return(form.Form{ID: testFormID}, nil)
+				querier.On("CreateLink", mock.Anything, mock.MatchedBy(func(arg share.CreateLinkParams) bool {
+					return arg.FormID == testFormID && len(arg.Slug) >= 22 && arg.RequireSignIn &&
+						arg.AccessCodeHash.Valid && bcrypt.CompareHashAndPassword([]byte(arg.AccessCodeHash.String), []byte("secret")) == nil &&
+						arg.CreatedBy == pgtype.UUID{Bytes: testUserID, Valid: true}
+				})).Return(share.FormShareLink{ID: uuid.New()}, nil)
+			},
+		},
+		{
+			name:  "Expiry in the past",
+			input: share.LinkInput{ExpiresAt: &past},
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("Authorize", mock.Anything, testFormID, form.RoleEditor).Return(form.Form{ID: testFormID}, nil)
+			},
+			expectError: share.ErrInvalidLink,
+		},
+		{
+			name: "Viewers may not share",
+			setMock: func(querier *mocks.Querier, forms *mocks.FormStore) {
+				forms.On("Authorize", mock.Anything, testFormID, form.RoleEditor).Return(form.Form{}, form.ErrForbidden)
+			},
+			expectError: form.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := mocks.NewQuerier(t)
+			forms := mocks.NewFormStore(t)
+			tt.setMock(querier, forms)
+
+			ctx := context.WithValue(context.Background(), jwt.UserContextKey, testUserID)
+			s := share.NewService(zaptest.NewLogger(t), querier, forms, mocks.NewResponseStore(t))
+			_, err := s.Create(ctx, testFormID, tt.input)
+			assert.ErrorIs(t, err, tt.expectError)
+		})
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := share.NewRateLimiter(2, time.Hour)
+
+	allowed, _ := limiter.Allow("a")
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow("a")
+	assert.True(t, allowed)
+	allowed, wait := limiter.Allow("a")
+	assert.False(t, allowed)
+	assert.InDelta(t, 30*time.Minute, wait, float64(time.Second))
+
+	// every link has its own budget
+	allowed, _ = limiter.Allow("b")
+	assert.True(t, allowed)
+}
@@ -0,0 +1,161 @@
+// Package xlsx writes workbooks with a single sheet in the Office Open XML
+// format read by Excel and LibreOffice. Rows are written straight to the
+// underlying writer, nothing is kept in memory, so sheets can be of any size.
+// Strings are stored inline instead of in a shared string table for the same
+// reason.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MaxRows is the number of rows a sheet can hold.
+const MaxRows = 1 << 20
+
+var ErrTooManyRows = errors.New("xlsx: too many rows")
+
+// Cell is a single value of a row. The zero value is an empty cell.
+type Cell struct {
+	Text     string
+	Number   float64
+	IsNumber bool
+}
+
+func String(text string) Cell {
+	return Cell{Text: text}
+}
+
+func Number(number float64) Cell {
+	return Cell{Number: number, IsNumber: true}
+}
+
+// Writer streams one sheet. Call Close to finish the workbook; the output is
+// not a valid file before that.
+type Writer struct {
+	zip   *zip.Writer
+	sheet io.Writer
+	rows  int
+	buf   strings.Builder
+}
+
+// NewWriter writes the parts of the workbook that precede the sheet data.
+// sheetName must be a valid sheet name: at most 31 characters and none of
+// []:*?/\.
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	zw := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypes},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", workbookStart + escape(sheetName) + workbookEnd},
+		{"xl/_rels/workbook.xml.rels", workbookRels},
+	}
+	for _, part := range parts {
+		file, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sheet, sheetStart); err != nil {
+		return nil, err
+	}
+
+	return &Writer{zip: zw, sheet: sheet}, nil
+}
+
+// WriteRow appends a row to the sheet.
+func (w *Writer) WriteRow(cells []Cell) error {
+	if w.rows >= MaxRows {
+		return ErrTooManyRows
+	}
+	w.rows++
+	row := strconv.Itoa(w.rows)
+
+	w.buf.Reset()
+	w.buf.WriteString(`<row r="` + row + `">`)
+	for i, cell := range cells {
+		ref := ColumnName(i) + row
+		switch {
+		case cell.IsNumber:
+			w.buf.WriteString(`<c r="` + ref + `"><v>` + strconv.FormatFloat(cell.Number, 'g', -1, 64) + `</v></c>`)
+		case cell.Text != "":
+			w.buf.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">` + escape(cell.Text) + `</t></is></c>`)
+		}
+	}
+	w.buf.WriteString(`</row>`)
+
+	_, err := io.WriteString(w.sheet, w.buf.String())
+	return err
+}
+
+// Close ends the sheet and the workbook. It does not close the underlying
+// writer.
+func (w *Writer) Close() error {
+	if _, err := io.WriteString(w.sheet, sheetEnd); err != nil {
+		return err
+	}
+	return w.zip.Close()
+}
+
+// ColumnName returns the letters of the zero-based column index: A, B, ...,
+// Z, AA, AB and so on.
+func ColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// escape makes text safe for XML. Characters XML cannot hold, such as most
+// control characters, are replaced by U+FFFD.
+func escape(text string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+const contentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`</Types>`
+
+const rootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const workbookStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="`
+
+const workbookEnd = `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+const workbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`</Relationships>`
+
+const sheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+const sheetEnd = `</sheetData></worksheet>`
@@ -0,0 +1,83 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"awesomeProject/internal/xlsx"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		index  int
+		expect string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{51, "AZ"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expect, func(t *testing.T) {
+			assert.Equal(t, tt.expect, xlsx.ColumnName(tt.index))
+		})
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var out bytes.Buffer
+	w, err := xlsx.NewWriter(&out, "Responses")
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRow([]xlsx.Cell{xlsx.String("Name"), xlsx.String("Age")}))
+	require.NoError(t, w.WriteRow([]xlsx.Cell{xlsx.String("<Alice & Bob>"), xlsx.Number(42.5)}))
+	require.NoError(t, w.WriteRow([]xlsx.Cell{{}, xlsx.Number(7)}))
+	require.NoError(t, w.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	names := make(map[string]*zip.File)
+	for _, file := range archive.File {
+		names[file.Name] = file
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		require.Contains(t, names, name)
+	}
+
+	reader, err := names["xl/worksheets/sheet1.xml"].Open()
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	var sheet struct {
+		Rows []struct {
+			R     string `xml:"r,attr"`
+			Cells []struct {
+				R      string `xml:"r,attr"`
+				T      string `xml:"t,attr"`
+				V      string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	require.NoError(t, xml.Unmarshal(content, &sheet))
+	require.Len(t, sheet.Rows, 3)
+
+	assert.Equal(t, "2", sheet.Rows[1].R)
+	assert.Equal(t, "A2", sheet.Rows[1].Cells[0].R)
+	assert.Equal(t, "inlineStr", sheet.Rows[1].Cells[0].T)
+	assert.Equal(t, "<Alice & Bob>", sheet.Rows[1].Cells[0].Inline)
+	assert.Equal(t, "B2", sheet.Rows[1].Cells[1].R)
+	assert.Equal(t, "42.5", sheet.Rows[1].Cells[1].V)
+
+	// empty cells are left out
+	require.Len(t, sheet.Rows[2].Cells, 1)
+	assert.Equal(t, "B3", sheet.Rows[2].Cells[0].R)
+}
@@ -0,0 +1,273 @@
+// Package jwtverify validates access tokens of the backend in other services.
+// It fetches the public keys from the JWKS endpoint of the backend, usually
+// https://HOST/.well-known/jwks.json, and caches them as long as the
+// endpoint allows. Tokens signed with a key that is not cached yet make it
+// fetch the keys again, so keys can be rotated without restarting anyone.
+//
+//	verifier := jwtverify.New("https://forms.example.com/.well-known/jwks.json", nil)
+//	claims, err := verifier.Verify(ctx, r.Header.Get("Authorization"))
+package jwtverify
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownKey = errors.New("unknown signing key")
+	ErrFetchKeys  = errors.New("failed to fetch JWKS")
+)
+
+const (
+	// DefaultTTL is how long keys are cached when the JWKS response does not
+	// say.
+	DefaultTTL = 5 * time.Minute
+	// refetchInterval limits how often tokens with unknown kids make the
+	// verifier fetch the keys again, so forged tokens cannot flood the
+	// JWKS endpoint. It is also how long the verifier waits after a failed
+	// fetch before trying again.
+	refetchInterval = 30 * time.Second
+	// fetchTimeout bounds fetches of the default client.
+	fetchTimeout = 10 * time.Second
+)
+
+// Claims are the claims of access tokens.
+type Claims struct {
+	UserID uuid.UUID `json:"Id"`
+	Email  string    `json:"Email"`
+	jwt.RegisteredClaims
+}
+
+type publicKey struct {
+	algorithm string
+	key       any
+}
+
+type Verifier struct {
+	url    string
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]publicKey
+	expiresAt time.Time
+	lastMiss  time.Time
+	// retryAt is when to fetch again after the fetch that failed with
+	// fetchErr.
+	retryAt  time.Time
+	fetchErr error
+	// fetching is the fetch in flight, if any.
+	fetching *fetchCall
+}
+
+// fetchCall is one fetch of the keys that every caller of key needing them
+// waits for.
+type fetchCall struct {
+	done chan struct{}
+	err  error
+}
+
+// New returns a verifier for tokens signed with the keys published at
+// jwksURL. A nil client means one that gives up after ten seconds.
+func New(jwksURL string, client *http.Client) *Verifier {
+	if client == nil {
+		client = &http.Client{Timeout: fetchTimeout}
+	}
+	return &Verifier{
+		url:    jwksURL,
+		client: client,
+	}
+}
+
+// Verify checks the signature and the expiry of the token and returns its
+// claims. The token may start with "Bearer ". Only RS256 and EdDSA tokens are
+// accepted, HS256 keys are never published.
+func (v *Verifier) Verify(ctx context.Context, tokenString string) (*Claims, error) {
+	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
+
+	var claims Claims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := v.key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.algorithm {
+			return nil, fmt.Errorf("%w: %q is a %s key", jwt.ErrTokenSignatureInvalid, kid, key.algorithm)
+		}
+		return key.key, nil
+	}, jwt.WithValidMethods([]string{"RS256", "EdDSA"}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	return &claims, nil
+}
+
+// key returns the cached key with the kid, fetching the keys when the cache
+// expired or, at most once per refetchInterval, when the kid is unknown.
+// Concurrent callers share one fetch, which runs without holding the lock.
+// If fetching fails, the keys fetched before stay in use and the next fetch
+// waits for refetchInterval.
+func (v *Verifier) key(ctx context.Context, kid string) (publicKey, error) {
+	v.mu.Lock()
+	now := time.Now()
+	key, ok := v.keys[kid]
+	if ok && now.Before(v.expiresAt) {
+		v.mu.Unlock()
+		return key, nil
+	}
+
+	call := v.fetching
+	if call == nil {
+		switch {
+		case now.Before(v.retryAt):
+			err := v.fetchErr
+			v.mu.Unlock()
+			if ok {
+				return key, nil
+			}
+			return publicKey{}, err
+		case !ok && now.Before(v.expiresAt):
+			if now.Sub(v.lastMiss) < refetchInterval {
+				v.mu.Unlock()
+				return publicKey{}, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+			}
+			v.lastMiss = now
+		}
+		call = &fetchCall{done: make(chan struct{})}
+		v.fetching = call
+		// the fetch serves every waiting caller, not only this one
+		go v.refresh(context.WithoutCancel(ctx), call)
+	}
+	v.mu.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return publicKey{}, ctx.Err()
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	key, ok = v.keys[kid]
+	switch {
+	case ok:
+		return key, nil
+	case call.err != nil:
+		return publicKey{}, call.err
+	default:
+		return publicKey{}, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+}
+
+// refresh fetches the keys for the callers waiting for call.
+func (v *Verifier) refresh(ctx context.Context, call *fetchCall) {
+	keys, ttl, err := v.fetch(ctx)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	now := time.Now()
+	if err != nil {
+		v.retryAt = now.Add(refetchInterval)
+		v.fetchErr = err
+	} else {
+		v.keys = keys
+		v.expiresAt = now.Add(ttl)
+	}
+	v.fetching = nil
+	call.err = err
+	close(call.done)
+}
+
+type jwks struct {
+	Keys []struct {
+		KeyType   string `json:"kty"`
+		ID        string `json:"kid"`
+		Use       string `json:"use"`
+		Algorithm string `json:"alg"`
+		N         string `json:"n"`
+		E         string `json:"e"`
+		Curve     string `json:"crv"`
+		X         string `json:"x"`
+	} `json:"keys"`
+}
+
+func (v *Verifier) fetch(ctx context.Context) (map[string]publicKey, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrFetchKeys, err)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrFetchKeys, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("%w: %s", ErrFetchKeys, resp.Status)
+	}
+
+	var set jwks
+	err = json.NewDecoder(resp.Body).Decode(&set)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrFetchKeys, err)
+	}
+
+	keys := make(map[string]publicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch {
+		case k.KeyType == "RSA" && k.Algorithm == "RS256":
+			n, errN := base64.RawURLEncoding.DecodeString(k.N)
+			e, errE := base64.RawURLEncoding.DecodeString(k.E)
+			if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+				continue
+			}
+			keys[k.ID] = publicKey{algorithm: k.Algorithm, key: &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}}
+		case k.KeyType == "OKP" && k.Curve == "Ed25519" && k.Algorithm == "EdDSA":
+			x, err := base64.RawURLEncoding.DecodeString(k.X)
+			if err != nil || len(x) != ed25519.PublicKeySize {
+				continue
+			}
+			keys[k.ID] = publicKey{algorithm: k.Algorithm, key: ed25519.PublicKey(x)}
+		}
+	}
+
+	return keys, maxAge(resp.Header.Get("Cache-Control")), nil
+}
+
+// maxAge returns the max-age of a Cache-Control header, or DefaultTTL if it
+// has none.
+func maxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store", "no-cache":
+			return 0
+		case "max-age":
+			seconds, err := strconv.Atoi(value)
+			if err == nil && seconds >= 0 {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return DefaultTTL
+}
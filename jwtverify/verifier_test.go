@@ -0,0 +1,180 @@
+package jwtverify_test
+
+import (
+	"awesomeProject/internal/jwt"
+	"awesomeProject/jwtverify"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Now()
+
+	newToken := func(t *testing.T, alg string) string {
+		var keys jwt.KeySet
+		_, err := keys.Generate(alg, now, now)
+		require.NoError(t, err)
+		token, err := jwt.NewService(zaptest.NewLogger(t), time.Minute, nil, keys).New(ctx, userID, "alice@example.com", uuid.New())
+		require.NoError(t, err)
+		return token
+	}
+
+	tests := []struct {
+		name        string
+		alg         string
+		expectError bool
+	}{
+		{name: "RS256 token", alg: jwt.AlgRS256},
+		{name: "EdDSA token", alg: jwt.AlgEdDSA},
+		{name: "HS256 tokens cannot be verified by others", alg: jwt.AlgHS256, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys jwt.KeySet
+			_, err := keys.Generate(tt.alg, now, now)
+			require.NoError(t, err)
+			s := jwt.NewService(zaptest.NewLogger(t), time.Minute, nil, keys)
+			server := httptest.NewServer(http.HandlerFunc(jwt.NewHandler(zaptest.NewLogger(t), nil, s, nil, nil).JWKS))
+			defer server.Close()
+
+			token, err := s.New(ctx, userID, "alice@example.com", uuid.New())
+			require.NoError(t, err)
+			v := jwtverify.New(server.URL, server.Client())
+
+			claims, err := v.Verify(ctx, "Bearer "+token)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, claims.UserID)
+			assert.Equal(t, "alice@example.com", claims.Email)
+
+			// tokens signed with someone else's key of the same kind
+			_, err = v.Verify(ctx, newToken(t, tt.alg))
+			assert.ErrorIs(t, err, jwtverify.ErrUnknownKey)
+		})
+	}
+}
+
+func TestVerifier_KeyRotation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	var keys jwt.KeySet
+	_, err := keys.Generate(jwt.AlgEdDSA, now.Add(-time.Hour), now.Add(-time.Hour))
+	require.NoError(t, err)
+
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(keys.JWKS())
+	}))
+	defer server.Close()
+	v := jwtverify.New(server.URL, server.Client())
+
+	oldToken, err := jwt.NewService(zaptest.NewLogger(t), time.Minute, nil, keys).New(ctx, uuid.New(), "alice@example.com", uuid.New())
+	require.NoError(t, err)
+	_, err = v.Verify(ctx, oldToken)
+	require.NoError(t, err)
+	_, err = v.Verify(ctx, oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), fetches.Load(), "keys are cached")
+
+	// a key the verifier has not seen yet makes it fetch the keys again
+	_, err = keys.Generate(jwt.AlgRS256, now, now)
+	require.NoError(t, err)
+	newToken, err := jwt.NewService(zaptest.NewLogger(t), time.Minute, nil, keys).New(ctx, uuid.New(), "bob@example.com", uuid.New())
+	require.NoError(t, err)
+	claims, err := v.Verify(ctx, newToken)
+	require.NoError(t, err)
+	assert.Equal(t, "bob@example.com", claims.Email)
+	assert.Equal(t, int32(2), fetches.Load())
+
+	// but not for every unknown kid
+	var other jwt.KeySet
+	_, err = other.Generate(jwt.AlgRS256, now, now)
+	require.NoError(t, err)
+	forged, err := jwt.NewService(zaptest.NewLogger(t), time.Minute, nil, other).New(ctx, uuid.New(), "mallory@example.com", uuid.New())
+	require.NoError(t, err)
+	_, err = v.Verify(ctx, forged)
+	assert.ErrorIs(t, err, jwtverify.ErrUnknownKey)
+	assert.Equal(t, int32(2), fetches.Load())
+}
+
+func TestVerifier_SharesFetches(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	var keys jwt.KeySet
+	_, err := keys.Generate(jwt.AlgEdDSA, now, now)
+	require.NoError(t, err)
+	token, err := jwt.NewService(zaptest.NewLogger(t), time.Minute, nil, keys).New(ctx, uuid.New(), "alice@example.com", uuid.New())
+	require.NoError(t, err)
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(keys.JWKS())
+	}))
+	defer server.Close()
+	v := jwtverify.New(server.URL, server.Client())
+
+	errs := make(chan error, 10)
+	for range 10 {
+		go func() {
+			_, err := v.Verify(ctx, token)
+			errs <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	for range 10 {
+		assert.NoError(t, <-errs)
+	}
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+func TestVerifier_FailedFetch(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	var keys jwt.KeySet
+	_, err := keys.Generate(jwt.AlgEdDSA, now, now)
+	require.NoError(t, err)
+	token, err := jwt.NewService(zaptest.NewLogger(t), time.Minute, nil, keys).New(ctx, uuid.New(), "alice@example.com", uuid.New())
+	require.NoError(t, err)
+
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	v := jwtverify.New(server.URL, server.Client())
+
+	_, err = v.Verify(ctx, token)
+	assert.ErrorIs(t, err, jwtverify.ErrFetchKeys)
+	// the endpoint is not asked again right away
+	_, err = v.Verify(ctx, token)
+	assert.ErrorIs(t, err, jwtverify.ErrFetchKeys)
+	assert.Equal(t, int32(1), fetches.Load())
+}